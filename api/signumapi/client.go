@@ -17,31 +17,46 @@ const DEFAULT_DEADLINE = 1440
 type RequestType string
 
 const (
-	RT_SEND_MONEY                        RequestType = "sendMoney"          // recipient + amountNQT
-	RT_SEND_MONEY_MULTI                  RequestType = "sendMoneyMulti"     // recipients = <numid1>:<amount1>;<numid2>:<amount2>;<numidN>:<amountN>
-	RT_SEND_MONEY_MULTI_SAME             RequestType = "sendMoneyMultiSame" // recipients = <numid1>;<numid2>;<numidN> + amountNQT
-	RT_SEND_MESSAGE                      RequestType = "sendMessage"
-	RT_READ_MESSAGE                      RequestType = "readMessage"
-	RT_SUGGEST_FEE                       RequestType = "suggestFee"
-	RT_GET_ACCOUNT                       RequestType = "getAccount"
-	RT_GET_AT_DETAILS                    RequestType = "getATDetails"
-	RT_GET_TRANSACTION                   RequestType = "getTransaction"
-	RT_GET_BLOCK                         RequestType = "getBlock"
-	RT_GET_ACCOUNT_ID                    RequestType = "getAccountId"
-	RT_GET_ACCOUNT_TRANSACTIONS          RequestType = "getAccountTransactions"
-	RT_GET_UNCONFIRMED_TRANSACTIONS      RequestType = "getUnconfirmedTransactions"
-	RT_GET_MINING_INFO                   RequestType = "getMiningInfo"
-	RT_GET_BLOCKCHAIN_STATUS             RequestType = "getBlockchainStatus"
-	RT_GET_REWARD_RECIPIENT              RequestType = "getRewardRecipient"
-	RT_SET_REWARD_RECIPIENT              RequestType = "setRewardRecipient"
-	RT_ADD_COMMITMENT                    RequestType = "addCommitment"
-	RT_REMOVE_COMMITMENT                 RequestType = "removeCommitment"
-	RT_SET_ACCOUNT_INFO                  RequestType = "setAccountInfo"
-	RT_GENERATE_SEND_TRANSACTION_QR_CODE RequestType = "generateSendTransactionQRCode"
-	RT_CREATE_AT_PROGRAM                 RequestType = "createATProgram"
-	RT_DECRYPT_FROM                      RequestType = "decryptFrom"
-	RT_GET_INDIRECT_INCOMING             RequestType = "getIndirectIncoming"
-	RT_GET_ASSET                         RequestType = "getAsset"
+	RT_SEND_MONEY                         RequestType = "sendMoney"          // recipient + amountNQT
+	RT_SEND_MONEY_MULTI                   RequestType = "sendMoneyMulti"     // recipients = <numid1>:<amount1>;<numid2>:<amount2>;<numidN>:<amountN>
+	RT_SEND_MONEY_MULTI_SAME              RequestType = "sendMoneyMultiSame" // recipients = <numid1>;<numid2>;<numidN> + amountNQT
+	RT_SEND_MESSAGE                       RequestType = "sendMessage"
+	RT_READ_MESSAGE                       RequestType = "readMessage"
+	RT_SUGGEST_FEE                        RequestType = "suggestFee"
+	RT_GET_ACCOUNT                        RequestType = "getAccount"
+	RT_GET_AT_DETAILS                     RequestType = "getATDetails"
+	RT_GET_TRANSACTION                    RequestType = "getTransaction"
+	RT_GET_BLOCK                          RequestType = "getBlock"
+	RT_GET_ACCOUNT_ID                     RequestType = "getAccountId"
+	RT_GET_ACCOUNT_TRANSACTIONS           RequestType = "getAccountTransactions"
+	RT_GET_UNCONFIRMED_TRANSACTIONS       RequestType = "getUnconfirmedTransactions"
+	RT_GET_MINING_INFO                    RequestType = "getMiningInfo"
+	RT_GET_BLOCKCHAIN_STATUS              RequestType = "getBlockchainStatus"
+	RT_GET_REWARD_RECIPIENT               RequestType = "getRewardRecipient"
+	RT_GET_ACCOUNTS_WITH_REWARD_RECIPIENT RequestType = "getAccountsWithRewardRecipient"
+	RT_SET_REWARD_RECIPIENT               RequestType = "setRewardRecipient"
+	RT_ADD_COMMITMENT                     RequestType = "addCommitment"
+	RT_REMOVE_COMMITMENT                  RequestType = "removeCommitment"
+	RT_SET_ACCOUNT_INFO                   RequestType = "setAccountInfo"
+	RT_GENERATE_SEND_TRANSACTION_QR_CODE  RequestType = "generateSendTransactionQRCode"
+	RT_CREATE_AT_PROGRAM                  RequestType = "createATProgram"
+	RT_DECRYPT_FROM                       RequestType = "decryptFrom"
+	RT_GET_INDIRECT_INCOMING              RequestType = "getIndirectIncoming"
+	RT_GET_ASSET                          RequestType = "getAsset"
+	RT_GET_ASSET_ACCOUNTS                 RequestType = "getAssetAccounts"
+	RT_GET_ASSETS_BY_NAME                 RequestType = "getAssetsByName"
+	RT_GET_ALL_TRADES                     RequestType = "getAllTrades"
+	RT_GET_TRADES                         RequestType = "getTrades"
+	RT_GET_ALIAS                          RequestType = "getAlias"
+	RT_GET_ALIASES                        RequestType = "getAliases"
+	RT_GET_ACCOUNT_SUBSCRIPTIONS          RequestType = "getAccountSubscriptions"
+	RT_GET_SUBSCRIPTIONS_TO_ACCOUNT       RequestType = "getSubscriptionsToAccount"
+	RT_GET_ACCOUNT_ESCROW_TRANSACTIONS    RequestType = "getAccountEscrowTransactions"
+	RT_GET_DGS_GOOD                       RequestType = "getDGSGood"
+	RT_GET_DGS_GOODS                      RequestType = "getDGSGoods"
+	RT_GET_DGS_PURCHASE                   RequestType = "getDGSPurchase"
+	RT_GET_DGS_PURCHASES                  RequestType = "getDGSPurchases"
+	RT_GET_DGS_PENDING_PURCHASES          RequestType = "getDGSPendingPurchases"
 )
 
 type SignumApiClient struct {
@@ -49,6 +64,7 @@ type SignumApiClient struct {
 	localAccountCache          AccountCache
 	localTransactionsCache     TransactionsCache
 	localBlocksCache           BlocksCache
+	localAssetsCache           AssetsCache
 	localSuggestFeeCache       SuggestFeeCache
 	localBigWalletNamesCache   BigWalletNamesCache
 	localBlockchainStatusCache BlockchainStatusCache
@@ -95,9 +111,10 @@ func NewSignumApiClient(logger abstractapi.LoggerI, wg *sync.WaitGroup, shutdown
 	signumApiClient := &SignumApiClient{
 		apiClientsPool:           apiClientsPool{clients: apiClients},
 		localAccountCache:        AccountCache{sync.RWMutex{}, map[string]*Account{}},
-		localTransactionsCache:   TransactionsCache{sync.RWMutex{}, map[string]map[TransactionType]map[TransactionSubType]*AccountTransactions{}},
+		localTransactionsCache:   TransactionsCache{sync.RWMutex{}, map[string]map[TransactionType]map[TransactionSubType]map[uint64]*AccountTransactions{}},
 		localBlocksCache:         BlocksCache{sync.RWMutex{}, map[string]*AccountBlocks{}},
-		localBigWalletNamesCache: BigWalletNamesCache{sync.RWMutex{}, map[string]string{}},
+		localAssetsCache:         AssetsCache{sync.RWMutex{}, map[string]*Asset{}},
+		localBigWalletNamesCache: BigWalletNamesCache{sync.RWMutex{}, map[string]string{}, DefaultBigWallets},
 		shutdownChannel:          shutdownChannel,
 		config:                   config,
 	}
@@ -7,22 +7,28 @@ import (
 )
 
 type Account struct {
-	Name                string `json:"name"`
-	Account             string `json:"account"`
-	AccountRS           string `json:"accountRS"`
-	TotalBalanceNQT     uint64 `json:"balanceNQT,string"`
-	AvailableBalanceNQT uint64 `json:"unconfirmedBalanceNQT,string"`
-	CommittedBalanceNQT uint64 `json:"committedBalanceNQT,string"`
-	ErrorDescription    string `json:"errorDescription"`
-	lastUpdateTime      time.Time
+	Name                string         `json:"name"`
+	Account             string         `json:"account"`
+	AccountRS           string         `json:"accountRS"`
+	TotalBalanceNQT     uint64         `json:"balanceNQT,string"`
+	AvailableBalanceNQT uint64         `json:"unconfirmedBalanceNQT,string"`
+	CommittedBalanceNQT uint64         `json:"committedBalanceNQT,string"`
+	AssetBalances       []AssetBalance `json:"assetBalances"`
+	// effective balance leasing
+	CurrentLessee            string   `json:"currentLessee"`
+	CurrentLesseeRS          string   `json:"currentLesseeRS"`
+	CurrentLeasingHeightFrom uint64   `json:"currentLeasingHeightFrom"`
+	CurrentLeasingHeightTo   uint64   `json:"currentLeasingHeightTo"`
+	NextLessee               string   `json:"nextLessee"`
+	NextLesseeRS             string   `json:"nextLesseeRS"`
+	Lessors                  []string `json:"lessors"`
+	LessorsRS                []string `json:"lessorsRS"`
+	ErrorDescription         string   `json:"errorDescription"`
+	lastUpdateTime           time.Time
 	//ForgedBalanceNQT      uint64 `json:"forgedBalanceNQT,string"`
 	//EffectiveBalanceNXT   uint64 `json:"effectiveBalanceNXT,string"`
 	//GuaranteedBalanceNQT  uint64 `json:"guaranteedBalanceNQT,string"`
 	//AccountRSExtended     string `json:"accountRSExtended"`
-	//AssetBalances         []struct {
-	//	BalanceQNT uint64 `json:"balanceQNT,string"`
-	//	Asset      uint64 `json:"asset,string"`
-	//} `json:"assetBalances"`
 	//UnconfirmedAssetBalances []struct {
 	//	UnconfirmedBalanceQNT uint64 `json:"unconfirmedBalanceQNT,string"`
 	//	Asset                 uint64 `json:"asset,string"`
@@ -30,6 +36,11 @@ type Account struct {
 	//PublicKey string `json:"publicKey"`
 }
 
+type AssetBalance struct {
+	Asset      string `json:"asset"`
+	BalanceQNT uint64 `json:"balanceQNT,string"`
+}
+
 func (a *Account) GetError() string {
 	return a.ErrorDescription
 }
@@ -13,9 +13,18 @@ type Block struct {
 	Timestamp        int64  `json:"timestamp"`
 	Height           uint64 `json:"height"`
 	BlockReward      string `json:"blockReward"`
+	Generator        string `json:"generator"`
+	GeneratorRS      string `json:"generatorRS"`
+	TotalFeeNQT      uint64 `json:"totalFeeNQT,string"`
 	ErrorDescription string `json:"errorDescription"`
 }
 
+// GetRewardNQT returns the block reward including fees
+func (b *Block) GetRewardNQT() uint64 {
+	blockReward, _ := strconv.ParseFloat(b.BlockReward, 64)
+	return uint64(blockReward*1e8) + b.TotalFeeNQT
+}
+
 func (b *Block) GetError() string {
 	return b.ErrorDescription
 }
@@ -28,6 +37,7 @@ type AccountBlocks struct {
 	Blocks           []Block `json:"blocks"`
 	ErrorDescription string  `json:"errorDescription"`
 	lastUpdateTime   time.Time
+	HasNextPage      bool `json:"-"`
 }
 
 func (ab *AccountBlocks) GetError() string {
@@ -40,7 +50,7 @@ func (ab *AccountBlocks) ClearError() {
 
 type BlocksCache struct {
 	sync.RWMutex
-	cache map[string]*AccountBlocks
+	cache map[string]*AccountBlocks // all blocks of the account, they are paged on read
 }
 
 func (c *SignumApiClient) readAccountBlocksFromCache(account string) *AccountBlocks {
@@ -53,43 +63,80 @@ func (c *SignumApiClient) readAccountBlocksFromCache(account string) *AccountBlo
 	return nil
 }
 
+// storeAccountBlocksToCache also drops expired accounts, so the cache keeps only recently viewed ones
 func (c *SignumApiClient) storeAccountBlocksToCache(accountS string, accountBlocks *AccountBlocks) {
 	c.localBlocksCache.Lock()
+	for account, cachedBlocks := range c.localBlocksCache.cache {
+		if time.Since(cachedBlocks.lastUpdateTime) >= c.config.CacheTtl {
+			delete(c.localBlocksCache.cache, account)
+		}
+	}
 	accountBlocks.lastUpdateTime = time.Now()
 	c.localBlocksCache.cache[accountS] = accountBlocks
 	c.localBlocksCache.Unlock()
 }
 
-func (c *SignumApiClient) GetAccountBlocks(logger abstractapi.LoggerI, account string) (*AccountBlocks, error) {
+// getAccountBlocksPage slices the page out of all blocks of the account
+func (c *SignumApiClient) getAccountBlocksPage(accountBlocks *AccountBlocks, page uint64) *AccountBlocks {
+	var total = uint64(len(accountBlocks.Blocks))
+	var firstIndex = page * c.GetPageSize()
+	if firstIndex >= total {
+		return &AccountBlocks{}
+	}
+	var lastIndex = firstIndex + c.GetPageSize()
+	if lastIndex > total {
+		lastIndex = total
+	}
+	return &AccountBlocks{
+		Blocks:      accountBlocks.Blocks[firstIndex:lastIndex],
+		HasNextPage: lastIndex < total,
+	}
+}
+
+// GetAccountBlocks returns the page of the account blocks, the node ignores lastIndex of getAccountBlocks,
+// so all blocks are requested at once and paged here
+func (c *SignumApiClient) GetAccountBlocks(logger abstractapi.LoggerI, account string, page uint64) (*AccountBlocks, error) {
 	accountBlocks := &AccountBlocks{}
 	_, err := c.doJsonReq(logger, "GET", "/burst",
 		map[string]string{
 			"account":     account,
 			"requestType": "getAccountBlocks",
-			"firstIndex":  "0",
-			"lastIndex":   strconv.FormatUint(c.config.LastIndex, 10), // it doesn't work
 		},
 		nil,
 		accountBlocks)
-	if err == nil {
-		if len(accountBlocks.Blocks) > 10 {
-			accountBlocks.Blocks = accountBlocks.Blocks[:10]
-		}
-		c.storeAccountBlocksToCache(account, accountBlocks)
+	if err != nil {
+		return accountBlocks, err
 	}
+	c.storeAccountBlocksToCache(account, accountBlocks)
+	return c.getAccountBlocksPage(accountBlocks, page), nil
+}
+
+// GetAccountBlocksRange returns blocks forged not earlier than timestamp (in chain time), newest first
+func (c *SignumApiClient) GetAccountBlocksRange(logger abstractapi.LoggerI, account string, timestamp int64, firstIndex, lastIndex uint64) (*AccountBlocks, error) {
+	accountBlocks := &AccountBlocks{}
+	_, err := c.doJsonReq(logger, "GET", "/burst",
+		map[string]string{
+			"account":     account,
+			"requestType": "getAccountBlocks",
+			"timestamp":   strconv.FormatInt(timestamp, 10),
+			"firstIndex":  strconv.FormatUint(firstIndex, 10),
+			"lastIndex":   strconv.FormatUint(lastIndex, 10),
+		},
+		nil,
+		accountBlocks)
 	return accountBlocks, err
 }
 
-func (c *SignumApiClient) GetCachedAccountBlocks(logger abstractapi.LoggerI, account string) (*AccountBlocks, error) {
+func (c *SignumApiClient) GetCachedAccountBlocks(logger abstractapi.LoggerI, account string, page uint64) (*AccountBlocks, error) {
 	accountBlocks := c.readAccountBlocksFromCache(account)
 	if accountBlocks != nil {
-		return accountBlocks, nil
+		return c.getAccountBlocksPage(accountBlocks, page), nil
 	}
-	return c.GetAccountBlocks(logger, account)
+	return c.GetAccountBlocks(logger, account, page)
 }
 
 func (c *SignumApiClient) GetLastAccountBlock(logger abstractapi.LoggerI, account string) *Block {
-	accountBlocks, err := c.GetAccountBlocks(logger, account)
+	accountBlocks, err := c.GetAccountBlocks(logger, account, 0)
 	if err == nil && len(accountBlocks.Blocks) > 0 {
 		return &accountBlocks.Blocks[0]
 	}
@@ -5,7 +5,8 @@ import (
 	"sync"
 )
 
-var listOfBigWallets = map[string]string{
+// DefaultBigWallets are known pools and exchanges, the list could be replaced at runtime by SetBigWallets
+var DefaultBigWallets = map[string]string{
 	"13729039893708541600": "signa.foxypool.io",
 	"15587859947385731145": "POOL.SIGNUMCOIN.ROᶜˡᵒᵘᵈᶠˡᵃʳᵉ ᴾʳᵒᵗᵉᶜᵗᵉᵈ",
 	"357805355326612814":   "VoipLanParty.com POOL",
@@ -23,11 +24,19 @@ var listOfBigWallets = map[string]string{
 
 type BigWalletNamesCache struct {
 	sync.RWMutex
-	cache map[string]string
+	cache   map[string]string
+	wallets map[string]string // account -> default name
 }
 
 func (c *SignumApiClient) preloadNamesForBigWallets(logger abstractapi.LoggerI) {
-	for account, defaultName := range listOfBigWallets {
+	c.localBigWalletNamesCache.RLock()
+	var wallets = make(map[string]string, len(c.localBigWalletNamesCache.wallets))
+	for account, defaultName := range c.localBigWalletNamesCache.wallets {
+		wallets[account] = defaultName
+	}
+	c.localBigWalletNamesCache.RUnlock()
+
+	for account, defaultName := range wallets {
 		signumAccount, _ := c.GetAccount(logger, account)
 		c.localBigWalletNamesCache.Lock()
 		if signumAccount != nil {
@@ -58,3 +67,22 @@ func (c *SignumApiClient) GetCachedAccountName(logger abstractapi.LoggerI, accou
 	}
 	return ""
 }
+
+// SetBigWallets replaces the list of big wallets and reloads their names
+func (c *SignumApiClient) SetBigWallets(logger abstractapi.LoggerI, wallets map[string]string) {
+	c.localBigWalletNamesCache.Lock()
+	c.localBigWalletNamesCache.wallets = wallets
+	c.localBigWalletNamesCache.cache = make(map[string]string, len(wallets))
+	c.localBigWalletNamesCache.Unlock()
+	if c.config.PreloadNamesForBigWallets {
+		c.preloadNamesForBigWallets(logger)
+	}
+}
+
+// IsBigWallet returns true for well-known pools and exchanges
+func (c *SignumApiClient) IsBigWallet(account string) bool {
+	c.localBigWalletNamesCache.RLock()
+	_, ok := c.localBigWalletNamesCache.wallets[account]
+	c.localBigWalletNamesCache.RUnlock()
+	return ok
+}
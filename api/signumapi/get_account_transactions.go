@@ -23,6 +23,9 @@ const (
 
 type TransactionSubType int
 
+// TST_ALL_SUBTYPES requests transactions of the type with any subtype
+const TST_ALL_SUBTYPES TransactionSubType = -1
+
 // Payment
 const (
 	TST_ORDINARY_PAYMENT       TransactionSubType = 0
@@ -42,6 +45,37 @@ const (
 // Messaging
 const (
 	TST_ARBITRARY_MESSAGE TransactionSubType = 0
+	TST_ALIAS_ASSIGNMENT                     = 1
+	TST_ACCOUNT_INFO                         = 5
+	TST_ALIAS_SELL                           = 6
+	TST_ALIAS_BUY                            = 7
+)
+
+// Account control
+const (
+	TST_EFFECTIVE_BALANCE_LEASING TransactionSubType = 0
+)
+
+// Digital goods
+const (
+	TST_DGS_LISTING         TransactionSubType = 0
+	TST_DGS_DELISTING                          = 1
+	TST_DGS_PRICE_CHANGE                       = 2
+	TST_DGS_QUANTITY_CHANGE                    = 3
+	TST_DGS_PURCHASE                           = 4
+	TST_DGS_DELIVERY                           = 5
+	TST_DGS_FEEDBACK                           = 6
+	TST_DGS_REFUND                             = 7
+)
+
+// Advanced payment
+const (
+	TST_ESCROW_CREATION        TransactionSubType = 0
+	TST_ESCROW_SIGN                               = 1
+	TST_ESCROW_RESULT                             = 2
+	TST_SUBSCRIPTION_SUBSCRIBE                    = 3
+	TST_SUBSCRIPTION_CANCEL                       = 4
+	TST_SUBSCRIPTION_PAYMENT                      = 5
 )
 
 // SmartContract
@@ -51,13 +85,20 @@ const (
 
 // Tokenization
 const (
-	TST_TOKENIZATION_DISTRIBUTION_TO_HOLDER = 8
+	TST_ASSET_ISSUANCE                      TransactionSubType = 0
+	TST_ASSET_TRANSFER                                         = 1
+	TST_ASK_ORDER_PLACEMENT                                    = 2
+	TST_BID_ORDER_PLACEMENT                                    = 3
+	TST_ASK_ORDER_CANCELLATION                                 = 4
+	TST_BID_ORDER_CANCELLATION                                 = 5
+	TST_TOKENIZATION_DISTRIBUTION_TO_HOLDER                    = 8
 )
 
 type AccountTransactions struct {
 	Transactions     []Transaction `json:"transactions"`
 	ErrorDescription string        `json:"errorDescription"`
 	LastUpdateTime   time.Time     `json:"-"`
+	HasNextPage      bool          `json:"-"`
 	// RequestProcessingTime uint64    `json:"requestProcessingTime"`
 }
 
@@ -69,25 +110,41 @@ func (at *AccountTransactions) ClearError() {
 	at.ErrorDescription = ""
 }
 
-func (c *SignumApiClient) getAccountTransactionsByType(logger abstractapi.LoggerI, account string, transactionType TransactionType, transactionSubType TransactionSubType) (*AccountTransactions, error) {
+// getPageIndexes returns the first and the last index of the page, the last one is requested in addition to know if there is the next page
+func (c *SignumApiClient) getPageIndexes(page uint64) (string, string) {
+	firstIndex := page * c.GetPageSize()
+	return strconv.FormatUint(firstIndex, 10), strconv.FormatUint(firstIndex+c.GetPageSize(), 10)
+}
+
+// GetPageSize returns the number of history entries on one page
+func (c *SignumApiClient) GetPageSize() uint64 {
+	return c.config.LastIndex + 1
+}
+
+func (c *SignumApiClient) getAccountTransactionsByType(logger abstractapi.LoggerI, account string, transactionType TransactionType, transactionSubType TransactionSubType, page uint64) (*AccountTransactions, error) {
 	accountTransactions := &AccountTransactions{}
 
+	firstIndex, lastIndex := c.getPageIndexes(page)
 	urlParams := map[string]string{
 		"account":         account,
 		"requestType":     string(RT_GET_ACCOUNT_TRANSACTIONS),
 		"includeIndirect": "true",
 		"type":            strconv.Itoa(int(transactionType)),
-		"firstIndex":      "0",
-		"lastIndex":       strconv.FormatUint(c.config.LastIndex, 10),
+		"firstIndex":      firstIndex,
+		"lastIndex":       lastIndex,
 	}
 
-	if transactionSubType != TST_ALL_TYPES_PAYMENT && transactionSubType != TST_ALL_TYPES_MINING {
+	if transactionSubType != TST_ALL_SUBTYPES && transactionSubType != TST_ALL_TYPES_PAYMENT && transactionSubType != TST_ALL_TYPES_MINING {
 		urlParams["subtype"] = fmt.Sprint(transactionSubType)
 	}
 
 	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, accountTransactions)
 	if err == nil {
-		c.storeAccountTransactionsToCache(account, transactionType, transactionSubType, accountTransactions)
+		if uint64(len(accountTransactions.Transactions)) > c.GetPageSize() {
+			accountTransactions.Transactions = accountTransactions.Transactions[:c.GetPageSize()]
+			accountTransactions.HasNextPage = true
+		}
+		c.storeAccountTransactionsToCache(account, transactionType, transactionSubType, page, accountTransactions)
 	}
 	return accountTransactions, err
 }
@@ -107,32 +164,49 @@ func (c *SignumApiClient) GetAccountTransactions(logger abstractapi.LoggerI, acc
 	return accountTransactions, err
 }
 
+// GetAccountTransactionsRange returns transactions of all types not older than timestamp (in chain time), newest first
+func (c *SignumApiClient) GetAccountTransactionsRange(logger abstractapi.LoggerI, account string, timestamp int64, firstIndex, lastIndex uint64) (*AccountTransactions, error) {
+	accountTransactions := &AccountTransactions{}
+
+	urlParams := map[string]string{
+		"account":         account,
+		"requestType":     string(RT_GET_ACCOUNT_TRANSACTIONS),
+		"includeIndirect": "true",
+		"timestamp":       strconv.FormatInt(timestamp, 10),
+		"firstIndex":      strconv.FormatUint(firstIndex, 10),
+		"lastIndex":       strconv.FormatUint(lastIndex, 10),
+	}
+
+	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, accountTransactions)
+	return accountTransactions, err
+}
+
 func (c *SignumApiClient) GetAccountOrdinaryPaymentTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getAccountTransactionsByType(logger, account, TT_PAYMENT, TST_ORDINARY_PAYMENT)
+	return c.getAccountTransactionsByType(logger, account, TT_PAYMENT, TST_ORDINARY_PAYMENT, 0)
 }
 
 func (c *SignumApiClient) GetAccountMultiOutTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getAccountTransactionsByType(logger, account, TT_PAYMENT, TST_MULTI_OUT_PAYMENT)
+	return c.getAccountTransactionsByType(logger, account, TT_PAYMENT, TST_MULTI_OUT_PAYMENT, 0)
 }
 
 func (c *SignumApiClient) GetAccountMultiOutSameTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getAccountTransactionsByType(logger, account, TT_PAYMENT, TST_MULTI_OUT_SAME_PAYMENT)
+	return c.getAccountTransactionsByType(logger, account, TT_PAYMENT, TST_MULTI_OUT_SAME_PAYMENT, 0)
 }
 
 func (c *SignumApiClient) GetAccountPaymentTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getAccountTransactionsByType(logger, account, TT_PAYMENT, TST_ALL_TYPES_PAYMENT)
+	return c.getAccountTransactionsByType(logger, account, TT_PAYMENT, TST_ALL_TYPES_PAYMENT, 0)
 }
 
 func (c *SignumApiClient) GetAccountMiningTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getAccountTransactionsByType(logger, account, TT_BURST_MINING, TST_ALL_TYPES_MINING)
+	return c.getAccountTransactionsByType(logger, account, TT_BURST_MINING, TST_ALL_TYPES_MINING, 0)
 }
 
 func (c *SignumApiClient) GetAccountMessageTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getAccountTransactionsByType(logger, account, TT_MESSAGING, TST_ARBITRARY_MESSAGE)
+	return c.getAccountTransactionsByType(logger, account, TT_MESSAGING, TST_ARBITRARY_MESSAGE, 0)
 }
 
 func (c *SignumApiClient) GetAccountATPaymentTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getAccountTransactionsByType(logger, account, TT_AUTOMATED_TRANSACTIONS, TST_AT_PAYMENT)
+	return c.getAccountTransactionsByType(logger, account, TT_AUTOMATED_TRANSACTIONS, TST_AT_PAYMENT, 0)
 }
 
 func (c *SignumApiClient) GetLastAccountPaymentTransaction(logger abstractapi.LoggerI, account string) *Transaction {
@@ -152,7 +226,7 @@ func (c *SignumApiClient) GetLastAccountMiningTransaction(logger abstractapi.Log
 }
 
 func (c *SignumApiClient) GetLastAccountAddCommitmentTransaction(logger abstractapi.LoggerI, account string) *Transaction {
-	userTransactions, err := c.getAccountTransactionsByType(logger, account, TT_BURST_MINING, TST_ADD_COMMITMENT)
+	userTransactions, err := c.getAccountTransactionsByType(logger, account, TT_BURST_MINING, TST_ADD_COMMITMENT, 0)
 	if err == nil && userTransactions != nil && len(userTransactions.Transactions) > 0 {
 		return &userTransactions.Transactions[0]
 	}
@@ -174,3 +248,8 @@ func (c *SignumApiClient) GetLastAccountATPaymentTransaction(logger abstractapi.
 	}
 	return nil
 }
+
+// GetAccountMessagingTransactions returns the page of messaging transactions of all subtypes, aliases are among them
+func (c *SignumApiClient) GetAccountMessagingTransactions(logger abstractapi.LoggerI, account string, page uint64) (*AccountTransactions, error) {
+	return c.getAccountTransactionsByType(logger, account, TT_MESSAGING, TST_ALL_SUBTYPES, page)
+}
@@ -9,17 +9,17 @@ import (
 
 type TransactionsCache struct {
 	sync.RWMutex
-	cache map[string]map[TransactionType]map[TransactionSubType]*AccountTransactions
+	cache map[string]map[TransactionType]map[TransactionSubType]map[uint64]*AccountTransactions // the last key is the page
 }
 
-func (c *SignumApiClient) readAccountTransactionsFromCache(account string, transactionType TransactionType, transactionSubType TransactionSubType) *AccountTransactions {
+func (c *SignumApiClient) readAccountTransactionsFromCache(account string, transactionType TransactionType, transactionSubType TransactionSubType, page uint64) *AccountTransactions {
 	var transactions *AccountTransactions
 	c.localTransactionsCache.RLock()
 	transactionTypeCache := c.localTransactionsCache.cache[account]
 	if transactionTypeCache != nil {
 		transactionSubTypeCache := transactionTypeCache[transactionType]
-		if transactionSubTypeCache != nil {
-			transactions = transactionSubTypeCache[transactionSubType]
+		if transactionSubTypeCache != nil && transactionSubTypeCache[transactionSubType] != nil {
+			transactions = transactionSubTypeCache[transactionSubType][page]
 		}
 	}
 	c.localTransactionsCache.RUnlock()
@@ -29,61 +29,90 @@ func (c *SignumApiClient) readAccountTransactionsFromCache(account string, trans
 	return nil
 }
 
-func (c *SignumApiClient) storeAccountTransactionsToCache(accountS string, transactionType TransactionType, transactionSubType TransactionSubType, transactions *AccountTransactions) {
+// storeAccountTransactionsToCache also drops expired pages, so the cache keeps only recently viewed ones
+func (c *SignumApiClient) storeAccountTransactionsToCache(accountS string, transactionType TransactionType, transactionSubType TransactionSubType, page uint64, transactions *AccountTransactions) {
 	c.localTransactionsCache.Lock()
+	c.pruneAccountTransactionsCache()
 	transactions.LastUpdateTime = time.Now()
 	if c.localTransactionsCache.cache[accountS] == nil {
-		c.localTransactionsCache.cache[accountS] = make(map[TransactionType]map[TransactionSubType]*AccountTransactions)
+		c.localTransactionsCache.cache[accountS] = make(map[TransactionType]map[TransactionSubType]map[uint64]*AccountTransactions)
 	}
 	if c.localTransactionsCache.cache[accountS][transactionType] == nil {
-		c.localTransactionsCache.cache[accountS][transactionType] = make(map[TransactionSubType]*AccountTransactions)
+		c.localTransactionsCache.cache[accountS][transactionType] = make(map[TransactionSubType]map[uint64]*AccountTransactions)
 	}
-	c.localTransactionsCache.cache[accountS][transactionType][transactionSubType] = transactions
+	if c.localTransactionsCache.cache[accountS][transactionType][transactionSubType] == nil {
+		c.localTransactionsCache.cache[accountS][transactionType][transactionSubType] = make(map[uint64]*AccountTransactions)
+	}
+	c.localTransactionsCache.cache[accountS][transactionType][transactionSubType][page] = transactions
 	c.localTransactionsCache.Unlock()
 }
 
-func (c *SignumApiClient) getCachedAccountTransactionsByType(logger abstractapi.LoggerI, account string, transactionType TransactionType, transactionSubType TransactionSubType) (*AccountTransactions, error) {
-	accountTransactions := c.readAccountTransactionsFromCache(account, transactionType, transactionSubType)
+// pruneAccountTransactionsCache must be called under the cache lock
+func (c *SignumApiClient) pruneAccountTransactionsCache() {
+	for account, transactionTypeCache := range c.localTransactionsCache.cache {
+		for transactionType, transactionSubTypeCache := range transactionTypeCache {
+			for transactionSubType, pages := range transactionSubTypeCache {
+				for page, transactions := range pages {
+					if time.Since(transactions.LastUpdateTime) >= c.config.CacheTtl {
+						delete(pages, page)
+					}
+				}
+				if len(pages) == 0 {
+					delete(transactionSubTypeCache, transactionSubType)
+				}
+			}
+			if len(transactionSubTypeCache) == 0 {
+				delete(transactionTypeCache, transactionType)
+			}
+		}
+		if len(transactionTypeCache) == 0 {
+			delete(c.localTransactionsCache.cache, account)
+		}
+	}
+}
+
+func (c *SignumApiClient) getCachedAccountTransactionsByType(logger abstractapi.LoggerI, account string, transactionType TransactionType, transactionSubType TransactionSubType, page uint64) (*AccountTransactions, error) {
+	accountTransactions := c.readAccountTransactionsFromCache(account, transactionType, transactionSubType, page)
 	if accountTransactions != nil {
 		return accountTransactions, nil
 	}
-	return c.getAccountTransactionsByType(logger, account, transactionType, transactionSubType)
+	return c.getAccountTransactionsByType(logger, account, transactionType, transactionSubType, page)
 }
 
-func (c *SignumApiClient) GetCachedAccountOrdinaryPaymentTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getCachedAccountTransactionsByType(logger, account, TT_PAYMENT, TST_ORDINARY_PAYMENT)
+func (c *SignumApiClient) GetCachedAccountOrdinaryPaymentTransactions(logger abstractapi.LoggerI, account string, page uint64) (*AccountTransactions, error) {
+	return c.getCachedAccountTransactionsByType(logger, account, TT_PAYMENT, TST_ORDINARY_PAYMENT, page)
 }
 
-func (c *SignumApiClient) GetCachedAccountMultiOutTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getCachedAccountTransactionsByType(logger, account, TT_PAYMENT, TST_MULTI_OUT_PAYMENT)
+func (c *SignumApiClient) GetCachedAccountMultiOutTransactions(logger abstractapi.LoggerI, account string, page uint64) (*AccountTransactions, error) {
+	return c.getCachedAccountTransactionsByType(logger, account, TT_PAYMENT, TST_MULTI_OUT_PAYMENT, page)
 }
 
-func (c *SignumApiClient) GetCachedAccountMultiOutSameTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getCachedAccountTransactionsByType(logger, account, TT_PAYMENT, TST_MULTI_OUT_SAME_PAYMENT)
+func (c *SignumApiClient) GetCachedAccountMultiOutSameTransactions(logger abstractapi.LoggerI, account string, page uint64) (*AccountTransactions, error) {
+	return c.getCachedAccountTransactionsByType(logger, account, TT_PAYMENT, TST_MULTI_OUT_SAME_PAYMENT, page)
 }
 
-func (c *SignumApiClient) GetCachedAccountPaymentTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getCachedAccountTransactionsByType(logger, account, TT_PAYMENT, TST_ALL_TYPES_PAYMENT)
+func (c *SignumApiClient) GetCachedAccountPaymentTransactions(logger abstractapi.LoggerI, account string, page uint64) (*AccountTransactions, error) {
+	return c.getCachedAccountTransactionsByType(logger, account, TT_PAYMENT, TST_ALL_TYPES_PAYMENT, page)
 }
 
-func (c *SignumApiClient) GetCachedAccountMiningTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getCachedAccountTransactionsByType(logger, account, TT_BURST_MINING, TST_ALL_TYPES_MINING)
+func (c *SignumApiClient) GetCachedAccountMiningTransactions(logger abstractapi.LoggerI, account string, page uint64) (*AccountTransactions, error) {
+	return c.getCachedAccountTransactionsByType(logger, account, TT_BURST_MINING, TST_ALL_TYPES_MINING, page)
 }
 
-func (c *SignumApiClient) GetCachedAccountMessageTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getCachedAccountTransactionsByType(logger, account, TT_MESSAGING, TST_ARBITRARY_MESSAGE)
+func (c *SignumApiClient) GetCachedAccountMessageTransactions(logger abstractapi.LoggerI, account string, page uint64) (*AccountTransactions, error) {
+	return c.getCachedAccountTransactionsByType(logger, account, TT_MESSAGING, TST_ARBITRARY_MESSAGE, page)
 }
 
-func (c *SignumApiClient) GetCachedAccountATPaymentTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getCachedAccountTransactionsByType(logger, account, TT_AUTOMATED_TRANSACTIONS, TST_AT_PAYMENT)
+func (c *SignumApiClient) GetCachedAccountATPaymentTransactions(logger abstractapi.LoggerI, account string, page uint64) (*AccountTransactions, error) {
+	return c.getCachedAccountTransactionsByType(logger, account, TT_AUTOMATED_TRANSACTIONS, TST_AT_PAYMENT, page)
 }
 
-func (c *SignumApiClient) GetCachedAccountTokenizationTransactions(logger abstractapi.LoggerI, account string) (*AccountTransactions, error) {
-	return c.getCachedAccountTransactionsByType(logger, account, TT_TOKENIZATION, TST_TOKENIZATION_DISTRIBUTION_TO_HOLDER)
+func (c *SignumApiClient) GetCachedAccountTokenizationTransactions(logger abstractapi.LoggerI, account string, page uint64) (*AccountTransactions, error) {
+	return c.getCachedAccountTransactionsByType(logger, account, TT_TOKENIZATION, TST_TOKENIZATION_DISTRIBUTION_TO_HOLDER, page)
 }
 
 func (c *SignumApiClient) GetLastCachedAccountPaymentTransaction(logger abstractapi.LoggerI, account string) *Transaction {
-	userTransactions, err := c.GetCachedAccountPaymentTransactions(logger, account)
+	userTransactions, err := c.GetCachedAccountPaymentTransactions(logger, account, 0)
 	if err == nil && userTransactions != nil && len(userTransactions.Transactions) > 0 {
 		return &userTransactions.Transactions[0]
 	}
@@ -91,7 +120,7 @@ func (c *SignumApiClient) GetLastCachedAccountPaymentTransaction(logger abstract
 }
 
 func (c *SignumApiClient) GetLastCachedAccountMiningTransaction(logger abstractapi.LoggerI, account string) *Transaction {
-	userTransactions, err := c.GetCachedAccountMiningTransactions(logger, account)
+	userTransactions, err := c.GetCachedAccountMiningTransactions(logger, account, 0)
 	if err == nil && userTransactions != nil && len(userTransactions.Transactions) > 0 {
 		return &userTransactions.Transactions[0]
 	}
@@ -99,7 +128,7 @@ func (c *SignumApiClient) GetLastCachedAccountMiningTransaction(logger abstracta
 }
 
 func (c *SignumApiClient) GetLastCachedAccountAddCommitmentTransaction(logger abstractapi.LoggerI, account string) *Transaction {
-	userTransactions, err := c.getCachedAccountTransactionsByType(logger, account, TT_BURST_MINING, TST_ADD_COMMITMENT)
+	userTransactions, err := c.getCachedAccountTransactionsByType(logger, account, TT_BURST_MINING, TST_ADD_COMMITMENT, 0)
 	if err == nil && userTransactions != nil && len(userTransactions.Transactions) > 0 {
 		return &userTransactions.Transactions[0]
 	}
@@ -107,7 +136,7 @@ func (c *SignumApiClient) GetLastCachedAccountAddCommitmentTransaction(logger ab
 }
 
 func (c *SignumApiClient) GetLastCachedAccountMessageTransaction(logger abstractapi.LoggerI, account string) *Transaction {
-	userMessages, err := c.GetCachedAccountMessageTransactions(logger, account)
+	userMessages, err := c.GetCachedAccountMessageTransactions(logger, account, 0)
 	if err == nil && userMessages != nil && len(userMessages.Transactions) > 0 {
 		return &userMessages.Transactions[0]
 	}
@@ -115,7 +144,7 @@ func (c *SignumApiClient) GetLastCachedAccountMessageTransaction(logger abstract
 }
 
 func (c *SignumApiClient) GetLastCachedAccountATPaymentTransaction(logger abstractapi.LoggerI, account string) *Transaction {
-	atPaymentTransactions, err := c.GetCachedAccountATPaymentTransactions(logger, account)
+	atPaymentTransactions, err := c.GetCachedAccountATPaymentTransactions(logger, account, 0)
 	if err == nil && atPaymentTransactions != nil && len(atPaymentTransactions.Transactions) > 0 {
 		return &atPaymentTransactions.Transactions[0]
 	}
@@ -123,7 +152,7 @@ func (c *SignumApiClient) GetLastCachedAccountATPaymentTransaction(logger abstra
 }
 
 func (c *SignumApiClient) GetLastCachedAccountTokenizationTransaction(logger abstractapi.LoggerI, account string) *Transaction {
-	tokenizationTransactions, err := c.GetCachedAccountTokenizationTransactions(logger, account)
+	tokenizationTransactions, err := c.GetCachedAccountTokenizationTransactions(logger, account, 0)
 	if err == nil && tokenizationTransactions != nil && len(tokenizationTransactions.Transactions) > 0 {
 		return &tokenizationTransactions.Transactions[0]
 	}
@@ -0,0 +1,125 @@
+package signumapi
+
+import (
+	"strconv"
+
+	"github.com/xDWart/signum-explorer-bot/api/abstractapi"
+)
+
+type Subscription struct {
+	ID          string `json:"id"`
+	Sender      string `json:"sender"`
+	SenderRS    string `json:"senderRS"`
+	Recipient   string `json:"recipient"`
+	RecipientRS string `json:"recipientRS"`
+	AmountNQT   uint64 `json:"amountNQT,string"`
+	Frequency   uint64 `json:"frequency"` // in seconds
+	TimeNext    int64  `json:"timeNext"`  // chain time of the next payment
+}
+
+type Subscriptions struct {
+	Subscriptions    []Subscription `json:"subscriptions"`
+	ErrorDescription string         `json:"errorDescription"`
+}
+
+func (s *Subscriptions) GetError() string {
+	return s.ErrorDescription
+}
+
+func (s *Subscriptions) ClearError() {
+	s.ErrorDescription = ""
+}
+
+// GetAccountSubscriptions returns subscriptions paid by the account
+func (c *SignumApiClient) GetAccountSubscriptions(logger abstractapi.LoggerI, account string) (*Subscriptions, error) {
+	subscriptions := &Subscriptions{}
+	_, err := c.doJsonReq(logger, "GET", "/burst",
+		map[string]string{"requestType": string(RT_GET_ACCOUNT_SUBSCRIPTIONS), "account": account},
+		nil,
+		subscriptions)
+	return subscriptions, err
+}
+
+// GetSubscriptionsToAccount returns subscriptions paid to the account
+func (c *SignumApiClient) GetSubscriptionsToAccount(logger abstractapi.LoggerI, account string) (*Subscriptions, error) {
+	subscriptions := &Subscriptions{}
+	_, err := c.doJsonReq(logger, "GET", "/burst",
+		map[string]string{"requestType": string(RT_GET_SUBSCRIPTIONS_TO_ACCOUNT), "account": account},
+		nil,
+		subscriptions)
+	return subscriptions, err
+}
+
+type EscrowDecision string
+
+const (
+	ED_UNDECIDED EscrowDecision = "undecided"
+	ED_RELEASE   EscrowDecision = "release"
+	ED_REFUND    EscrowDecision = "refund"
+	ED_SPLIT     EscrowDecision = "split"
+)
+
+type EscrowSigner struct {
+	ID       string         `json:"id"`
+	IDRS     string         `json:"idRS"`
+	Decision EscrowDecision `json:"decision"`
+}
+
+type Escrow struct {
+	ID              string         `json:"id"`
+	Sender          string         `json:"sender"`
+	SenderRS        string         `json:"senderRS"`
+	Recipient       string         `json:"recipient"`
+	RecipientRS     string         `json:"recipientRS"`
+	AmountNQT       uint64         `json:"amountNQT,string"`
+	RequiredSigners uint64         `json:"requiredSigners"`
+	Deadline        int64          `json:"deadline"` // chain time
+	DeadlineAction  EscrowDecision `json:"deadlineAction"`
+	Signers         []EscrowSigner `json:"signers"`
+}
+
+type Escrows struct {
+	Escrows          []Escrow `json:"escrows"`
+	ErrorDescription string   `json:"errorDescription"`
+}
+
+func (e *Escrows) GetError() string {
+	return e.ErrorDescription
+}
+
+func (e *Escrows) ClearError() {
+	e.ErrorDescription = ""
+}
+
+// GetAccountEscrows returns unresolved escrows where the account is the sender, the recipient or a signer
+func (c *SignumApiClient) GetAccountEscrows(logger abstractapi.LoggerI, account string) (*Escrows, error) {
+	escrows := &Escrows{}
+	_, err := c.doJsonReq(logger, "GET", "/burst",
+		map[string]string{"requestType": string(RT_GET_ACCOUNT_ESCROW_TRANSACTIONS), "account": account},
+		nil,
+		escrows)
+	return escrows, err
+}
+
+// GetAccountAdvancedPaymentTransactions returns the page of escrow and subscription transactions not older than timestamp (in chain time),
+// subscription payments and escrow results are made by the node itself and can't be found in blocks
+func (c *SignumApiClient) GetAccountAdvancedPaymentTransactions(logger abstractapi.LoggerI, account string, timestamp int64, page uint64) (*AccountTransactions, error) {
+	accountTransactions := &AccountTransactions{}
+
+	firstIndex, lastIndex := c.getPageIndexes(page)
+	urlParams := map[string]string{
+		"account":     account,
+		"requestType": string(RT_GET_ACCOUNT_TRANSACTIONS),
+		"type":        strconv.Itoa(int(TT_ADVANCED_PAYMENT)),
+		"timestamp":   strconv.FormatInt(timestamp, 10),
+		"firstIndex":  firstIndex,
+		"lastIndex":   lastIndex,
+	}
+
+	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, accountTransactions)
+	if err == nil && uint64(len(accountTransactions.Transactions)) > c.GetPageSize() {
+		accountTransactions.Transactions = accountTransactions.Transactions[:c.GetPageSize()]
+		accountTransactions.HasNextPage = true
+	}
+	return accountTransactions, err
+}
@@ -0,0 +1,78 @@
+package signumapi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/xDWart/signum-explorer-bot/api/abstractapi"
+)
+
+type Alias struct {
+	Alias            string `json:"alias"`
+	AliasName        string `json:"aliasName"`
+	AliasURI         string `json:"aliasURI"`
+	Account          string `json:"account"`
+	AccountRS        string `json:"accountRS"`
+	Timestamp        int64  `json:"timestamp"`
+	PriceNQT         uint64 `json:"priceNQT,string"` // is set only if the alias is for sale
+	Buyer            string `json:"buyer"`           // the only account which can buy the alias, empty if anybody
+	BuyerRS          string `json:"buyerRS"`
+	ErrorDescription string `json:"errorDescription"`
+}
+
+func (a *Alias) GetError() string {
+	return a.ErrorDescription
+}
+
+func (a *Alias) ClearError() {
+	a.ErrorDescription = ""
+}
+
+// GetURIAccount returns the account of the alias URI like acct:S-XXXX-XXXX-XXXX-XXXXX@signum, empty if it's not an account
+func (a *Alias) GetURIAccount() string {
+	if !strings.HasPrefix(strings.ToLower(a.AliasURI), "acct:") {
+		return ""
+	}
+	account := a.AliasURI[len("acct:"):]
+	if i := strings.Index(account, "@"); i >= 0 {
+		account = account[:i]
+	}
+	return strings.ToUpper(account)
+}
+
+func (c *SignumApiClient) GetAlias(logger abstractapi.LoggerI, aliasName string) (*Alias, error) {
+	alias := &Alias{}
+	_, err := c.doJsonReq(logger, "GET", "/burst",
+		map[string]string{"requestType": string(RT_GET_ALIAS), "aliasName": aliasName},
+		nil,
+		alias)
+	return alias, err
+}
+
+type Aliases struct {
+	Aliases          []Alias `json:"aliases"`
+	ErrorDescription string  `json:"errorDescription"`
+}
+
+func (a *Aliases) GetError() string {
+	return a.ErrorDescription
+}
+
+func (a *Aliases) ClearError() {
+	a.ErrorDescription = ""
+}
+
+// GetAccountAliases returns the first count aliases owned by the account
+func (c *SignumApiClient) GetAccountAliases(logger abstractapi.LoggerI, account string, count int) (*Aliases, error) {
+	aliases := &Aliases{}
+
+	urlParams := map[string]string{
+		"account":     account,
+		"requestType": string(RT_GET_ALIASES),
+		"firstIndex":  "0",
+		"lastIndex":   strconv.Itoa(count - 1),
+	}
+
+	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, aliases)
+	return aliases, err
+}
@@ -1,6 +1,11 @@
 package signumapi
 
 import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
 	"github.com/xDWart/signum-explorer-bot/api/abstractapi"
 )
 
@@ -25,9 +30,42 @@ type Asset struct {
 	PriceOpen              string `json:"priceOpen"`
 	PriceClose             string `json:"priceClose"`
 	ErrorDescription       string `json:"errorDescription"`
+	lastUpdateTime         time.Time
 	//RequestProcessingTime  uint64 `json:"requestProcessingTime"`
 }
 
+// GetQuantity converts QNT into tokens according to the decimals
+func (a *Asset) GetQuantity(quantityQNT uint64) float64 {
+	return float64(quantityQNT) / math.Pow10(int(a.Decimals))
+}
+
+// GetCirculatingQNT returns the circulating supply in QNT
+func (a *Asset) GetCirculatingQNT() uint64 {
+	quantity, _ := strconv.ParseUint(a.QuantityCirculatingQNT, 10, 64)
+	return quantity
+}
+
+// GetPriceNQT returns the last trade price in NQT per QNT
+func (a *Asset) GetPriceNQT() float64 {
+	price, _ := strconv.ParseFloat(a.PriceClose, 64)
+	return price
+}
+
+// GetPrice returns the last trade price of one token in SIGNA
+func (a *Asset) GetPrice() float64 {
+	return a.GetTokenPrice(a.GetPriceNQT())
+}
+
+// GetTokenPrice converts the price in NQT per QNT into SIGNA per token
+func (a *Asset) GetTokenPrice(priceNQT float64) float64 {
+	return priceNQT * math.Pow10(int(a.Decimals)) / 1e8
+}
+
+// GetValueNQT returns the value of the quantity by the last trade price
+func (a *Asset) GetValueNQT(quantityQNT uint64) uint64 {
+	return uint64(float64(quantityQNT) * a.GetPriceNQT())
+}
+
 func (a *Asset) GetError() string {
 	return a.ErrorDescription
 }
@@ -45,5 +83,120 @@ func (c *SignumApiClient) GetAsset(logger abstractapi.LoggerI, token string) (*A
 	}
 
 	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, asset)
+	if err == nil {
+		c.storeAssetToCache(token, asset)
+	}
 	return asset, err
 }
+
+type AssetsCache struct {
+	sync.RWMutex
+	cache map[string]*Asset
+}
+
+func (c *SignumApiClient) readAssetFromCache(token string) *Asset {
+	c.localAssetsCache.RLock()
+	asset := c.localAssetsCache.cache[token]
+	c.localAssetsCache.RUnlock()
+	if asset != nil && time.Since(asset.lastUpdateTime) < c.config.CacheTtl {
+		return asset
+	}
+	return nil
+}
+
+func (c *SignumApiClient) storeAssetToCache(token string, asset *Asset) {
+	c.localAssetsCache.Lock()
+	asset.lastUpdateTime = time.Now()
+	c.localAssetsCache.cache[token] = asset
+	c.localAssetsCache.Unlock()
+}
+
+func (c *SignumApiClient) GetCachedAsset(logger abstractapi.LoggerI, token string) (*Asset, error) {
+	asset := c.readAssetFromCache(token)
+	if asset != nil {
+		return asset, nil
+	}
+	return c.GetAsset(logger, token)
+}
+
+type AssetAccount struct {
+	Account     string `json:"account"`
+	AccountRS   string `json:"accountRS"`
+	QuantityQNT uint64 `json:"quantityQNT,string"`
+}
+
+type AssetAccounts struct {
+	AccountAssets    []AssetAccount `json:"accountAssets"`
+	ErrorDescription string         `json:"errorDescription"`
+}
+
+func (aa *AssetAccounts) GetError() string {
+	return aa.ErrorDescription
+}
+
+func (aa *AssetAccounts) ClearError() {
+	aa.ErrorDescription = ""
+}
+
+const assetAccountsPageSize = 500
+
+func (c *SignumApiClient) getAssetAccountsPage(logger abstractapi.LoggerI, token string, firstIndex, lastIndex int) (*AssetAccounts, error) {
+	assetAccounts := &AssetAccounts{}
+	urlParams := map[string]string{
+		"asset":       token,
+		"requestType": string(RT_GET_ASSET_ACCOUNTS),
+		"firstIndex":  strconv.Itoa(firstIndex),
+		"lastIndex":   strconv.Itoa(lastIndex),
+	}
+
+	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, assetAccounts)
+	return assetAccounts, err
+}
+
+// GetAssetAccounts returns all holders of the token, requesting them page by page
+func (c *SignumApiClient) GetAssetAccounts(logger abstractapi.LoggerI, token string) (*AssetAccounts, error) {
+	assetAccounts := &AssetAccounts{}
+
+	for firstIndex := 0; ; firstIndex += assetAccountsPageSize {
+		page, err := c.getAssetAccountsPage(logger, token, firstIndex, firstIndex+assetAccountsPageSize-1)
+		if err != nil {
+			return assetAccounts, err
+		}
+
+		assetAccounts.AccountAssets = append(assetAccounts.AccountAssets, page.AccountAssets...)
+		if len(page.AccountAssets) < assetAccountsPageSize {
+			return assetAccounts, nil
+		}
+	}
+}
+
+// GetAssetTopHolders returns the biggest holders of the token, the node sorts them by quantity
+func (c *SignumApiClient) GetAssetTopHolders(logger abstractapi.LoggerI, token string, count int) (*AssetAccounts, error) {
+	return c.getAssetAccountsPage(logger, token, 0, count-1)
+}
+
+type Assets struct {
+	Assets           []Asset `json:"assets"`
+	ErrorDescription string  `json:"errorDescription"`
+}
+
+func (a *Assets) GetError() string {
+	return a.ErrorDescription
+}
+
+func (a *Assets) ClearError() {
+	a.ErrorDescription = ""
+}
+
+// GetAssetsByName returns all tokens with the name, token names are not unique
+func (c *SignumApiClient) GetAssetsByName(logger abstractapi.LoggerI, name string) (*Assets, error) {
+	assets := &Assets{}
+
+	urlParams := map[string]string{
+		"name":        name,
+		"requestType": string(RT_GET_ASSETS_BY_NAME),
+	}
+
+	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, assets)
+	return assets, err
+}
@@ -1,30 +1,35 @@
 package signumapi
 
-import "github.com/xDWart/signum-explorer-bot/api/abstractapi"
+import (
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/xDWart/signum-explorer-bot/api/abstractapi"
+)
 
 type ATDetails struct {
-	AT          string `json:"at"`
-	MachineData string `json:"machineData"`
-	BalanceNQT  uint64 `json:"balanceNQT,string"`
-	NextBlock   uint64 `json:"nextBlock"`
-	Name        string `json:"name"`
-	//PrevBalanceNQT        string `json:"prevBalanceNQT"`
-	//Frozen                bool   `json:"frozen"`
-	//Running               bool   `json:"running"`
-	//Stopped               bool   `json:"stopped"`
-	//Finished              bool   `json:"finished"`
-	//Dead                  bool   `json:"dead"`
-	//MachineCodeHashId     string `json:"machineCodeHashId"`
-	//AtVersion             int    `json:"atVersion"`
-	//AtRS                  string `json:"atRS"`
-	//Description           string `json:"description"`
-	//Creator               string `json:"creator"`
-	//CreatorRS             string `json:"creatorRS"`
-	//MachineCode           string `json:"machineCode"`
-	//MinActivation         string `json:"minActivation"`
-	//CreationBlock         int    `json:"creationBlock"`
+	AT                string `json:"at"`
+	AtRS              string `json:"atRS"`
+	AtVersion         int    `json:"atVersion"`
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	Creator           string `json:"creator"`
+	CreatorRS         string `json:"creatorRS"`
+	MachineCode       string `json:"machineCode"`
+	MachineCodeHashId string `json:"machineCodeHashId"`
+	MachineData       string `json:"machineData"`
+	BalanceNQT        uint64 `json:"balanceNQT,string"`
+	PrevBalanceNQT    uint64 `json:"prevBalanceNQT,string"`
+	MinActivationNQT  uint64 `json:"minActivation,string"`
+	NextBlock         uint64 `json:"nextBlock"`
+	CreationBlock     uint64 `json:"creationBlock"`
+	Frozen            bool   `json:"frozen"`
+	Running           bool   `json:"running"`
+	Stopped           bool   `json:"stopped"`
+	Finished          bool   `json:"finished"`
+	Dead              bool   `json:"dead"`
+	ErrorDescription  string `json:"errorDescription"`
 	//RequestProcessingTime int    `json:"requestProcessingTime"`
-	ErrorDescription string `json:"errorDescription"`
 }
 
 func (a *ATDetails) GetError() string {
@@ -35,6 +40,19 @@ func (a *ATDetails) ClearError() {
 	a.ErrorDescription = ""
 }
 
+// GetVariables decodes the machine data into 8-byte little-endian variables, an incomplete tail is dropped
+func (a *ATDetails) GetVariables() []uint64 {
+	data, err := hex.DecodeString(a.MachineData)
+	if err != nil {
+		return nil
+	}
+	variables := make([]uint64, 0, len(data)/8)
+	for i := 0; i+8 <= len(data); i += 8 {
+		variables = append(variables, binary.LittleEndian.Uint64(data[i:i+8]))
+	}
+	return variables
+}
+
 func (c *SignumApiClient) GetATDetails(logger abstractapi.LoggerI, at string) (*ATDetails, error) {
 	atDetails := &ATDetails{}
 	_, err := c.doJsonReq(logger, "GET", "/burst",
@@ -0,0 +1,38 @@
+package signumapi
+
+import (
+	"strconv"
+
+	"github.com/xDWart/signum-explorer-bot/api/abstractapi"
+)
+
+type BlockWithTransactions struct {
+	Block
+	Transactions []Transaction `json:"transactions"`
+}
+
+func (c *SignumApiClient) GetBlockWithTransactions(logger abstractapi.LoggerI, height uint64) (*BlockWithTransactions, error) {
+	block := &BlockWithTransactions{}
+	_, err := c.doJsonReq(logger, "GET", "/burst",
+		map[string]string{
+			"requestType":         string(RT_GET_BLOCK),
+			"height":              strconv.FormatUint(height, 10),
+			"includeTransactions": "true",
+		},
+		nil,
+		block)
+	return block, err
+}
+
+// GetBlockByHeight returns the block without its transactions
+func (c *SignumApiClient) GetBlockByHeight(logger abstractapi.LoggerI, height uint64) (*Block, error) {
+	block := &Block{}
+	_, err := c.doJsonReq(logger, "GET", "/burst",
+		map[string]string{
+			"requestType": string(RT_GET_BLOCK),
+			"height":      strconv.FormatUint(height, 10),
+		},
+		nil,
+		block)
+	return block, err
+}
@@ -0,0 +1,148 @@
+package signumapi
+
+import (
+	"strconv"
+
+	"github.com/xDWart/signum-explorer-bot/api/abstractapi"
+)
+
+type Goods struct {
+	Goods            string `json:"goods"`
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	Tags             string `json:"tags"`
+	Quantity         uint64 `json:"quantity"` // in stock
+	PriceNQT         uint64 `json:"priceNQT,string"`
+	Seller           string `json:"seller"`
+	SellerRS         string `json:"sellerRS"`
+	Delisted         bool   `json:"delisted"`
+	Timestamp        int64  `json:"timestamp"`
+	ErrorDescription string `json:"errorDescription"`
+}
+
+func (g *Goods) GetError() string {
+	return g.ErrorDescription
+}
+
+func (g *Goods) ClearError() {
+	g.ErrorDescription = ""
+}
+
+type GoodsList struct {
+	Goods            []Goods `json:"goods"`
+	ErrorDescription string  `json:"errorDescription"`
+}
+
+func (g *GoodsList) GetError() string {
+	return g.ErrorDescription
+}
+
+func (g *GoodsList) ClearError() {
+	g.ErrorDescription = ""
+}
+
+type Purchase struct {
+	Purchase         string        `json:"purchase"`
+	Goods            string        `json:"goods"`
+	Name             string        `json:"name"`
+	Seller           string        `json:"seller"`
+	SellerRS         string        `json:"sellerRS"`
+	Buyer            string        `json:"buyer"`
+	BuyerRS          string        `json:"buyerRS"`
+	Quantity         uint64        `json:"quantity"`
+	PriceNQT         uint64        `json:"priceNQT,string"` // per item
+	DeliveryDeadline int64         `json:"deliveryDeadlineTimestamp"`
+	Pending          bool          `json:"pending"` // isn't delivered yet
+	Timestamp        int64         `json:"timestamp"`
+	DiscountNQT      uint64        `json:"discountNQT,string"`
+	RefundNQT        uint64        `json:"refundNQT,string"`
+	FeedbackNotes    []interface{} `json:"feedbackNotes"` // encrypted
+	PublicFeedbacks  []string      `json:"publicFeedbacks"`
+	ErrorDescription string        `json:"errorDescription"`
+}
+
+func (p *Purchase) GetError() string {
+	return p.ErrorDescription
+}
+
+func (p *Purchase) ClearError() {
+	p.ErrorDescription = ""
+}
+
+// GetAmountNQT returns the price of all purchased items
+func (p *Purchase) GetAmountNQT() uint64 {
+	return p.Quantity * p.PriceNQT
+}
+
+type Purchases struct {
+	Purchases        []Purchase `json:"purchases"`
+	ErrorDescription string     `json:"errorDescription"`
+}
+
+func (p *Purchases) GetError() string {
+	return p.ErrorDescription
+}
+
+func (p *Purchases) ClearError() {
+	p.ErrorDescription = ""
+}
+
+func (c *SignumApiClient) GetGoods(logger abstractapi.LoggerI, goodsID string) (*Goods, error) {
+	goods := &Goods{}
+	_, err := c.doJsonReq(logger, "GET", "/burst",
+		map[string]string{"requestType": string(RT_GET_DGS_GOOD), "goods": goodsID},
+		nil,
+		goods)
+	return goods, err
+}
+
+// GetSellerGoods returns listed goods of the seller from first to last index, sold out ones too
+func (c *SignumApiClient) GetSellerGoods(logger abstractapi.LoggerI, seller string, firstIndex, lastIndex int) (*GoodsList, error) {
+	goodsList := &GoodsList{}
+
+	urlParams := map[string]string{
+		"requestType": string(RT_GET_DGS_GOODS),
+		"seller":      seller,
+		"inStockOnly": "false",
+		"firstIndex":  strconv.Itoa(firstIndex),
+		"lastIndex":   strconv.Itoa(lastIndex),
+	}
+
+	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, goodsList)
+	return goodsList, err
+}
+
+func (c *SignumApiClient) GetDGSPurchase(logger abstractapi.LoggerI, purchaseID string) (*Purchase, error) {
+	purchase := &Purchase{}
+	_, err := c.doJsonReq(logger, "GET", "/burst",
+		map[string]string{"requestType": string(RT_GET_DGS_PURCHASE), "purchase": purchaseID},
+		nil,
+		purchase)
+	return purchase, err
+}
+
+// GetSellerPendingPurchases returns purchases waiting for the delivery by the seller
+func (c *SignumApiClient) GetSellerPendingPurchases(logger abstractapi.LoggerI, seller string) (*Purchases, error) {
+	purchases := &Purchases{}
+	_, err := c.doJsonReq(logger, "GET", "/burst",
+		map[string]string{"requestType": string(RT_GET_DGS_PENDING_PURCHASES), "seller": seller},
+		nil,
+		purchases)
+	return purchases, err
+}
+
+// GetSellerCompletedPurchases returns the last delivered purchases of the seller, newest first
+func (c *SignumApiClient) GetSellerCompletedPurchases(logger abstractapi.LoggerI, seller string, count int) (*Purchases, error) {
+	purchases := &Purchases{}
+
+	urlParams := map[string]string{
+		"requestType": string(RT_GET_DGS_PURCHASES),
+		"seller":      seller,
+		"completed":   "true",
+		"firstIndex":  "0",
+		"lastIndex":   strconv.Itoa(count - 1),
+	}
+
+	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, purchases)
+	return purchases, err
+}
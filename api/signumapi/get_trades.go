@@ -0,0 +1,86 @@
+package signumapi
+
+import (
+	"strconv"
+
+	"github.com/xDWart/signum-explorer-bot/api/abstractapi"
+)
+
+type Trade struct {
+	Asset       string `json:"asset"`
+	Name        string `json:"name"`
+	Decimals    uint64 `json:"decimals"`
+	QuantityQNT uint64 `json:"quantityQNT,string"`
+	PriceNQT    uint64 `json:"priceNQT,string"` // per QNT
+	AskOrder    string `json:"askOrder"`
+	BidOrder    string `json:"bidOrder"`
+	Seller      string `json:"seller"`
+	SellerRS    string `json:"sellerRS"`
+	Buyer       string `json:"buyer"`
+	BuyerRS     string `json:"buyerRS"`
+	Block       string `json:"block"`
+	Height      uint64 `json:"height"`
+	Timestamp   int64  `json:"timestamp"`
+	TradeType   string `json:"tradeType"` // buy or sell, depends on which order has been filled by the newer one
+}
+
+// GetAmountNQT returns the amount of SIGNA paid for the tokens
+func (t *Trade) GetAmountNQT() uint64 {
+	return t.QuantityQNT * t.PriceNQT
+}
+
+type Trades struct {
+	Trades           []Trade `json:"trades"`
+	ErrorDescription string  `json:"errorDescription"`
+}
+
+func (t *Trades) GetError() string {
+	return t.ErrorDescription
+}
+
+func (t *Trades) ClearError() {
+	t.ErrorDescription = ""
+}
+
+const tradesPageSize = 100
+
+// GetAllTrades returns trades of all tokens not older than timestamp (in chain time), requesting them page by page
+func (c *SignumApiClient) GetAllTrades(logger abstractapi.LoggerI, timestamp int64) (*Trades, error) {
+	trades := &Trades{}
+
+	for firstIndex := 0; ; firstIndex += tradesPageSize {
+		page := &Trades{}
+		urlParams := map[string]string{
+			"requestType":      string(RT_GET_ALL_TRADES),
+			"timestamp":        strconv.FormatInt(timestamp, 10),
+			"includeAssetInfo": "true",
+			"firstIndex":       strconv.Itoa(firstIndex),
+			"lastIndex":        strconv.Itoa(firstIndex + tradesPageSize - 1),
+		}
+
+		_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, page)
+		if err != nil {
+			return trades, err
+		}
+
+		trades.Trades = append(trades.Trades, page.Trades...)
+		if len(page.Trades) < tradesPageSize {
+			return trades, nil
+		}
+	}
+}
+
+// GetAssetTrades returns the last trades of the token, newest first
+func (c *SignumApiClient) GetAssetTrades(logger abstractapi.LoggerI, token string, count int) (*Trades, error) {
+	trades := &Trades{}
+
+	urlParams := map[string]string{
+		"asset":       token,
+		"requestType": string(RT_GET_TRADES),
+		"firstIndex":  "0",
+		"lastIndex":   strconv.Itoa(count - 1),
+	}
+
+	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, trades)
+	return trades, err
+}
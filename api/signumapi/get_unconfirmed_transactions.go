@@ -30,10 +30,12 @@ func (c *SignumApiClient) GetUnconfirmedTransactions(logger abstractapi.LoggerI,
 	}
 
 	urlParams := map[string]string{
-		"account":         account,
 		"requestType":     string(RT_GET_UNCONFIRMED_TRANSACTIONS),
 		"includeIndirect": includeIndirectStr,
 	}
+	if account != "" { // otherwise the whole mempool
+		urlParams["account"] = account
+	}
 
 	_, err := c.doJsonReq(logger, "GET", "/burst", urlParams, nil, unconfirmedTransactions)
 	return unconfirmedTransactions, err
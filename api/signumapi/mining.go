@@ -24,6 +24,29 @@ func (c *SignumApiClient) GetRewardRecipient(logger abstractapi.LoggerI, account
 	return &rewardRecipient, err
 }
 
+type RewardRecipientAccounts struct {
+	Accounts         []string `json:"accounts"`
+	ErrorDescription string   `json:"errorDescription"`
+}
+
+func (rra *RewardRecipientAccounts) GetError() string {
+	return rra.ErrorDescription
+}
+
+func (rra *RewardRecipientAccounts) ClearError() {
+	rra.ErrorDescription = ""
+}
+
+// GetAccountsWithRewardRecipient returns accounts assigned to the pool
+func (c *SignumApiClient) GetAccountsWithRewardRecipient(logger abstractapi.LoggerI, account string) (*RewardRecipientAccounts, error) {
+	var rewardRecipientAccounts = RewardRecipientAccounts{}
+	_, err := c.doJsonReq(logger, "GET", "/burst", map[string]string{
+		"requestType": string(RT_GET_ACCOUNTS_WITH_REWARD_RECIPIENT),
+		"account":     account,
+	}, nil, &rewardRecipientAccounts)
+	return &rewardRecipientAccounts, err
+}
+
 func (c *SignumApiClient) SetRewardRecipient(logger abstractapi.LoggerI, secretPhrase, recipient string, feeNQT uint64) (*TransactionResponse, error) {
 	return c.createTransaction(logger,
 		&TransactionRequest{
@@ -1,6 +1,7 @@
 package signumapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
@@ -19,6 +20,8 @@ type Transaction struct {
 	Sender        string             `json:"sender"`
 	SenderRS      string             `json:"senderRS"`
 	Height        uint64             `json:"height"`
+	Deadline      uint64             `json:"deadline"`     // in minutes
+	Block         uint64             `json:"block,string"` // is empty for unconfirmed transactions
 	Attachment    struct {
 		Recipients       RecipientsType `json:"recipients"`
 		AmountNQT        uint64         `json:"amountNQT"`
@@ -26,6 +29,21 @@ type Transaction struct {
 		MessageIsText    bool           `json:"messageIsText"`
 		EncryptedMessage interface{}    `json:"encryptedMessage"`
 		Asset            string         `json:"asset"`
+		QuantityQNT      json.Number    `json:"quantityQNT"` // asset transfers and orders
+		PriceNQT         json.Number    `json:"priceNQT"`    // per QNT for orders, the whole price for alias sales
+		Order            string         `json:"order"`       // cancelled order, it's the ID of the placement transaction
+		Alias            string         `json:"alias"`       // alias name
+		URI              string         `json:"uri"`         // alias assignment
+		EscrowID         string         `json:"escrowId"`
+		Decision         string         `json:"decision"` // escrow sign and result
+		SubscriptionID   string         `json:"subscriptionId"`
+		Goods            string         `json:"goods"`
+		Quantity         uint64         `json:"quantity"` // purchased goods
+		Purchase         string         `json:"purchase"` // delivered, refunded or commented purchase
+		RefundNQT        uint64         `json:"refundNQT"`
+		DiscountNQT      uint64         `json:"discountNQT"`
+		DeliveryDeadline int64          `json:"deliveryDeadlineTimestamp"`
+		Period           uint64         `json:"period"` // effective balance leasing, in blocks
 		// VersionMultiOutCreation          byte           `json:"version.MultiOutCreation"`
 		// VersionCommitmentAdd             byte           `json:"version.CommitmentAdd"`
 		// VersionRewardRecipientAssignment byte           `json:"version.RewardRecipientAssignment"`
@@ -37,12 +55,10 @@ type Transaction struct {
 	// Signature       string             `json:"signature"`
 	// SignatureHash   string             `json:"signatureHash"`
 	// FullHash        string             `json:"fullHash"`
-	// Deadline        uint64             `json:"deadline"`
 	// SenderPublicKey string             `json:"senderPublicKey"`
 	// Version        uint64 `json:"version"`
 	// EcBlockId      uint64 `json:"ecBlockId,string"`
 	// EcBlockHeight  uint64 `json:"ecBlockHeight"`
-	// Block          uint64 `json:"block,string"`
 	// Confirmations  uint64 `json:"confirmations"`
 	// BlockTimestamp int64 `json:"blockTimestamp"`
 }
@@ -80,10 +96,40 @@ func (r *RecipientsType) foundMyAmountNQT(account string) uint64 {
 	return 0
 }
 
+// GetRecipients works for both multi-out ([[id, amount], ...]) and multi-out same ([id, ...]) attachments
+func (r *RecipientsType) GetRecipients() []string {
+	recipients := make([]string, 0, len(*r))
+	for _, v := range *r {
+		switch recipient := v.(type) {
+		case string:
+			recipients = append(recipients, recipient)
+		case []interface{}:
+			if len(recipient) > 0 {
+				if account, ok := recipient[0].(string); ok {
+					recipients = append(recipients, account)
+				}
+			}
+		}
+	}
+	return recipients
+}
+
 func (t *Transaction) GetAmountNQT() uint64 {
 	return t.AmountNQT
 }
 
+// GetQuantityQNT returns the token quantity of the asset transfer or order
+func (t *Transaction) GetQuantityQNT() uint64 {
+	quantity, _ := strconv.ParseUint(t.Attachment.QuantityQNT.String(), 10, 64)
+	return quantity
+}
+
+// GetPriceNQT returns the order price in NQT per QNT or the alias sale price in NQT
+func (t *Transaction) GetPriceNQT() uint64 {
+	price, _ := strconv.ParseUint(t.Attachment.PriceNQT.String(), 10, 64)
+	return price
+}
+
 func (t *Transaction) GetAmount() float64 {
 	return float64(t.GetAmountNQT()) / 1e8
 }
@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"gorm.io/gorm"
+)
+
+const maxCombinedMessageLength = 4000 // telegram limit is 4096 characters
+
+type notificationGroup struct {
+	header        string
+	notifications []*models.Notification
+}
+
+// getQuietUsers returns users having quiet hours by chat id
+func (bot *TelegramBot) getQuietUsers(chatIDs []int64) map[int64]*models.DbUser {
+	var quietUsers = make(map[int64]*models.DbUser)
+	if len(chatIDs) == 0 {
+		return quietUsers
+	}
+
+	var dbUsers []models.DbUser
+	err := bot.db.Where("chat_id IN ? AND quiet_from <> quiet_to", chatIDs).Find(&dbUsers).Error
+	if err != nil {
+		bot.logger.Errorf("Can't get quiet hours of users: %v", err)
+		return quietUsers
+	}
+	for i := range dbUsers {
+		quietUsers[dbUsers[i].ChatID] = &dbUsers[i]
+	}
+	return quietUsers
+}
+
+// getQuietEnd returns the end of the current quiet hours or zero time if the user isn't in quiet hours now
+func getQuietEnd(dbUser *models.DbUser, now time.Time) time.Time {
+	if dbUser == nil {
+		return time.Time{}
+	}
+	_, end := common.GetQuietPeriod(now, dbUser.QuietFrom, dbUser.QuietTo, dbUser.QuietTimezone)
+	if now.Before(end) {
+		return end
+	}
+	return time.Time{}
+}
+
+// coalesceNotifications combines notifications queued during quiet hours and bursts of notifications for the same account,
+// only never sent notifications having a batch key are combined
+func (bot *TelegramBot) coalesceNotifications(now time.Time) {
+	var notifications []models.Notification
+	err := bot.db.Where("status = ? AND batch_key <> '' AND message_id = 0 AND attempts = 0", models.NOTIFICATION_PENDING).
+		Order("id").Find(&notifications).Error
+	if err != nil {
+		bot.logger.Errorf("Can't get notifications to combine: %v", err)
+		return
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	var chatIDs []int64
+	var chatNotifications = make(map[int64][]*models.Notification)
+	for i := range notifications {
+		notification := &notifications[i]
+		if _, ok := chatNotifications[notification.ChatID]; !ok {
+			chatIDs = append(chatIDs, notification.ChatID)
+		}
+		chatNotifications[notification.ChatID] = append(chatNotifications[notification.ChatID], notification)
+	}
+	quietUsers := bot.getQuietUsers(chatIDs)
+
+	var cutoff = now.Add(-bot.outboxConfig.CoalesceWindow)
+	for _, chatID := range chatIDs {
+		dbUser := quietUsers[chatID]
+		if !getQuietEnd(dbUser, now).IsZero() {
+			continue // will be combined once quiet hours are over
+		}
+
+		var quietGroup = notificationGroup{}
+		var accountGroups []*notificationGroup
+		var groupByKey = make(map[string]*notificationGroup)
+		for _, notification := range chatNotifications[chatID] {
+			if dbUser != nil {
+				start, end := common.GetQuietPeriod(now, dbUser.QuietFrom, dbUser.QuietTo, dbUser.QuietTimezone)
+				if !notification.CreatedAt.Before(start) && notification.CreatedAt.Before(end) {
+					quietGroup.notifications = append(quietGroup.notifications, notification)
+					continue
+				}
+			}
+
+			group := groupByKey[notification.BatchKey]
+			if group == nil {
+				group = &notificationGroup{}
+				groupByKey[notification.BatchKey] = group
+				accountGroups = append(accountGroups, group)
+			}
+			group.notifications = append(group.notifications, notification)
+		}
+
+		if len(quietGroup.notifications) > 1 {
+			quietGroup.header = fmt.Sprintf("🔕 <b>%v notifications during quiet hours</b>", len(quietGroup.notifications))
+			bot.combineNotifications(&quietGroup)
+		}
+		for _, group := range accountGroups {
+			// the burst is combined once its first notification is out of the window, the rest are included too
+			if len(group.notifications) < bot.outboxConfig.CoalesceCount || group.notifications[0].CreatedAt.After(cutoff) {
+				continue
+			}
+			group.header = fmt.Sprintf("📦 <b>%v notifications for %v</b>", len(group.notifications), group.notifications[0].BatchKey)
+			bot.combineNotifications(group)
+		}
+	}
+}
+
+// combineNotifications replaces the group by new notifications split to fit the telegram message limit
+func (bot *TelegramBot) combineNotifications(group *notificationGroup) {
+	var messages []string
+	var message string
+	var ids = make([]uint, 0, len(group.notifications))
+	for _, notification := range group.notifications {
+		ids = append(ids, notification.ID)
+		if message != "" && len(message)+len(notification.Message)+2 > maxCombinedMessageLength {
+			messages = append(messages, message)
+			message = ""
+		}
+		if message != "" {
+			message += "\n\n"
+		}
+		message += notification.Message
+	}
+	messages = append(messages, message)
+
+	var first = group.notifications[0]
+	err := bot.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Notification{}).
+			Where("id IN ? AND status = ? AND message_id = 0", ids, models.NOTIFICATION_PENDING).
+			Update("status", models.NOTIFICATION_COMBINED)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(ids)) {
+			return fmt.Errorf("some of notifications have been changed in the meantime")
+		}
+
+		for i, message := range messages {
+			var header = group.header
+			if len(messages) > 1 {
+				header += fmt.Sprintf(" (%v/%v)", i+1, len(messages))
+			}
+			if err := tx.Create(models.NewNotification(first.ChatID, first.UserName, header+"\n\n"+message)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		bot.logger.Errorf("Can't combine %v notifications for user %v (Chat.ID %v): %v", len(ids), first.UserName, first.ChatID, err)
+		return
+	}
+	bot.logger.Infof("Combined %v notifications for user %v (Chat.ID %v) into %v", len(ids), first.UserName, first.ChatID, len(messages))
+}
@@ -1,6 +1,9 @@
 package common
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 const GENESIS_BLOCK_TIME = 1407722400
 
@@ -8,6 +11,10 @@ func ChainTimeToTime(chainTime int64) time.Time {
 	return time.Unix(GENESIS_BLOCK_TIME+chainTime, 0)
 }
 
+func TimeToChainTime(t time.Time) int64 {
+	return t.Unix() - GENESIS_BLOCK_TIME
+}
+
 func FormatChainTimeToStringDatetimeUTC(chainTime int64) string {
 	return ChainTimeToTime(chainTime).UTC().Format("2006-01-02 15:04")
 }
@@ -19,3 +26,14 @@ func FormatChainTimeToStringTimeUTC(chainTime int64) string {
 func FormatChainTimeToStringDate(chainTime int64) string {
 	return ChainTimeToTime(chainTime).UTC().Format("2006-01-02")
 }
+
+func FormatPeriod(period time.Duration) string {
+	switch {
+	case period%(24*time.Hour) == 0:
+		return fmt.Sprintf("%vd", int(period/(24*time.Hour)))
+	case period%time.Hour == 0:
+		return fmt.Sprintf("%vh", int(period/time.Hour))
+	default:
+		return fmt.Sprintf("%vm", int(period/time.Minute))
+	}
+}
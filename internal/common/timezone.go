@@ -0,0 +1,26 @@
+package common
+
+import "time"
+
+// LoadLocation returns UTC if the timezone is empty or unknown
+func LoadLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// GetQuietPeriod returns the last quiet period started not after now, it is still active if now is before the end
+func GetQuietPeriod(now time.Time, fromHour, toHour int, timezone string) (start time.Time, end time.Time) {
+	now = now.In(LoadLocation(timezone))
+	start = time.Date(now.Year(), now.Month(), now.Day(), fromHour, 0, 0, 0, now.Location())
+	if start.After(now) {
+		start = start.AddDate(0, 0, -1)
+	}
+	end = start.Add(time.Duration((toHour-fromHour+24)%24) * time.Hour)
+	return start, end
+}
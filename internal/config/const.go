@@ -9,42 +9,75 @@ const NAME = "<b>🚀 Signum Explorer Bot</b>"
 const VERSION = "<i>v.1.9.0</i>"
 
 const (
-	COMMAND_START     = "/start"
-	COMMAND_ADD       = "/add"
-	COMMAND_DEL       = "/del"
-	COMMAND_PRICE     = "/price"
-	COMMAND_CALC      = "/calc"
-	COMMAND_CONVERT   = "/convert"
-	COMMAND_NETWORK   = "/network"
-	COMMAND_CROSSING  = "/crossing"
-	COMMAND_FAUCET    = "/faucet"
-	COMMAND_THRESHOLD = "/threshold"
-	COMMAND_INFO      = "/info"
-	COMMAND_P         = "/p"
-	COMMAND_C         = "/c"
-	COMMAND_PC        = "/pc"
+	COMMAND_START         = "/start"
+	COMMAND_ADD           = "/add"
+	COMMAND_DEL           = "/del"
+	COMMAND_PRICE         = "/price"
+	COMMAND_CALC          = "/calc"
+	COMMAND_CONVERT       = "/convert"
+	COMMAND_NETWORK       = "/network"
+	COMMAND_CROSSING      = "/crossing"
+	COMMAND_FAUCET        = "/faucet"
+	COMMAND_THRESHOLD     = "/threshold"
+	COMMAND_RULES         = "/rules"
+	COMMAND_ALERT         = "/alert"
+	COMMAND_ALERTS        = "/alerts"
+	COMMAND_MINING        = "/mining"
+	COMMAND_BLOCKS        = "/blocks"
+	COMMAND_DIGEST        = "/digest"
+	COMMAND_QUIET         = "/quiet"
+	COMMAND_WEBHOOK       = "/webhook"
+	COMMAND_POOL          = "/pool"
+	COMMAND_EXPORT        = "/export"
+	COMMAND_PORTFOLIO     = "/portfolio"
+	COMMAND_TOKEN         = "/token"
+	COMMAND_AT            = "/at"
+	COMMAND_ALIAS         = "/alias"
+	COMMAND_SUBSCRIPTIONS = "/subscriptions"
+	COMMAND_ESCROWS       = "/escrows"
+	COMMAND_INFO          = "/info"
+	COMMAND_P             = "/p"
+	COMMAND_C             = "/c"
+	COMMAND_PC            = "/pc"
 )
 
 const (
-	BUTTON_PRICES  = "💵 Price"
-	BUTTON_NETWORK = "💻 Network"
-	BUTTON_CALC    = "📃 Calc"
-	BUTTON_CONVERT = "💱 Convert"
-	BUTTON_INFO    = "ℹ Info"
-	BUTTON_BACK    = "⬅ Back"
-	BUTTON_REFRESH = "↪ Refresh"
-	BUTTON_NEXT    = "Next ⏩"
-	BUTTON_PREV    = "⏪ Prev"
+	BUTTON_PRICES    = "💵 Price"
+	BUTTON_NETWORK   = "💻 Network"
+	BUTTON_CALC      = "📃 Calc"
+	BUTTON_CONVERT   = "💱 Convert"
+	BUTTON_INFO      = "ℹ Info"
+	BUTTON_BACK      = "⬅ Back"
+	BUTTON_REFRESH   = "↪ Refresh"
+	BUTTON_NEXT      = "Next ⏩"
+	BUTTON_PREV      = "⏪ Prev"
+	BUTTON_PORTFOLIO = "💼 Portfolio"
 )
 
 const INSTRUCTION_TEXT = `
 Send any <b>Signum Account</b> (S-XXXX-XXXX-XXXX-XXXXX or numeric ID) to explore it once.
 Send <b>` + COMMAND_ADD + ` ACCOUNT [ALIAS]</b> to constantly add an account into your main menu and <b>` + COMMAND_DEL + ` [ACCOUNT or ALIAS]</b> to remove it from there.
+Send <b>` + COMMAND_PORTFOLIO + `</b> to get the total balance and tokens of all accounts from your menu.
 Send <b>` + COMMAND_THRESHOLD + ` [AMOUNT of SIGNA]</b> to set a lower threshold for notifications.
+Payments and token transfers are notified as pending from the mempool, other transactions only once confirmed.
+Send <b>` + COMMAND_RULES + ` ACCOUNT</b> to set up notification rules of an account from your menu.
+Send <b>` + COMMAND_DIGEST + `</b> to get a daily or weekly digest of your accounts.
+Send <b>` + COMMAND_QUIET + ` FROM-TO [TIMEZONE]</b> to hold notifications back during quiet hours.
+Send <b>` + COMMAND_WEBHOOK + ` URL</b> to also receive notifications as signed JSON POST requests.
+Send <b>` + COMMAND_EXPORT + ` ACCOUNT [FROM] [TO] [csv|json]</b> to get the full history of an account with USD and BTC values.
+Send <b>` + COMMAND_ALERT + `</b> to set up SIGNA price alerts and <b>` + COMMAND_ALERTS + `</b> to list them.
 Send <b>` + COMMAND_CALC + ` TiB COMMITMENT</b> (or just <b>` + COMMAND_CALC + ` TiB</b>) to calculate your expected mining rewards.
+Send <b>` + COMMAND_MINING + `</b> to set up network difficulty and commitment alerts.
+Send <b>` + COMMAND_BLOCKS + ` stats [ACCOUNT]</b> to get the forging statistic and luck of your accounts.
 Send <b>` + COMMAND_PRICE + `</b> to get up-to-date currency quotes.
 Send <b>` + COMMAND_CONVERT + `</b> for currency converter SIGNA / USD / BTC
 Send <b>` + COMMAND_NETWORK + `</b> to get Signum Network statistic.
+Send <b>` + COMMAND_POOL + ` [POOL]</b> to get a pool dashboard: miners, forged blocks, payouts and balance.
+Send <b>` + COMMAND_TOKEN + ` ASSET_ID_OR_NAME</b> to explore a token and <b>` + COMMAND_TOKEN + `</b> to watch tokens for price and large transfer alerts.
+Send <b>` + COMMAND_AT + ` AT_ID</b> to explore a smart contract: state, balance, decoded data and recent transactions.
+Send <b>` + COMMAND_ALIAS + ` NAME</b> to resolve an alias to its account or URI, with the owner, sale price and history.
+Send <b>` + COMMAND_SUBSCRIPTIONS + ` ACCOUNT</b> to list active incoming and outgoing subscriptions with their next renewals.
+Send <b>` + COMMAND_ESCROWS + ` ACCOUNT</b> to list unresolved escrows with their deadlines and signers.
 Send <b>` + COMMAND_CROSSING + `</b> to check your plots crossing (they should not overlap to maximize mining profit).
 Send <b>` + COMMAND_FAUCET + `</b> to get some free SIGNA.
 Send <b>` + COMMAND_INFO + `</b> for information.
@@ -60,16 +93,19 @@ const (
 	WEEK  = 7 * DAY
 	MONTH = 30 * DAY
 	ALL   = 100 * 12 * MONTH
+
+	BLOCK_TIME = 4 * time.Minute // target time between blocks
 )
 
 const (
 	DB_CONFIG_ORDINARY_FAUCET_AMOUNT = "ORDINARY_FAUCET_AMOUNT"
 	DB_CONFIG_NEW_USERS_EXTRA_FAUCET = "NEW_USERS_EXTRA_FAUCET"
 	DB_CONFIG_EXTRA_FAUCET_AMOUNT    = "EXTRA_FAUCET_AMOUNT"
+	DB_CONFIG_NOTIFIER_LAST_HEIGHT   = "NOTIFIER_LAST_SCANNED_HEIGHT"
+	DB_CONFIG_NOTIFIER_AP_HEIGHT     = "NOTIFIER_ADVANCED_PAYMENTS_HEIGHT"
 )
 
 const FAUCET_ACCOUNT = "S-8N2F-TDD7-4LY6-64FZ7"
 const FAUCET_DAYS_PERIOD = 7
 
 var ValidAccount = regexp.MustCompile(`^[0-9]{1,}$`)
-var ValidAccountRS = regexp.MustCompile(`^(S|BURST)-[A-Z0-9]{4}-[A-Z0-9]{4}-[A-Z0-9]{4}-[A-Z0-9]{5}$`)
@@ -0,0 +1,92 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+)
+
+// Field is a readable value decoded from the machine data
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Decoder renders the machine data of a known AT type
+type Decoder interface {
+	TypeName() string
+	Decode(at *signumapi.ATDetails) []Field
+}
+
+var decoders = struct {
+	sync.RWMutex
+	byCodeHash map[string]Decoder
+}{byCodeHash: make(map[string]Decoder)}
+
+// RegisterDecoder plugs the decoder in for all ATs with the machine code hash,
+// the hash is the same for every deployment of the same contract
+func RegisterDecoder(machineCodeHashID string, decoder Decoder) {
+	decoders.Lock()
+	decoders.byCodeHash[machineCodeHashID] = decoder
+	decoders.Unlock()
+}
+
+// GetDecoder returns the decoder of the AT type, unknown types get the raw variables decoder
+func GetDecoder(at *signumapi.ATDetails) Decoder {
+	decoders.RLock()
+	decoder := decoders.byCodeHash[at.MachineCodeHashId]
+	decoders.RUnlock()
+	if decoder == nil {
+		return &RawDecoder{MaxVariables: rawMaxVariables}
+	}
+	return decoder
+}
+
+const rawMaxVariables = 16
+
+// RawDecoder lists non-zero variables of an unknown AT type
+type RawDecoder struct {
+	MaxVariables int
+}
+
+func (d *RawDecoder) TypeName() string {
+	return "Unknown type"
+}
+
+func (d *RawDecoder) Decode(at *signumapi.ATDetails) []Field {
+	var fields []Field
+	for i, variable := range at.GetVariables() {
+		if variable == 0 {
+			continue
+		}
+		if len(fields) == d.MaxVariables {
+			break
+		}
+		fields = append(fields, Field{Name: fmt.Sprintf("var %v", i), Value: fmt.Sprint(variable)})
+	}
+	return fields
+}
+
+// DecodeMessage returns the text of the hex encoded AT message without the zero padding of its pages,
+// binary messages give an empty string
+func DecodeMessage(message string) string {
+	decoded, err := hex.DecodeString(message)
+	if err != nil {
+		return ""
+	}
+	decoded = bytes.TrimRight(decoded, "\x00")
+	if !utf8.Valid(decoded) {
+		return ""
+	}
+	for _, r := range string(decoded) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return ""
+		}
+	}
+	return string(decoded)
+}
@@ -0,0 +1,40 @@
+package contracts
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+)
+
+func TestRegisteredDecoder(t *testing.T) {
+	RegisterDecoder("123", &VariablesDecoder{Name: "Test", Variables: []Variable{
+		{Name: "Goal", Index: 1, Kind: VK_AMOUNT},
+		{Name: "Deadline", Index: 0, Kind: VK_HEIGHT},
+		{Name: "Missing", Index: 5, Kind: VK_NUMBER},
+	}})
+
+	// var 0 = 1000, var 1 = 2 SIGNA
+	at := &signumapi.ATDetails{MachineCodeHashId: "123", MachineData: "e803000000000000" + "00c2eb0b00000000"}
+	decoder := GetDecoder(at)
+	if decoder.TypeName() != "Test" {
+		t.Fatalf("expected the registered decoder, got %v", decoder.TypeName())
+	}
+	fields := decoder.Decode(at)
+	if len(fields) != 2 || fields[0].Value != "2.00 SIGNA" || fields[1].Value != "#1000" {
+		t.Fatalf("unexpected fields %+v", fields)
+	}
+
+	if _, ok := GetDecoder(&signumapi.ATDetails{MachineCodeHashId: "456"}).(*RawDecoder); !ok {
+		t.Fatalf("unknown types must get the raw decoder")
+	}
+}
+
+func TestDecodeMessage(t *testing.T) {
+	if message := DecodeMessage(hex.EncodeToString([]byte("hello\x00\x00\x00"))); message != "hello" {
+		t.Fatalf("expected the text without padding, got %q", message)
+	}
+	if message := DecodeMessage("0001ff"); message != "" {
+		t.Fatalf("binary messages must be empty, got %q", message)
+	}
+}
@@ -0,0 +1,30 @@
+package contracts
+
+import (
+	"encoding/json"
+
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// LoadDecoders registers the variables decoder of every contract type stored in DB
+func LoadDecoders(logger *zap.SugaredLogger, db *gorm.DB) {
+	var contractTypes []models.ContractType
+	if err := db.Find(&contractTypes).Error; err != nil {
+		logger.Errorf("Can't get contract types: %v", err)
+		return
+	}
+
+	var loaded int
+	for _, contractType := range contractTypes {
+		var variables []Variable
+		if err := json.Unmarshal([]byte(contractType.Variables), &variables); err != nil {
+			logger.Errorf("Bad variables of the contract type %v: %v", contractType.Name, err)
+			continue
+		}
+		RegisterDecoder(contractType.MachineCodeHashID, &VariablesDecoder{Name: contractType.Name, Variables: variables})
+		loaded++
+	}
+	logger.Infof("Loaded %v contract types from DB", loaded)
+}
@@ -0,0 +1,65 @@
+package contracts
+
+import (
+	"fmt"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/rsaddress"
+)
+
+type VariableKind string
+
+const (
+	VK_NUMBER    VariableKind = "number"
+	VK_AMOUNT    VariableKind = "amount"    // NQT
+	VK_ACCOUNT   VariableKind = "account"   // account ID
+	VK_TIMESTAMP VariableKind = "timestamp" // chain time
+	VK_HEIGHT    VariableKind = "height"    // block height
+)
+
+// Variable describes one variable of the machine data
+type Variable struct {
+	Name  string       `json:"name"`
+	Index int          `json:"index"`
+	Kind  VariableKind `json:"kind"`
+}
+
+// VariablesDecoder is the declarative decoder for contracts which keep their state in fixed variables,
+// new AT types are plugged in as data without a decoder of their own
+type VariablesDecoder struct {
+	Name      string
+	Variables []Variable
+}
+
+func (d *VariablesDecoder) TypeName() string {
+	return d.Name
+}
+
+func (d *VariablesDecoder) Decode(at *signumapi.ATDetails) []Field {
+	variables := at.GetVariables()
+	fields := make([]Field, 0, len(d.Variables))
+	for _, variable := range d.Variables {
+		if variable.Index >= len(variables) {
+			continue
+		}
+		fields = append(fields, Field{Name: variable.Name, Value: formatVariable(variables[variable.Index], variable.Kind)})
+	}
+	return fields
+}
+
+func formatVariable(value uint64, kind VariableKind) string {
+	switch kind {
+	case VK_AMOUNT:
+		return common.FormatNQT(value) + " SIGNA"
+	case VK_ACCOUNT:
+		return rsaddress.ToRS(value)
+	case VK_TIMESTAMP:
+		// AT timestamps keep the height in the higher 32 bits and the transaction index in the lower ones
+		return fmt.Sprintf("block #%v", value>>32)
+	case VK_HEIGHT:
+		return fmt.Sprintf("#%v", value)
+	default:
+		return fmt.Sprint(int64(value))
+	}
+}
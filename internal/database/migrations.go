@@ -14,5 +14,23 @@ func autoMigrate(db *gorm.DB) {
 		&models.Faucet{},
 		&models.Donation{},
 		&models.Config{},
+		&models.Notification{},
+		&models.WebhookDelivery{},
+		&models.PendingTransaction{},
+		&models.NotificationRule{},
+		&models.AccountBalance{},
+		&models.PriceAlert{},
+		&models.MiningAlert{},
+		&models.DigestStat{},
+		&models.ForgedBlock{},
+		&models.AccountWatchdog{},
+		&models.BigWallet{},
+		&models.PoolMiner{},
+		&models.PoolBlock{},
+		&models.PoolPayout{},
+		&models.PortfolioSnapshot{},
+		&models.AssetPrice{},
+		&models.TokenWatch{},
+		&models.ContractType{},
 	)
 }
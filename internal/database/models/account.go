@@ -12,18 +12,8 @@ type DbAccount struct {
 	Alias                    string `gorm:"type:varchar(255)"`
 	NotifyIncomeTransactions bool
 	NotifyOutgoTransactions  bool
-	LastTransactionID        string `gorm:"type:varchar(255)"`
-	LastTransactionH         uint64
 	NotifyNewBlocks          bool
-	LastBlockID              string `gorm:"type:varchar(255)"`
-	LastBlockH               uint64
 	NotifyOtherTXs           bool
-	LastMiningTX             string `gorm:"type:varchar(255)"`
-	LastMiningH              uint64
-	LastMessageTX            string `gorm:"type:varchar(255)"`
-	LastMessageH             uint64
-	LastATPaymentTX          string `gorm:"type:varchar(255)"`
-	LastATPaymentH           uint64
-	LastTokenizationTX       string `gorm:"type:varchar(255)"`
-	LastTokenizationH        uint64
+	BalanceFloorNQT          uint64  // alert when the available balance drops below, 0 is disabled
+	BalanceChangePercent     float64 // alert when the available balance changes by more than this between notifier runs, 0 is disabled
 }
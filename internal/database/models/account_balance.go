@@ -0,0 +1,14 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// AccountBalance is the last available balance observed by the notifier, it is kept apart from DbAccount
+// which is entirely saved by the users package
+type AccountBalance struct {
+	gorm.Model
+	DbAccountID         uint `gorm:"uniqueIndex"`
+	AvailableBalanceNQT uint64
+	BelowFloor          bool // the low balance alert has been sent, it is sent again after the balance recovers
+}
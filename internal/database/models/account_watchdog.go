@@ -0,0 +1,14 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// AccountWatchdog is the last reward recipient and commitment observed by the notifier,
+// any change of them is reported as an urgent alert
+type AccountWatchdog struct {
+	gorm.Model
+	DbAccountID         uint   `gorm:"uniqueIndex"`
+	RewardRecipient     string `gorm:"type:varchar(255)"`
+	CommittedBalanceNQT uint64
+}
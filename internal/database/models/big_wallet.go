@@ -0,0 +1,13 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// BigWallet is a known pool or exchange, the list is editable in DB and reloaded by the pool collector
+type BigWallet struct {
+	gorm.Model
+	Account string `gorm:"type:varchar(255);uniqueIndex"`
+	Name    string `gorm:"type:varchar(255)"`
+	Pool    bool   // the pool collector gathers statistic only for pools
+}
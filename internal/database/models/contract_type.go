@@ -0,0 +1,13 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// ContractType describes the machine data of a known AT type, the list is editable in DB and loaded on start
+type ContractType struct {
+	gorm.Model
+	MachineCodeHashID string `gorm:"type:varchar(32);uniqueIndex"` // the same for every deployment of the contract
+	Name              string `gorm:"type:varchar(255)"`
+	Variables         string `gorm:"type:text"` // JSON list of {"name", "index", "kind"}, kinds: number, amount, account, timestamp, height
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DigestPeriod string
+
+const (
+	DIGEST_DAILY  DigestPeriod = "daily"
+	DIGEST_WEEKLY DigestPeriod = "weekly"
+)
+
+// DigestStat accumulates account activity since PeriodStart, it is reset once the digest is sent
+type DigestStat struct {
+	gorm.Model
+	DbAccountID     uint `gorm:"uniqueIndex"`
+	PeriodStart     time.Time
+	StartBalanceNQT uint64
+	IncomeNQT       uint64
+	IncomeCount     uint
+	OutgoNQT        uint64
+	OutgoCount      uint
+	FeesNQT         uint64
+	BlocksForged    uint
+	BlockRewardsNQT uint64
+	ATIncomeNQT     uint64
+	TokenIncomeNQT  uint64
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ForgedBlock is a block forged by a monitored account, the history is kept for the forging statistic
+type ForgedBlock struct {
+	gorm.Model
+	Account        string `gorm:"type:varchar(255);index:unique_forged_block,unique"`
+	Height         uint64 `gorm:"index:unique_forged_block,unique"`
+	BlockRewardNQT uint64
+	FeesNQT        uint64
+	ForgedAt       time.Time `gorm:"index"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// MiningAlert keeps the user's plot for rewards recalculation and the network alert thresholds,
+// Last* fields are written by the network info listener only
+type MiningAlert struct {
+	gorm.Model
+	DbUserID             uint `gorm:"uniqueIndex"`
+	PlotSizeTiB          float64
+	Commitment           float64 // SIGNA
+	DifficultyPiB        float64 // alert when the network difficulty crosses it, 0 is disabled
+	CommitmentPercent    float64 // alert when the network commitment per TiB changes by this since the last alert, 0 is disabled
+	LastDifficultyPiB    float64
+	LastCommitmentPerTiB float64 // the network commitment of the last alert
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationStatus string
+
+const (
+	NOTIFICATION_PENDING  NotificationStatus = "pending"
+	NOTIFICATION_SENT     NotificationStatus = "sent"
+	NOTIFICATION_FAILED   NotificationStatus = "failed"
+	NOTIFICATION_COMBINED NotificationStatus = "combined" // delivered as a part of another notification
+)
+
+type Notification struct {
+	gorm.Model
+	ChatID        int64              `gorm:"type:bigint;index"`
+	UserName      string             `gorm:"type:varchar(255)"`
+	Message       string             `gorm:"type:text"`
+	Status        NotificationStatus `gorm:"type:varchar(16);index:idx_notification_queue,priority:1"`
+	Attempts      uint
+	NextAttemptAt time.Time `gorm:"index:idx_notification_queue,priority:2"`
+	LastError     string    `gorm:"type:text"`
+	SentAt        *time.Time
+	MessageID     int // telegram message id, the message is edited if the notification becomes pending again
+
+	BatchKey string `gorm:"type:varchar(64)"` // notifications with the same key could be combined into one, empty if never
+	Urgent   bool   // delivered during quiet hours too
+}
+
+// NewNotification returns a notification ready to be delivered by the outbox
+func NewNotification(chatID int64, userName, message string) *Notification {
+	return &Notification{
+		ChatID:        chatID,
+		UserName:      userName,
+		Message:       message,
+		Status:        NOTIFICATION_PENDING,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+type EventType string
+
+// NotificationEvent is the structured data of the rendered message, it's posted to the webhook
+type NotificationEvent struct {
+	Type          EventType `json:"type"`
+	Account       string    `json:"account"`
+	AccountRS     string    `json:"account_rs,omitempty"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	AmountNQT     int64     `json:"amount_nqt"` // negative for outgo
+	Height        uint64    `json:"height,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+type RuleKind string
+
+const (
+	RULE_AMOUNT   RuleKind = "amount"   // amount is in range [MinAmountNQT, MaxAmountNQT]
+	RULE_ALLOW    RuleKind = "allow"    // counterparty is Account
+	RULE_DENY     RuleKind = "deny"     // counterparty is not Account
+	RULE_TYPE     RuleKind = "type"     // transaction type is TxType (and TxSubtype if it isn't -1)
+	RULE_CONTAINS RuleKind = "contains" // message contains Text
+)
+
+const RULE_TEXT_MAX_LENGTH = 255 // characters of Text
+
+// NotificationRule filters account notifications, rules of different kinds are combined by AND and rules of the same kind by OR
+type NotificationRule struct {
+	gorm.Model
+	DbAccountID  uint     `gorm:"index"`
+	Kind         RuleKind `gorm:"type:varchar(16)"`
+	MinAmountNQT uint64
+	MaxAmountNQT uint64 // 0 is unlimited
+	Account      string `gorm:"type:varchar(255)"`
+	AccountRS    string `gorm:"type:varchar(255)"`
+	TxType       int
+	TxSubtype    int
+	Text         string `gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PendingTransaction is an unconfirmed transaction which has been already notified, its notification will be edited with the result
+type PendingTransaction struct {
+	gorm.Model
+	TransactionID  string `gorm:"type:varchar(255);index:unique_pending_tx_per_chat,unique"`
+	ChatID         int64  `gorm:"type:bigint;index:unique_pending_tx_per_chat,unique"`
+	Account        string `gorm:"type:varchar(255)"`
+	NotificationID uint
+	Message        string `gorm:"type:text"`
+	ExpiresAt      time.Time
+	MissedScans    uint  // the transaction isn't found neither in the mempool nor in the chain
+	AmountNQT      int64 // negative for outgo, it is reported to sinks with the result
+}
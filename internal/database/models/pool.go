@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PoolMiner is an account assigned to the pool (reward recipient), the list is replaced by every collection
+type PoolMiner struct {
+	gorm.Model
+	Pool    string `gorm:"type:varchar(255);index"`
+	Account string `gorm:"type:varchar(255)"`
+}
+
+// PoolBlock is a block forged by a miner of the pool
+type PoolBlock struct {
+	gorm.Model
+	Pool      string    `gorm:"type:varchar(255);index:unique_pool_block,unique"`
+	Height    uint64    `gorm:"index:unique_pool_block,unique"`
+	Generator string    `gorm:"type:varchar(255)"`
+	RewardNQT uint64    // block reward plus fees
+	ForgedAt  time.Time `gorm:"index"`
+}
+
+// PoolPayout is a multi-out payment sent by the pool
+type PoolPayout struct {
+	gorm.Model
+	Pool          string `gorm:"type:varchar(255);index:unique_pool_payout,unique"`
+	TransactionID string `gorm:"type:varchar(255);index:unique_pool_payout,unique"`
+	AmountNQT     uint64
+	Recipients    int
+	PaidAt        time.Time `gorm:"index"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// PortfolioSnapshot is the summary of all menu accounts of a user, it is stored periodically for the portfolio chart
+type PortfolioSnapshot struct {
+	gorm.Model
+	DbUserID            uint `gorm:"index"`
+	AvailableBalanceNQT uint64
+	CommittedBalanceNQT uint64
+	TotalBalanceNQT     uint64
+	TokensValueNQT      uint64  // by the last trade prices
+	SignaPrice          float64 // USD
+	BtcPrice            float64 // USD
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PriceAlertKind string
+
+const (
+	PRICE_ALERT_ABOVE  PriceAlertKind = "above"  // price is above Value
+	PRICE_ALERT_BELOW  PriceAlertKind = "below"  // price is below Value
+	PRICE_ALERT_CHANGE PriceAlertKind = "change" // price has changed by Value percent in Period
+)
+
+type PriceAlert struct {
+	gorm.Model
+	DbUserID    uint           `gorm:"index"`
+	Kind        PriceAlertKind `gorm:"type:varchar(16)"`
+	Currency    string         `gorm:"type:varchar(8)"` // USD or BTC
+	Value       float64
+	Period      time.Duration
+	Recurring   bool // one-shot alerts are deleted once triggered
+	Triggered   bool // recurring alert is sent again only after the condition becomes false
+	TriggeredAt *time.Time
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// AssetPrice is the sampled last trade price of a watched token
+type AssetPrice struct {
+	gorm.Model
+	Asset    string  `gorm:"type:varchar(32);index"`
+	PriceNQT float64 // per QNT
+}
+
+// TokenWatch is a token watched by the user, LastPriceNQT is written by the token manager only
+type TokenWatch struct {
+	gorm.Model
+	DbUserID     uint    `gorm:"index"`
+	Asset        string  `gorm:"type:varchar(32);index"`
+	PricePercent float64 // alert when the price changes by this since the last alert, 0 is disabled
+	TransferQNT  uint64  // alert on transfers of at least this quantity, 0 is disabled
+	LastPriceNQT float64 // the price of the last alert
+}
@@ -14,4 +14,17 @@ type DbUser struct {
 	LastFaucetClaim          time.Time
 	Accounts                 []*DbAccount
 	NotificationThresholdNQT uint64 `gorm:"type:bigint;default:1000000"`
+	Inactive                 bool   // the user has blocked the bot
+
+	DigestPeriod   DigestPeriod `gorm:"type:varchar(16)"` // empty if the digest is disabled
+	DigestHour     int          // local hour of the digest, weekly digests are sent on Monday
+	DigestTimezone string       `gorm:"type:varchar(64)"`
+	DigestOnly     bool         // instant transaction notifications are replaced by the digest
+
+	QuietFrom     int    // local hour when quiet hours start, disabled if equal to QuietTo
+	QuietTo       int    // local hour when quiet hours end
+	QuietTimezone string `gorm:"type:varchar(64)"`
+
+	WebhookURL    string `gorm:"type:varchar(512)"` // notifications are also posted there if not empty
+	WebhookSecret string `gorm:"type:varchar(64)"`  // HMAC-SHA256 key of the webhook signature
 }
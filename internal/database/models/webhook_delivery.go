@@ -0,0 +1,20 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// WebhookPayload is the JSON body, it is signed by the HMAC-SHA256 of the user secret
+type WebhookPayload struct {
+	Event     *NotificationEvent `json:"event,omitempty"`
+	ChatID    int64              `json:"chat_id"`
+	Message   string             `json:"message"` // rendered HTML
+	Timestamp int64              `json:"timestamp"`
+}
+
+// WebhookDelivery is the signed JSON payload waiting to be posted to the current webhook of the chat, it's deleted once delivered or failed
+type WebhookDelivery struct {
+	gorm.Model
+	ChatID int64  `gorm:"type:bigint;index"`
+	Body   string `gorm:"type:text"`
+}
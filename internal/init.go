@@ -10,10 +10,15 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/xDWart/signum-explorer-bot/api/geckoapi"
 	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/contracts"
 	"github.com/xDWart/signum-explorer-bot/internal/database"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
 	"github.com/xDWart/signum-explorer-bot/internal/networkinfo"
 	"github.com/xDWart/signum-explorer-bot/internal/notifier"
+	"github.com/xDWart/signum-explorer-bot/internal/pools"
+	"github.com/xDWart/signum-explorer-bot/internal/portfolio"
 	"github.com/xDWart/signum-explorer-bot/internal/prices"
+	"github.com/xDWart/signum-explorer-bot/internal/tokens"
 	"github.com/xDWart/signum-explorer-bot/internal/users"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -27,7 +32,10 @@ type TelegramBot struct {
 	usersManager        *users.Manager
 	priceManager        *prices.PriceManager
 	networkInfoListener *networkinfo.NetworkInfoListener
-	notifierCh          chan notifier.NotifierMessage
+	poolCollector       *pools.PoolCollector
+	portfolioManager    *portfolio.PortfolioManager
+	tokenManager        *tokens.TokenManager
+	outboxConfig        *OutboxConfig
 
 	overallWg               *sync.WaitGroup
 	overallShutdownChannel  chan interface{}
@@ -37,6 +45,7 @@ type TelegramBot struct {
 
 func InitTelegramBot(logger *zap.SugaredLogger) *TelegramBot {
 	db := database.NewDatabaseConnection(logger)
+	contracts.LoadDecoders(logger, db)
 
 	token := os.Getenv("EXPLORER_TELEGRAM_BOT_TOKEN")
 	if token == "" {
@@ -76,7 +85,24 @@ func InitTelegramBot(logger *zap.SugaredLogger) *TelegramBot {
 			RebuildApiClientsPeriod:   30 * time.Minute,
 			PreloadNamesForBigWallets: true,
 		})
-	priceManager := prices.NewPricesManager(logger, db, geckoClient, wg, shutdownChannel,
+	// notifier is stopped first, it may be in the middle of the block scanning
+	notifierWg := &sync.WaitGroup{}
+	notifierShutdownChannel := make(chan interface{})
+
+	// all notifications go to the telegram outbox and to the webhooks of users
+	notificationSinks := notifier.Sinks{
+		notifier.NewOutboxSink(db),
+		notifier.NewWebhookSink(logger, db, notifierWg, notifierShutdownChannel,
+			&notifier.WebhookConfig{
+				Timeout:     10 * time.Second,
+				MaxAttempts: 5,
+				RetryDelay:  5 * time.Second, // 5 attempts ~ 75 seconds
+				PollPeriod:  5 * time.Second,
+				BatchSize:   1000,
+			}),
+	}
+
+	priceManager := prices.NewPricesManager(logger, db, geckoClient, notificationSinks, wg, shutdownChannel,
 		&prices.Config{
 			SamplePeriod:      20 * time.Minute,
 			SmoothingFactor:   6, // samples for averaging
@@ -85,7 +111,7 @@ func InitTelegramBot(logger *zap.SugaredLogger) *TelegramBot {
 			DelayFuncK:        28 * time.Minute,   // kx + b: 1 week ~ 1 h between samples
 			DelayFuncB:        -136 * time.Minute, // 1 year ~ 1 week
 		})
-	networkInfoListener := networkinfo.NewNetworkInfoListener(logger, db, signumClient, wg, shutdownChannel,
+	networkInfoListener := networkinfo.NewNetworkInfoListener(logger, db, signumClient, notificationSinks, wg, shutdownChannel,
 		&networkinfo.Config{
 			SamplePeriod:          time.Hour,
 			AveragingDaysQuantity: 7,  // during 7 days
@@ -95,14 +121,43 @@ func InitTelegramBot(logger *zap.SugaredLogger) *TelegramBot {
 			DelayFuncK:            84 * time.Minute,   // kx + b: 1 week ~ 3 h between samples
 			DelayFuncB:            -408 * time.Minute, // 1 year ~ 3 week
 		})
+	poolCollector := pools.NewPoolCollector(logger, db, signumClient, wg, shutdownChannel,
+		&pools.Config{
+			CollectPeriod: 10 * time.Minute,
+			StatDays:      7,
+		})
+	portfolioManager := portfolio.NewPortfolioManager(logger, db, geckoClient, signumClient, wg, shutdownChannel,
+		&portfolio.Config{
+			SnapshotPeriod: 6 * time.Hour,
+			RetryPeriod:    30 * time.Minute,
+		})
+	tokenManager := tokens.NewTokenManager(logger, db, signumClient, notificationSinks, wg, shutdownChannel,
+		&tokens.Config{
+			SamplePeriod:      20 * time.Minute,
+			SmoothingFactor:   6, // samples for averaging
+			SaveEveryNSamples: 3, // 3 * 20 min = 1 hour
+			ScanQuantity:      20,
+			DelayFuncK:        28 * time.Minute,   // kx + b: 1 week ~ 1 h between samples
+			DelayFuncB:        -136 * time.Minute, // 1 year ~ 1 week
+			TopHolders:        10,
+			RecentTrades:      5,
+			MaxSampledTokens:  100,
+			HoldingsPeriod:    6 * time.Hour,
+		})
 
-	// we need to stop notifier first to avoid unread channel situation
-	notifierCh := make(chan notifier.NotifierMessage)
-	notifierWg := &sync.WaitGroup{}
-	notifierShutdownChannel := make(chan interface{})
-	notifier.NewNotifier(logger, db, signumClient, notifierCh, notifierWg, notifierShutdownChannel,
+	notifier.NewNotifier(logger, db, signumClient, notificationSinks, notifierWg, notifierShutdownChannel,
 		&notifier.Config{
-			NotifierPeriod: 3 * time.Minute,
+			NotifierPeriod:         3 * time.Minute,
+			Confirmations:          1,
+			MaxBlocksPerScan:       100,
+			MempoolPeriod:          30 * time.Second,
+			MempoolMissedScans:     3,
+			AdvancedPaymentsPeriod: 15 * time.Minute,
+			WatchdogPeriod:         6 * time.Hour,
+			BlockHandlers:          []notifier.BlockHandler{poolCollector.ProcessBlock},
+			ForgingStats: func(account string, plot *models.MiningAlert) string {
+				return networkinfo.FormatForgingStats(networkInfoListener.GetForgingStats(account, plot))
+			},
 		})
 
 	userManager := users.InitManager(logger, db, geckoClient, signumClient, priceManager, networkInfoListener, wg, shutdownChannel)
@@ -116,11 +171,24 @@ func InitTelegramBot(logger *zap.SugaredLogger) *TelegramBot {
 		usersManager:            userManager,
 		priceManager:            priceManager,
 		networkInfoListener:     networkInfoListener,
-		notifierCh:              notifierCh,
+		poolCollector:           poolCollector,
+		portfolioManager:        portfolioManager,
+		tokenManager:            tokenManager,
 		overallWg:               wg,
 		overallShutdownChannel:  shutdownChannel,
 		notifierWg:              notifierWg,
 		notifierShutdownChannel: notifierShutdownChannel,
+		outboxConfig: &OutboxConfig{
+			PollPeriod:     5 * time.Second,
+			BatchSize:      100,
+			MaxAttempts:    10,
+			BaseRetryDelay: 10 * time.Second, // 10 attempts ~ 1.5 hours
+			MaxRetryDelay:  time.Hour,
+			CleanupPeriod:  time.Hour,
+			KeepDelivered:  7 * 24 * time.Hour,
+			CoalesceWindow: 30 * time.Second,
+			CoalesceCount:  3,
+		},
 	}
 
 	if os.Getenv("BOT_DEBUG") == "true" {
@@ -153,6 +221,9 @@ func InitTelegramBot(logger *zap.SugaredLogger) *TelegramBot {
 		go bot.startBotListener()
 	}
 
+	bot.overallWg.Add(1)
+	go bot.startOutboxListener()
+
 	initTelegramPriceBot(logger, priceManager, wg, shutdownChannel)
 
 	return bot
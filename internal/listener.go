@@ -19,10 +19,6 @@ func (bot *TelegramBot) startBotListener() {
 			bot.logger.Infof("Telegram Bot Listener received shutdown signal")
 			return
 
-		case notifierMessage := <-bot.notifierCh:
-			bot.logger.Infof("Send notification to user %v (Chat.ID %v): %v", notifierMessage.UserName, notifierMessage.ChatID, strings.Replace(notifierMessage.Message, "\n", " ", -1))
-			bot.SendMessage(notifierMessage.ChatID, notifierMessage.Message, nil)
-
 		case update := <-bot.updates:
 			user := bot.usersManager.GetUserByChatIdFromUpdate(&update)
 			if user == nil {
@@ -68,12 +64,57 @@ func (bot *TelegramBot) startBotListener() {
 					userAnswer.MainText = bot.networkInfoListener.GetNetworkInfo()
 					userAnswer.Chart = bot.networkInfoListener.GetNetworkChart(config.MONTH)
 					userAnswer.InlineKeyboard = user.GetNetworkChartKeyboard()
+				case strings.HasPrefix(message, config.COMMAND_PORTFOLIO) || message == config.BUTTON_PORTFOLIO:
+					user.ResetState()
+					userAnswer.MainText, userAnswer.Chart = bot.portfolioManager.GetPortfolio(user.DbUser)
+				case strings.HasPrefix(message, config.COMMAND_EXPORT):
+					user.ResetState()
+					userAnswer = user.ProcessExport(message)
+				case strings.HasPrefix(message, config.COMMAND_POOL):
+					user.ResetState()
+					userAnswer.MainText = bot.poolCollector.GetPoolInfo(message)
+				case strings.HasPrefix(message, config.COMMAND_TOKEN):
+					user.ResetState()
+					userAnswer.MainText, userAnswer.Chart = bot.tokenManager.GetTokenInfo(user.DbUser, message)
+				case strings.HasPrefix(message, config.COMMAND_AT+" ") || message == config.COMMAND_AT:
+					user.ResetState()
+					userAnswer = user.ProcessAT(message)
+				case strings.HasPrefix(message, config.COMMAND_ALIAS):
+					user.ResetState()
+					userAnswer = user.ProcessAlias(message)
+				case strings.HasPrefix(message, config.COMMAND_SUBSCRIPTIONS):
+					user.ResetState()
+					userAnswer = user.ProcessSubscriptions(message)
+				case strings.HasPrefix(message, config.COMMAND_ESCROWS):
+					user.ResetState()
+					userAnswer = user.ProcessEscrows(message)
 				case strings.HasPrefix(message, config.COMMAND_CROSSING):
 					user.ResetState()
 					userAnswer.MainText = user.ProcessCrossing()
 				case strings.HasPrefix(message, config.COMMAND_THRESHOLD):
 					user.ResetState()
 					userAnswer = user.ProcessThreshold(message)
+				case strings.HasPrefix(message, config.COMMAND_RULES):
+					user.ResetState()
+					userAnswer = user.ProcessRules(message)
+				case strings.HasPrefix(message, config.COMMAND_ALERT):
+					user.ResetState()
+					userAnswer = user.ProcessAlert(message)
+				case strings.HasPrefix(message, config.COMMAND_MINING):
+					user.ResetState()
+					userAnswer = user.ProcessMining(message)
+				case strings.HasPrefix(message, config.COMMAND_BLOCKS):
+					user.ResetState()
+					userAnswer = user.ProcessBlocks(message)
+				case strings.HasPrefix(message, config.COMMAND_DIGEST):
+					user.ResetState()
+					userAnswer = user.ProcessDigest(message)
+				case strings.HasPrefix(message, config.COMMAND_QUIET):
+					user.ResetState()
+					userAnswer = user.ProcessQuiet(message)
+				case strings.HasPrefix(message, config.COMMAND_WEBHOOK):
+					user.ResetState()
+					userAnswer = user.ProcessWebhook(message)
 				case strings.HasPrefix(message, config.COMMAND_INFO) || message == config.BUTTON_INFO:
 					user.ResetState()
 					userAnswer.MainText = config.NAME + " " + config.VERSION + "\n" +
@@ -93,6 +134,15 @@ func (bot *TelegramBot) startBotListener() {
 			user.Unlock()
 
 			bot.SendAnswer(message.Chat.ID, userAnswer)
+			if userAnswer.Job != nil {
+				bot.overallWg.Add(1)
+				go bot.runJob(message.Chat.ID, userAnswer.Job)
+			}
 		}
 	}
 }
+
+func (bot *TelegramBot) runJob(chatID int64, job func(shutdownChannel chan interface{}) *users.BotMessage) {
+	defer bot.overallWg.Done()
+	bot.SendAnswer(chatID, job(bot.overallShutdownChannel))
+}
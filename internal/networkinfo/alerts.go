@@ -0,0 +1,113 @@
+package networkinfo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/calculator"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"github.com/xDWart/signum-explorer-bot/internal/notifier"
+)
+
+type userMiningAlert struct {
+	UserName string
+	ChatID   int64
+	models.MiningAlert
+}
+
+func (ni *NetworkInfoListener) checkAlerts(miningInfo signumapi.MiningInfo) {
+	var alerts []userMiningAlert
+	err := ni.db.Model(&models.DbUser{}).
+		Select("exbot_db_users.user_name, exbot_db_users.chat_id, exbot_mining_alerts.*").
+		Joins("join exbot_mining_alerts on exbot_mining_alerts.db_user_id = exbot_db_users.id").
+		Where("exbot_mining_alerts.deleted_at IS NULL").
+		Where("exbot_db_users.inactive = false").
+		Where("exbot_mining_alerts.difficulty_pi_b > 0 OR exbot_mining_alerts.commitment_percent > 0").
+		Scan(&alerts).Error
+	if err != nil {
+		ni.logger.Errorf("Can't get mining alerts: %v", err)
+		return
+	}
+
+	var difficultyPiB = miningInfo.ActualNetworkDifficulty / 1024
+	var commitmentPerTiB = miningInfo.ActualCommitment
+	for i := range alerts {
+		alert := &alerts[i]
+		var updates = map[string]interface{}{}
+
+		if alert.DifficultyPiB > 0 {
+			if alert.LastDifficultyPiB > 0 && (alert.LastDifficultyPiB < alert.DifficultyPiB) != (difficultyPiB < alert.DifficultyPiB) {
+				var direction = "risen above"
+				if difficultyPiB < alert.DifficultyPiB {
+					direction = "fallen below"
+				}
+				ni.notify(alert, notifier.EVENT_DIFFICULTY_ALERT, fmt.Sprintf("💻 <b>Network difficulty</b> has %v %v PiB:"+
+					"\n<i>Was:</i> %.2f PiB"+
+					"\n<i>Now:</i> %.2f PiB",
+					direction, common.FormatNumber(alert.DifficultyPiB, 2), alert.LastDifficultyPiB, difficultyPiB)+
+					ni.getRewardsText(&alert.MiningAlert, &miningInfo))
+			}
+			updates["last_difficulty_pi_b"] = difficultyPiB
+		}
+
+		if alert.CommitmentPercent > 0 {
+			if alert.LastCommitmentPerTiB == 0 {
+				updates["last_commitment_per_ti_b"] = commitmentPerTiB
+			} else {
+				change := (commitmentPerTiB - alert.LastCommitmentPerTiB) / alert.LastCommitmentPerTiB * 100
+				if math.Abs(change) >= alert.CommitmentPercent {
+					var sign string
+					if change > 0 {
+						sign = "+"
+					}
+					ni.notify(alert, notifier.EVENT_COMMITMENT_ALERT, fmt.Sprintf("💻 <b>Network commitment</b> has changed by %v%v%%:"+
+						"\n<i>Was:</i> %v SIGNA / TiB"+
+						"\n<i>Now:</i> %v SIGNA / TiB",
+						sign, common.FormatNumber(change, 2),
+						common.FormatNumber(alert.LastCommitmentPerTiB, 0), common.FormatNumber(commitmentPerTiB, 0))+
+						ni.getRewardsText(&alert.MiningAlert, &miningInfo))
+					updates["last_commitment_per_ti_b"] = commitmentPerTiB
+				}
+			}
+		}
+
+		if len(updates) > 0 {
+			if err := ni.db.Model(&alert.MiningAlert).Updates(updates).Error; err != nil {
+				ni.logger.Errorf("Error saving mining alert %v: %v", alert.ID, err)
+			}
+		}
+	}
+}
+
+// getRewardsText re-runs the calculation for the saved plot size and commitment
+func (ni *NetworkInfoListener) getRewardsText(alert *models.MiningAlert, miningInfo *signumapi.MiningInfo) string {
+	if alert.PlotSizeTiB <= 0 {
+		return ""
+	}
+
+	calcResult := calculator.Calculate(miningInfo, alert.PlotSizeTiB, alert.Commitment)
+	return fmt.Sprintf("\n\n<b>📃 Your %.2f TiB with %v SIGNA commitment:</b>"+
+		"\n<i>Capacity Multiplier:</i> %v"+
+		"\n<i>Effective Capacity:</i> %v TiB"+
+		"\n<i>Daily:</i> %v SIGNA"+
+		"\n<i>Monthly:</i> %v SIGNA",
+		calcResult.TiB, common.FormatNumber(calcResult.Commitment, 0),
+		common.FormatNumber(calcResult.CapacityMultiplier, 3),
+		common.FormatNumber(calcResult.EffectiveCapacity, 2),
+		common.FormatNumber(calcResult.MyDaily, 2),
+		common.FormatNumber(calcResult.MyMonthly, 0))
+}
+
+func (ni *NetworkInfoListener) notify(alert *userMiningAlert, eventType models.EventType, message string) {
+	err := ni.sink.Send(&notifier.NotifierMessage{
+		UserName: alert.UserName,
+		ChatID:   alert.ChatID,
+		Message:  message,
+		Event:    &models.NotificationEvent{Type: eventType},
+	})
+	if err != nil {
+		ni.logger.Errorf("Can't send mining alert for user %v (Chat.ID %v): %v", alert.UserName, alert.ChatID, err)
+	}
+}
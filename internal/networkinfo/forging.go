@@ -0,0 +1,173 @@
+package networkinfo
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/xDWart/signum-explorer-bot/calculator"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+var forgingPeriods = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"24h", config.DAY},
+	{"7d", config.WEEK},
+	{"30d", config.MONTH},
+}
+
+type ForgingStat struct {
+	Period         string
+	Blocks         int64
+	RewardsNQT     uint64  // block rewards plus fees
+	ExpectedBlocks float64 // zero if the plot isn't declared
+}
+
+type ForgingAccount struct {
+	Account string
+	Name    string
+}
+
+// getExpectedBlocksPerDay returns zero if the plot isn't declared
+func (ni *NetworkInfoListener) getExpectedBlocksPerDay(plot *models.MiningAlert) float64 {
+	miningInfo := ni.GetLastMiningInfo()
+	if plot == nil || plot.PlotSizeTiB <= 0 || miningInfo.LastBlockReward == 0 {
+		return 0
+	}
+	calcResult := calculator.Calculate(&miningInfo, plot.PlotSizeTiB, plot.Commitment)
+	return calcResult.MyDaily / float64(miningInfo.LastBlockReward)
+}
+
+// getForgingHistoryStart returns the time since the account is monitored, blocks aren't known before it
+func (ni *NetworkInfoListener) getForgingHistoryStart(account string) time.Time {
+	var dbAccount models.DbAccount
+	ni.db.Unscoped().Where("account = ?", account).Order("created_at").Limit(1).Find(&dbAccount)
+	return dbAccount.CreatedAt
+}
+
+func (ni *NetworkInfoListener) GetForgingStats(account string, plot *models.MiningAlert) []ForgingStat {
+	var now = time.Now()
+	var historyStart = ni.getForgingHistoryStart(account)
+	var expectedBlocksPerDay = ni.getExpectedBlocksPerDay(plot)
+
+	var stats = make([]ForgingStat, 0, len(forgingPeriods))
+	for _, period := range forgingPeriods {
+		var stat = ForgingStat{Period: period.name}
+		var from = now.Add(-period.duration)
+		err := ni.db.Model(&models.ForgedBlock{}).
+			Select("COUNT(*) AS blocks, COALESCE(SUM(block_reward_nqt + fees_nqt), 0) AS rewards_nqt").
+			Where("account = ? AND forged_at > ?", account, from).
+			Scan(&stat).Error
+		if err != nil {
+			ni.logger.Errorf("Can't get forged blocks of %v: %v", account, err)
+		}
+
+		if historyStart.After(from) {
+			from = historyStart
+		}
+		stat.ExpectedBlocks = expectedBlocksPerDay * now.Sub(from).Hours() / 24
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+func FormatForgingStats(stats []ForgingStat) string {
+	var text = "\n<b>Forged blocks:</b>"
+	for _, stat := range stats {
+		text += fmt.Sprintf("\n<i>%v:</i> %v (%v SIGNA)", stat.Period, stat.Blocks, common.FormatNQT(stat.RewardsNQT))
+		if stat.ExpectedBlocks > 0 {
+			text += fmt.Sprintf(", expected %v, luck %v%%",
+				common.FormatNumber(stat.ExpectedBlocks, 2), common.FormatNumber(float64(stat.Blocks)/stat.ExpectedBlocks*100, 0))
+		}
+	}
+	return text
+}
+
+// GetForgingChart plots the cumulative number of forged blocks and the expected one for the declared plot
+func (ni *NetworkInfoListener) GetForgingChart(accounts []ForgingAccount, plot *models.MiningAlert) []byte {
+	var now = time.Now()
+	var from = now.Add(-config.MONTH)
+
+	graph := chart.Chart{
+		Title: "Forged blocks (last month)",
+		Background: chart.Style{
+			Padding: chart.Box{
+				Top:  50,
+				Left: 20,
+			},
+		},
+		XAxis: chart.XAxis{
+			ValueFormatter: chart.TimeDateValueFormatter,
+		},
+		YAxis: chart.YAxis{
+			Name: "Blocks",
+		},
+		Series: []chart.Series{},
+	}
+
+	var totalBlocks int
+	var historyStart = now
+	for i, account := range accounts {
+		var forgedBlocks []models.ForgedBlock
+		err := ni.db.Where("account = ? AND forged_at > ?", account.Account, from).Order("forged_at").Find(&forgedBlocks).Error
+		if err != nil {
+			ni.logger.Errorf("Error getting forged blocks of %v for plotting chart: %v", account.Account, err)
+			return nil
+		}
+		if start := ni.getForgingHistoryStart(account.Account); start.Before(historyStart) {
+			historyStart = start
+		}
+
+		timeSeries := chart.TimeSeries{
+			Name: account.Name,
+			Style: chart.Style{
+				StrokeColor: chart.GetDefaultColor(i),
+			},
+			XValues: []time.Time{from},
+			YValues: []float64{0},
+		}
+		for j, forgedBlock := range forgedBlocks {
+			timeSeries.XValues = append(timeSeries.XValues, forgedBlock.ForgedAt)
+			timeSeries.YValues = append(timeSeries.YValues, float64(j+1))
+		}
+		timeSeries.XValues = append(timeSeries.XValues, now)
+		timeSeries.YValues = append(timeSeries.YValues, float64(len(forgedBlocks)))
+		graph.Series = append(graph.Series, timeSeries)
+		totalBlocks += len(forgedBlocks)
+	}
+
+	if expectedBlocksPerDay := ni.getExpectedBlocksPerDay(plot); expectedBlocksPerDay > 0 {
+		if historyStart.Before(from) {
+			historyStart = from
+		}
+		graph.Series = append(graph.Series, chart.TimeSeries{
+			Name: "Expected",
+			Style: chart.Style{
+				StrokeColor:     chart.ColorAlternateGray,
+				StrokeDashArray: []float64{5, 5},
+			},
+			XValues: []time.Time{historyStart, now},
+			YValues: []float64{0, expectedBlocksPerDay * now.Sub(historyStart).Hours() / 24},
+		})
+	} else if totalBlocks == 0 {
+		return nil // nothing to plot
+	}
+
+	graph.Elements = []chart.Renderable{
+		chart.Legend(&graph),
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	err := graph.Render(chart.PNG, buffer)
+	if err != nil {
+		ni.logger.Errorf("Could not render chart: %v", err)
+		return nil
+	}
+
+	return buffer.Bytes()
+}
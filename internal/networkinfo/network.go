@@ -5,6 +5,7 @@ import (
 	"github.com/xDWart/signum-explorer-bot/api/signumapi"
 	"github.com/xDWart/signum-explorer-bot/internal/common"
 	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"github.com/xDWart/signum-explorer-bot/internal/notifier"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"sync"
@@ -16,6 +17,7 @@ type NetworkInfoListener struct {
 	db             *gorm.DB
 	logger         *zap.SugaredLogger
 	signumClient   *signumapi.SignumApiClient
+	sink           notifier.Sink
 	lastMiningInfo signumapi.MiningInfo
 	Config         *Config
 }
@@ -31,12 +33,13 @@ type Config struct {
 	averageCount          int
 }
 
-func NewNetworkInfoListener(logger *zap.SugaredLogger, db *gorm.DB, signumClient *signumapi.SignumApiClient, wg *sync.WaitGroup, shutdownChannel chan interface{}, config *Config) *NetworkInfoListener {
+func NewNetworkInfoListener(logger *zap.SugaredLogger, db *gorm.DB, signumClient *signumapi.SignumApiClient, sink notifier.Sink, wg *sync.WaitGroup, shutdownChannel chan interface{}, config *Config) *NetworkInfoListener {
 	config.averageCount = 24 * config.AveragingDaysQuantity * int(time.Hour/config.SamplePeriod)
 	networkListener := &NetworkInfoListener{
 		db:             db,
 		logger:         logger,
 		signumClient:   signumClient,
+		sink:           sink,
 		lastMiningInfo: signumapi.DEFAULT_MINING_INFO,
 		Config:         config,
 	}
@@ -116,6 +119,8 @@ func (ni *NetworkInfoListener) getMiningInfo(samplesForAveraging []*signumapi.Mi
 	ni.lastMiningInfo.AverageNetworkDifficulty = (prevDifficulty*float64(ni.Config.averageCount-1) + miningInfo.ActualNetworkDifficulty) / float64(ni.Config.averageCount)
 	ni.Unlock()
 
+	ni.checkAlerts(ni.GetLastMiningInfo())
+
 	samplesForAveraging[sampleIndex] = miningInfo
 	sampleIndex = (sampleIndex + 1) % ni.Config.SmoothingFactor
 	timeToSave = (timeToSave + 1) % ni.Config.SaveEveryNSamples
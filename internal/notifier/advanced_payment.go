@@ -0,0 +1,176 @@
+package notifier
+
+import (
+	"fmt"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+// checkAdvancedPayments requests subscription payments and escrow results of monitored accounts from the stored height up to the last scanned block,
+// they are made by the node itself and aren't included into blocks
+func (n *Notifier) checkAdvancedPayments(shutdownChannel chan interface{}) {
+	var lastHeight = n.lastScannedHeight
+	if lastHeight == 0 { // no blocks have been scanned yet
+		return
+	}
+	if n.advancedPaymentsHeight == 0 { // the very first check, there is nothing to catch up
+		n.advancedPaymentsHeight = lastHeight
+		n.saveAdvancedPaymentsHeight()
+		return
+	}
+	if lastHeight <= n.advancedPaymentsHeight {
+		return
+	}
+
+	firstBlock, err := n.signumClient.GetBlockByHeight(n.logger, n.advancedPaymentsHeight+1)
+	if err != nil {
+		n.logger.Errorf("Can't get block #%v: %v", n.advancedPaymentsHeight+1, err)
+		return
+	}
+
+	var monitoredAccounts []MonitoredAccount
+	err = n.db.Model(&models.DbUser{}).
+		Select("exbot_db_users.user_name, exbot_db_users.chat_id, exbot_db_users.notification_threshold_nqt, " +
+			"exbot_db_users.digest_period, exbot_db_users.digest_only, exbot_db_accounts.*").
+		Joins("join exbot_db_accounts on exbot_db_accounts.db_user_id = exbot_db_users.id").
+		Where("exbot_db_accounts.deleted_at IS NULL").
+		Where("exbot_db_users.inactive = false").
+		Where("exbot_db_accounts.notify_income_transactions = true OR exbot_db_accounts.notify_outgo_transactions = true").
+		Scan(&monitoredAccounts).Error
+	if err != nil {
+		n.logger.Errorf("Can't get accounts for advanced payments: %v", err)
+		return
+	}
+	n.loadRules(monitoredAccounts)
+
+	// all transactions are requested before notifying, so the whole range is checked again if any request fails
+	var transactions = make(map[string][]signumapi.Transaction)
+	for _, account := range monitoredAccounts {
+		if _, ok := transactions[account.Account]; ok {
+			continue
+		}
+		select {
+		case <-shutdownChannel:
+			return // the same range will be checked again after restart
+		default:
+		}
+
+		accountTransactions, err := n.getAdvancedPaymentTransactions(account.Account, firstBlock.Timestamp, n.advancedPaymentsHeight+1, lastHeight)
+		if err != nil {
+			n.logger.Errorf("Can't get advanced payment transactions of %v: %v", account.Account, err)
+			return
+		}
+		transactions[account.Account] = accountTransactions
+	}
+
+	for i := range monitoredAccounts {
+		account := &monitoredAccounts[i]
+		for j := range transactions[account.Account] {
+			n.checkAdvancedPaymentTransaction(account, &transactions[account.Account][j])
+		}
+	}
+
+	n.advancedPaymentsHeight = lastHeight
+	n.saveAdvancedPaymentsHeight()
+}
+
+// getAdvancedPaymentTransactions returns subscription payments and escrow results of the account made in blocks from fromHeight to toHeight,
+// timestamp is the chain time of the block at fromHeight
+func (n *Notifier) getAdvancedPaymentTransactions(account string, timestamp int64, fromHeight, toHeight uint64) ([]signumapi.Transaction, error) {
+	var transactions []signumapi.Transaction
+	for page := uint64(0); ; page++ {
+		accountTransactions, err := n.signumClient.GetAccountAdvancedPaymentTransactions(n.logger, account, timestamp, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, transaction := range accountTransactions.Transactions {
+			if transaction.Height < fromHeight || transaction.Height > toHeight {
+				continue
+			}
+			if transaction.Subtype == signumapi.TST_SUBSCRIPTION_PAYMENT || transaction.Subtype == signumapi.TST_ESCROW_RESULT {
+				transactions = append(transactions, transaction)
+			}
+		}
+		if !accountTransactions.HasNextPage {
+			return transactions, nil
+		}
+	}
+}
+
+// checkAdvancedPaymentTransaction notifies about subscription payments and escrow results
+func (n *Notifier) checkAdvancedPaymentTransaction(account *MonitoredAccount, transaction *signumapi.Transaction) {
+	// escrow results are sent by the genesis account, so the monitored account is always the recipient of them
+	var incomeTransaction = transaction.Recipient == account.Account
+
+	if incomeTransaction && !account.NotifyIncomeTransactions {
+		return
+	}
+	if !incomeTransaction && !account.NotifyOutgoTransactions {
+		return
+	}
+	if transaction.GetAmountNQT() < account.NotificationThresholdNQT {
+		return
+	}
+	if !matchRules(account.Rules, newRuleSubject(account, transaction)) {
+		return
+	}
+
+	msg, accountIfAlias := formatAccountHeader("🔄", account)
+
+	var eventType models.EventType
+	switch transaction.Subtype {
+	case signumapi.TST_SUBSCRIPTION_PAYMENT:
+		eventType = EVENT_SUBSCRIPTION_PAYMENT
+		if incomeTransaction {
+			senderName := n.signumClient.GetCachedAccountName(n.logger, transaction.Sender)
+			if senderName != "" {
+				senderName = "\n<i>Name:</i> " + senderName
+			}
+			msg += fmt.Sprintf("new income:"+accountIfAlias+
+				"\n<i>Payment:</i> Subscription renewal"+
+				"\n<i>Subscription:</i> %v"+
+				"\n<i>Sender:</i> %v"+senderName+
+				"\n<i>Amount:</i> +%v SIGNA",
+				transaction.Attachment.SubscriptionID, transaction.SenderRS, common.FormatNQT(transaction.GetAmountNQT()))
+		} else {
+			recipientName := n.signumClient.GetCachedAccountName(n.logger, transaction.Recipient)
+			if recipientName != "" {
+				recipientName = "\n<i>Name:</i> " + recipientName
+			}
+			msg += fmt.Sprintf("new outgo:"+accountIfAlias+
+				"\n<i>Payment:</i> Subscription renewal"+
+				"\n<i>Subscription:</i> %v"+
+				"\n<i>Recipient:</i> %v"+recipientName+
+				"\n<i>Amount:</i> -%v SIGNA"+
+				"\n<i>Fee:</i> %v SIGNA",
+				transaction.Attachment.SubscriptionID, transaction.RecipientRS, common.FormatNQT(transaction.GetAmountNQT()),
+				common.ConvertFeeNQT(transaction.FeeNQT))
+		}
+	case signumapi.TST_ESCROW_RESULT:
+		eventType = EVENT_ESCROW_RESULT
+		msg += fmt.Sprintf("new income:"+accountIfAlias+
+			"\n<i>Payment:</i> Escrow %v"+
+			"\n<i>Escrow:</i> %v"+
+			"\n<i>Amount:</i> +%v SIGNA",
+			transaction.Attachment.Decision, transaction.Attachment.EscrowID, common.FormatNQT(transaction.GetAmountNQT()))
+	default:
+		n.logger.Errorf("%v: unknown SubType (%v) for transaction %v", account.Account, transaction.Subtype, transaction.TransactionID)
+		return
+	}
+
+	var totalBalance string
+	newAccount, err := n.signumClient.GetAccount(n.logger, account.Account)
+	if err == nil {
+		totalBalance = formatTotalBalance(newAccount.TotalBalanceNQT)
+	}
+
+	n.notify(NotifierMessage{
+		UserName: account.UserName,
+		ChatID:   account.ChatID,
+		BatchKey: account.AccountRS,
+		Message:  msg + totalBalance,
+		Event:    newTransactionEvent(eventType, account, transaction, getSignedAmountNQT(account, transaction, transaction.GetAmountNQT())),
+	})
+}
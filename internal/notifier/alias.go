@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"fmt"
+	"html"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+// checkAliasTransaction notifies about alias transfers, sale listings and purchases,
+// a sale for nothing to a certain account is the transfer and a sale for nothing to the owner cancels the listing
+func (n *Notifier) checkAliasTransaction(account *MonitoredAccount, transaction *signumapi.Transaction) {
+	if !matchRules(account.Rules, newRuleSubject(account, transaction)) {
+		return
+	}
+
+	var incomeTransaction = transaction.Sender != account.Account
+	var aliasName = html.EscapeString(transaction.Attachment.Alias)
+
+	var counterparty, counterpartyRS = transaction.Recipient, transaction.RecipientRS
+	if incomeTransaction {
+		counterparty, counterpartyRS = transaction.Sender, transaction.SenderRS
+	}
+	counterpartyName := n.signumClient.GetCachedAccountName(n.logger, counterparty)
+	if counterpartyName != "" {
+		counterpartyName = "\n<i>Name:</i> " + counterpartyName
+	}
+
+	msg, accountIfAlias := formatAccountHeader("🏷", account)
+
+	var eventType models.EventType
+	var amountNQT int64
+	switch transaction.Subtype {
+	case signumapi.TST_ALIAS_SELL:
+		priceNQT := transaction.GetPriceNQT()
+		switch {
+		case priceNQT == 0 && (transaction.Recipient == "" || transaction.Recipient == transaction.Sender):
+			eventType = EVENT_ALIAS_SALE
+			msg += fmt.Sprintf("alias sale cancelled:"+accountIfAlias+
+				"\n<i>Alias:</i> %v", aliasName)
+		case priceNQT == 0 && incomeTransaction:
+			eventType = EVENT_ALIAS_TRANSFER
+			msg += fmt.Sprintf("alias received:"+accountIfAlias+
+				"\n<i>Alias:</i> %v"+
+				"\n<i>Sender:</i> %v"+counterpartyName,
+				aliasName, counterpartyRS)
+		case priceNQT == 0:
+			eventType = EVENT_ALIAS_TRANSFER
+			msg += fmt.Sprintf("alias transferred:"+accountIfAlias+
+				"\n<i>Alias:</i> %v"+
+				"\n<i>Recipient:</i> %v"+counterpartyName,
+				aliasName, counterpartyRS)
+		case incomeTransaction:
+			eventType = EVENT_ALIAS_SALE
+			msg += fmt.Sprintf("alias offered to you:"+accountIfAlias+
+				"\n<i>Alias:</i> %v"+
+				"\n<i>Seller:</i> %v"+counterpartyName+
+				"\n<i>Price:</i> %v SIGNA",
+				aliasName, counterpartyRS, common.FormatNQT(priceNQT))
+		default:
+			eventType = EVENT_ALIAS_SALE
+			var buyer = "\n<i>Buyer:</i> anybody"
+			if transaction.Recipient != "" {
+				buyer = "\n<i>Buyer:</i> " + transaction.RecipientRS + counterpartyName
+			}
+			msg += fmt.Sprintf("alias listed for sale:"+accountIfAlias+
+				"\n<i>Alias:</i> %v"+
+				"\n<i>Price:</i> %v SIGNA"+buyer,
+				aliasName, common.FormatNQT(priceNQT))
+		}
+	case signumapi.TST_ALIAS_BUY:
+		eventType = EVENT_ALIAS_PURCHASE
+		amountNQT = getSignedAmountNQT(account, transaction, transaction.GetAmountNQT())
+		if incomeTransaction {
+			msg += fmt.Sprintf("alias sold:"+accountIfAlias+
+				"\n<i>Alias:</i> %v"+
+				"\n<i>Buyer:</i> %v"+counterpartyName+
+				"\n<i>Amount:</i> +%v SIGNA",
+				aliasName, counterpartyRS, common.FormatNQT(transaction.GetAmountNQT()))
+		} else {
+			msg += fmt.Sprintf("alias bought:"+accountIfAlias+
+				"\n<i>Alias:</i> %v"+
+				"\n<i>Seller:</i> %v"+counterpartyName+
+				"\n<i>Amount:</i> -%v SIGNA",
+				aliasName, counterpartyRS, common.FormatNQT(transaction.GetAmountNQT()))
+		}
+	default:
+		n.logger.Errorf("%v: unknown SubType (%v) for transaction %v", account.Account, transaction.Subtype, transaction.TransactionID)
+		return
+	}
+
+	if !incomeTransaction {
+		msg += fmt.Sprintf("\n<i>Fee:</i> %v SIGNA", common.ConvertFeeNQT(transaction.FeeNQT))
+	}
+
+	n.notify(NotifierMessage{
+		UserName: account.UserName,
+		ChatID:   account.ChatID,
+		BatchKey: account.AccountRS,
+		Message:  msg,
+		Event:    newTransactionEvent(eventType, account, transaction, amountNQT),
+	})
+}
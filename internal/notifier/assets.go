@@ -0,0 +1,289 @@
+package notifier
+
+import (
+	"fmt"
+	"html"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+func formatAssetQuantity(asset *signumapi.Asset, quantityQNT uint64) string {
+	return common.FormatNumber(asset.GetQuantity(quantityQNT), int(asset.Decimals))
+}
+
+func formatAssetPrice(asset *signumapi.Asset, priceNQT uint64) string {
+	return common.FormatNumber(asset.GetTokenPrice(float64(priceNQT)), 8)
+}
+
+// formatAssetBalance returns the actual token balance of the account, it's empty if the account can't be loaded
+func (n *Notifier) formatAssetBalance(account *MonitoredAccount, asset *signumapi.Asset) string {
+	newAccount, err := n.signumClient.GetAccount(n.logger, account.Account)
+	if err != nil {
+		n.logger.Errorf("Error getting account %v: %v", account.Account, err)
+		return ""
+	}
+	var balanceQNT uint64
+	for _, assetBalance := range newAccount.AssetBalances {
+		if assetBalance.Asset == asset.Asset {
+			balanceQNT = assetBalance.BalanceQNT
+		}
+	}
+	return fmt.Sprintf("\n<b>%v balance: %v</b>", html.EscapeString(asset.Name), formatAssetQuantity(asset, balanceQNT))
+}
+
+func (n *Notifier) checkAssetTransferTransaction(account *MonitoredAccount, transaction *signumapi.Transaction) {
+	var incomeTransaction = transaction.Sender != account.Account
+
+	if incomeTransaction && !account.NotifyIncomeTransactions {
+		return
+	}
+	if !incomeTransaction && !account.NotifyOutgoTransactions {
+		return
+	}
+	if !matchRules(account.Rules, newRuleSubject(account, transaction)) {
+		return
+	}
+
+	asset, err := n.signumClient.GetCachedAsset(n.logger, transaction.Attachment.Asset)
+	if err != nil {
+		n.logger.Errorf("%v: can't get asset %v for transaction %v: %v", account.Account, transaction.Attachment.Asset, transaction.TransactionID, err)
+		return
+	}
+
+	msg, accountIfAlias := formatAccountHeader("📇", account)
+	if incomeTransaction {
+		senderName := n.signumClient.GetCachedAccountName(n.logger, transaction.Sender)
+		if senderName != "" {
+			senderName = "\n<i>Name:</i> " + senderName
+		}
+		msg += fmt.Sprintf("new token income:"+accountIfAlias+
+			"\n<i>Token:</i> %v"+
+			"\n<i>Sender:</i> %v"+senderName+
+			"\n<i>Quantity:</i> +%v",
+			html.EscapeString(asset.Name), transaction.SenderRS, formatAssetQuantity(asset, transaction.GetQuantityQNT()))
+	} else {
+		recipientName := n.signumClient.GetCachedAccountName(n.logger, transaction.Recipient)
+		if recipientName != "" {
+			recipientName = "\n<i>Name:</i> " + recipientName
+		}
+		msg += fmt.Sprintf("new token outgo:"+accountIfAlias+
+			"\n<i>Token:</i> %v"+
+			"\n<i>Recipient:</i> %v"+recipientName+
+			"\n<i>Quantity:</i> -%v"+
+			"\n<i>Fee:</i> %v SIGNA",
+			html.EscapeString(asset.Name), transaction.RecipientRS, formatAssetQuantity(asset, transaction.GetQuantityQNT()),
+			common.ConvertFeeNQT(transaction.FeeNQT))
+	}
+
+	n.addOrConfirmPending(NotifierMessage{
+		UserName: account.UserName,
+		ChatID:   account.ChatID,
+		BatchKey: account.AccountRS,
+		Message:  msg + n.formatAssetBalance(account, asset),
+		Event:    newTransactionEvent(EVENT_ASSET_TRANSFER, account, transaction, 0),
+	}, transaction)
+}
+
+// checkAssetOrderTransaction notifies about placed and cancelled orders, the account is always the sender
+func (n *Notifier) checkAssetOrderTransaction(account *MonitoredAccount, transaction *signumapi.Transaction) {
+	if !matchRules(account.Rules, newRuleSubject(account, transaction)) {
+		return
+	}
+
+	var eventType = EVENT_ASSET_ORDER
+	var order = transaction
+	var action string
+	switch transaction.Subtype {
+	case signumapi.TST_ASK_ORDER_PLACEMENT:
+		action = "new sell order placed:"
+	case signumapi.TST_BID_ORDER_PLACEMENT:
+		action = "new buy order placed:"
+	case signumapi.TST_ASK_ORDER_CANCELLATION, signumapi.TST_BID_ORDER_CANCELLATION:
+		eventType = EVENT_ASSET_ORDER_CANCELLED
+		action = "sell order cancelled:"
+		if transaction.Subtype == signumapi.TST_BID_ORDER_CANCELLATION {
+			action = "buy order cancelled:"
+		}
+		// the order ID is the ID of its placement transaction
+		var err error
+		order, err = n.signumClient.GetTransaction(n.logger, transaction.Attachment.Order)
+		if err != nil {
+			n.logger.Errorf("%v: can't get order %v for transaction %v: %v", account.Account, transaction.Attachment.Order, transaction.TransactionID, err)
+			return
+		}
+	default:
+		n.logger.Errorf("%v: unknown SubType (%v) for transaction %v", account.Account, transaction.Subtype, transaction.TransactionID)
+		return
+	}
+
+	asset, err := n.signumClient.GetCachedAsset(n.logger, order.Attachment.Asset)
+	if err != nil {
+		n.logger.Errorf("%v: can't get asset %v for transaction %v: %v", account.Account, order.Attachment.Asset, transaction.TransactionID, err)
+		return
+	}
+
+	msg, accountIfAlias := formatAccountHeader("📇", account)
+	msg += fmt.Sprintf(action+accountIfAlias+
+		"\n<i>Token:</i> %v"+
+		"\n<i>Quantity:</i> %v"+
+		"\n<i>Price:</i> %v SIGNA"+
+		"\n<i>Total:</i> %v SIGNA"+
+		"\n<i>Fee:</i> %v SIGNA",
+		html.EscapeString(asset.Name), formatAssetQuantity(asset, order.GetQuantityQNT()),
+		formatAssetPrice(asset, order.GetPriceNQT()), common.FormatNQT(order.GetQuantityQNT()*order.GetPriceNQT()),
+		common.ConvertFeeNQT(transaction.FeeNQT))
+
+	n.notify(NotifierMessage{
+		UserName: account.UserName,
+		ChatID:   account.ChatID,
+		BatchKey: account.AccountRS,
+		Message:  msg,
+		Event:    newTransactionEvent(eventType, account, transaction, 0),
+	})
+}
+
+// getTradesByHeight requests trades since the block at once for the whole scanned range,
+// trades aren't transactions so they are requested separately
+func (n *Notifier) getTradesByHeight(fromBlock *signumapi.Block) (map[uint64][]*signumapi.Trade, error) {
+	allTrades, err := n.signumClient.GetAllTrades(n.logger, fromBlock.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("can't get trades since block #%v: %v", fromBlock.Height, err)
+	}
+
+	var trades = make(map[uint64][]*signumapi.Trade)
+	for i := range allTrades.Trades {
+		trade := &allTrades.Trades[i]
+		trades[trade.Height] = append(trades[trade.Height], trade)
+	}
+	return trades, nil
+}
+
+func (n *Notifier) checkTrade(account *MonitoredAccount, trade *signumapi.Trade) {
+	if account.DigestOnly || !account.NotifyIncomeTransactions && !account.NotifyOutgoTransactions {
+		return
+	}
+	if trade.GetAmountNQT() < account.NotificationThresholdNQT {
+		return
+	}
+	if !matchRules(account.Rules, newTradeRuleSubject(account, trade)) {
+		return
+	}
+
+	var asset = &signumapi.Asset{Asset: trade.Asset, Name: trade.Name, Decimals: trade.Decimals}
+	var amountNQT = int64(trade.GetAmountNQT())
+	msg, accountIfAlias := formatAccountHeader("📇", account)
+	if trade.Seller == account.Account {
+		buyerName := n.signumClient.GetCachedAccountName(n.logger, trade.Buyer)
+		if buyerName != "" {
+			buyerName = "\n<i>Name:</i> " + buyerName
+		}
+		msg += fmt.Sprintf("sell order filled:"+accountIfAlias+
+			"\n<i>Token:</i> %v"+
+			"\n<i>Buyer:</i> %v"+buyerName+
+			"\n<i>Quantity:</i> -%v"+
+			"\n<i>Price:</i> %v SIGNA"+
+			"\n<i>Amount:</i> +%v SIGNA",
+			html.EscapeString(asset.Name), trade.BuyerRS, formatAssetQuantity(asset, trade.QuantityQNT),
+			formatAssetPrice(asset, trade.PriceNQT), common.FormatNQT(trade.GetAmountNQT()))
+	} else {
+		amountNQT = -amountNQT
+		sellerName := n.signumClient.GetCachedAccountName(n.logger, trade.Seller)
+		if sellerName != "" {
+			sellerName = "\n<i>Name:</i> " + sellerName
+		}
+		msg += fmt.Sprintf("buy order filled:"+accountIfAlias+
+			"\n<i>Token:</i> %v"+
+			"\n<i>Seller:</i> %v"+sellerName+
+			"\n<i>Quantity:</i> +%v"+
+			"\n<i>Price:</i> %v SIGNA"+
+			"\n<i>Amount:</i> -%v SIGNA",
+			html.EscapeString(asset.Name), trade.SellerRS, formatAssetQuantity(asset, trade.QuantityQNT),
+			formatAssetPrice(asset, trade.PriceNQT), common.FormatNQT(trade.GetAmountNQT()))
+	}
+
+	event := newAccountEvent(EVENT_ASSET_TRADE, account, amountNQT)
+	event.Height = trade.Height
+	n.notify(NotifierMessage{
+		UserName: account.UserName,
+		ChatID:   account.ChatID,
+		BatchKey: account.AccountRS,
+		Message:  msg + n.formatAssetBalance(account, asset),
+		Event:    event,
+	})
+}
+
+type tokenWatch struct {
+	UserName string
+	ChatID   int64
+	models.TokenWatch
+}
+
+// checkTokenWatches notifies users watching the token about large transfers of anybody
+func (n *Notifier) checkTokenWatches(block *signumapi.BlockWithTransactions) {
+	var transfers []*signumapi.Transaction
+	var assets []string
+	for i := range block.Transactions {
+		transaction := &block.Transactions[i]
+		if transaction.Type == signumapi.TT_TOKENIZATION && transaction.Subtype == signumapi.TST_ASSET_TRANSFER {
+			transfers = append(transfers, transaction)
+			assets = append(assets, transaction.Attachment.Asset)
+		}
+	}
+	if len(transfers) == 0 {
+		return
+	}
+
+	var watches []tokenWatch
+	err := n.db.Model(&models.DbUser{}).
+		Select("exbot_db_users.user_name, exbot_db_users.chat_id, exbot_token_watches.*").
+		Joins("join exbot_token_watches on exbot_token_watches.db_user_id = exbot_db_users.id").
+		Where("exbot_token_watches.deleted_at IS NULL").
+		Where("exbot_token_watches.asset IN ? AND exbot_token_watches.transfer_qnt > 0", assets).
+		Where("exbot_db_users.inactive = false").
+		Scan(&watches).Error
+	if err != nil {
+		n.logger.Errorf("Can't get token watches: %v", err)
+		return
+	}
+
+	for _, transaction := range transfers {
+		for i := range watches {
+			watch := &watches[i]
+			if watch.Asset != transaction.Attachment.Asset || transaction.GetQuantityQNT() < watch.TransferQNT {
+				continue
+			}
+			asset, err := n.signumClient.GetCachedAsset(n.logger, watch.Asset)
+			if err != nil {
+				n.logger.Errorf("Can't get asset %v for transaction %v: %v", watch.Asset, transaction.TransactionID, err)
+				continue
+			}
+
+			msg := fmt.Sprintf("🐋 <b>%v</b> large transfer:"+
+				"\n<i>Sender:</i> %v"+
+				"\n<i>Recipient:</i> %v"+
+				"\n<i>Quantity:</i> %v",
+				html.EscapeString(asset.Name), transaction.SenderRS, transaction.RecipientRS,
+				formatAssetQuantity(asset, transaction.GetQuantityQNT()))
+			if circulatingQNT := asset.GetCirculatingQNT(); circulatingQNT > 0 {
+				msg += fmt.Sprintf(" (%v%% of circulating)",
+					common.FormatNumber(float64(transaction.GetQuantityQNT())/float64(circulatingQNT)*100, 2))
+			}
+
+			n.notify(NotifierMessage{
+				UserName: watch.UserName,
+				ChatID:   watch.ChatID,
+				BatchKey: asset.Name,
+				Message:  msg,
+				Event: &models.NotificationEvent{
+					Type:          EVENT_LARGE_TOKEN_TRANSFER,
+					Account:       transaction.Sender,
+					AccountRS:     transaction.SenderRS,
+					TransactionID: transaction.TransactionID,
+					Height:        transaction.Height,
+				},
+			})
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+// checkBalances compares available balances with the last observed ones,
+// so any change is caught regardless of the transaction type which caused it
+func (n *Notifier) checkBalances(shutdownChannel chan interface{}) {
+	var monitoredAccounts []MonitoredAccount
+	err := n.db.Model(&models.DbUser{}).
+		Select("exbot_db_users.user_name, exbot_db_users.chat_id, exbot_db_users.notification_threshold_nqt, exbot_db_accounts.*").
+		Joins("join exbot_db_accounts on exbot_db_accounts.db_user_id = exbot_db_users.id").
+		Where("exbot_db_accounts.deleted_at IS NULL").
+		Where("exbot_db_users.inactive = false").
+		Where("exbot_db_accounts.balance_floor_nqt > 0 OR exbot_db_accounts.balance_change_percent > 0").
+		Scan(&monitoredAccounts).Error
+	if err != nil {
+		n.logger.Errorf("Can't get accounts with balance alerts: %v", err)
+		return
+	}
+	if len(monitoredAccounts) == 0 {
+		return
+	}
+
+	var ids = make([]uint, 0, len(monitoredAccounts))
+	for _, account := range monitoredAccounts {
+		ids = append(ids, account.ID)
+	}
+	var accountBalances []models.AccountBalance
+	if err := n.db.Where("db_account_id IN ?", ids).Find(&accountBalances).Error; err != nil {
+		n.logger.Errorf("Can't get last observed balances: %v", err)
+		return
+	}
+	var lastBalances = make(map[uint]*models.AccountBalance, len(accountBalances))
+	for i := range accountBalances {
+		lastBalances[accountBalances[i].DbAccountID] = &accountBalances[i]
+	}
+
+	for i := range monitoredAccounts {
+		select {
+		case <-shutdownChannel:
+			return
+		default:
+		}
+
+		account := &monitoredAccounts[i]
+		lastBalance := lastBalances[account.ID]
+		if lastBalance == nil {
+			lastBalance = &models.AccountBalance{DbAccountID: account.ID}
+		}
+		n.checkBalance(account, lastBalance)
+	}
+}
+
+func (n *Notifier) checkBalance(account *MonitoredAccount, lastBalance *models.AccountBalance) {
+	signumAccount, err := n.signumClient.GetAccount(n.logger, account.Account)
+	if err != nil {
+		n.logger.Errorf("Can't get account %v to check the balance: %v", account.Account, err)
+		return
+	}
+	var balanceNQT = signumAccount.AvailableBalanceNQT
+
+	var accountName, accountIfAlias string
+	if account.Alias != "" {
+		accountName = account.Alias
+		accountIfAlias = "\n<i>Account:</i> " + account.AccountRS
+	} else {
+		accountName = account.AccountRS
+	}
+
+	if account.BalanceFloorNQT > 0 {
+		if balanceNQT < account.BalanceFloorNQT && !lastBalance.BelowFloor {
+			n.notify(NotifierMessage{
+				UserName: account.UserName,
+				ChatID:   account.ChatID,
+				BatchKey: account.AccountRS,
+				Message: fmt.Sprintf("🪫 <b>%v</b> balance is below the floor:"+accountIfAlias+
+					"\n<i>Available:</i> %v SIGNA"+
+					"\n<i>Floor:</i> %v SIGNA",
+					accountName, common.FormatNQT(balanceNQT), common.FormatNQT(account.BalanceFloorNQT)),
+				Event: newAccountEvent(EVENT_BALANCE_BELOW_FLOOR, account, int64(balanceNQT)),
+			})
+			lastBalance.BelowFloor = true
+		} else if balanceNQT >= account.BalanceFloorNQT && lastBalance.BelowFloor {
+			n.notify(NotifierMessage{
+				UserName: account.UserName,
+				ChatID:   account.ChatID,
+				BatchKey: account.AccountRS,
+				Message: fmt.Sprintf("🔋 <b>%v</b> balance is above the floor again:"+accountIfAlias+
+					"\n<i>Available:</i> %v SIGNA"+
+					"\n<i>Floor:</i> %v SIGNA",
+					accountName, common.FormatNQT(balanceNQT), common.FormatNQT(account.BalanceFloorNQT)),
+				Event: newAccountEvent(EVENT_BALANCE_ABOVE_FLOOR, account, int64(balanceNQT)),
+			})
+			lastBalance.BelowFloor = false
+		}
+	} else {
+		lastBalance.BelowFloor = false
+	}
+
+	// the change can't be calculated for the very first observation and for an empty account
+	if account.BalanceChangePercent > 0 && lastBalance.ID != 0 && lastBalance.AvailableBalanceNQT > 0 {
+		changePercent := (float64(balanceNQT) - float64(lastBalance.AvailableBalanceNQT)) / float64(lastBalance.AvailableBalanceNQT) * 100
+		if math.Abs(changePercent) >= account.BalanceChangePercent {
+			var sign string
+			if changePercent > 0 {
+				sign = "+"
+			}
+			n.notify(NotifierMessage{
+				UserName: account.UserName,
+				ChatID:   account.ChatID,
+				BatchKey: account.AccountRS,
+				Message: fmt.Sprintf("📊 <b>%v</b> balance has changed by %v%v%%:"+accountIfAlias+
+					"\n<i>Was:</i> %v SIGNA"+
+					"\n<i>Available:</i> %v SIGNA",
+					accountName, sign, common.FormatNumber(changePercent, 2),
+					common.FormatNQT(lastBalance.AvailableBalanceNQT), common.FormatNQT(balanceNQT)),
+				Event: newAccountEvent(EVENT_BALANCE_CHANGE, account, int64(balanceNQT)-int64(lastBalance.AvailableBalanceNQT)),
+			})
+		}
+	}
+
+	lastBalance.AvailableBalanceNQT = balanceNQT
+	if err := n.db.Save(lastBalance).Error; err != nil {
+		n.logger.Errorf("Error saving the last observed balance of %v: %v", account.Account, err)
+	}
+}
@@ -1,24 +1,16 @@
 package notifier
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
 
-func (n *Notifier) checkBlocks(account *MonitoredAccount) {
-	userBlocks, err := n.signumClient.GetCachedAccountBlocks(n.logger, account.Account)
-	if err != nil {
-		n.logger.Errorf("Can't get last account %v blocks: %v", account.Account, err)
-		return
-	}
-
-	if userBlocks == nil || len(userBlocks.Blocks) == 0 {
-		return
-	}
-
-	foundBlock := userBlocks.Blocks[0]
-	if foundBlock.Block == account.LastBlockID ||
-		foundBlock.Height <= account.LastBlockH {
-		return
-	}
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"gorm.io/gorm/clause"
+)
 
+func (n *Notifier) checkBlock(account *MonitoredAccount, foundBlock *signumapi.Block) {
 	var msg string
 	if account.Alias != "" {
 		msg = fmt.Sprintf("💽 <b>%v</b> (%v) ", account.Alias, account.AccountRS)
@@ -27,16 +19,38 @@ func (n *Notifier) checkBlocks(account *MonitoredAccount) {
 	}
 
 	msg += fmt.Sprintf("found new block <b>#%v</b> (%v SIGNA)", foundBlock.Height, foundBlock.BlockReward)
+	msg += fmt.Sprintf("\n<i>Reward + fees:</i> %v SIGNA", common.FormatNQT(foundBlock.GetRewardNQT()))
+
+	var plot models.MiningAlert
+	n.db.Where("db_user_id = ?", account.DbUserID).Limit(1).Find(&plot)
+	msg += "\n" + n.config.ForgingStats(account.Account, &plot)
 
-	n.notifierCh <- NotifierMessage{
+	n.notify(NotifierMessage{
 		UserName: account.UserName,
 		ChatID:   account.ChatID,
+		BatchKey: account.AccountRS,
 		Message:  msg,
-	}
+		Event: &models.NotificationEvent{
+			Type:      EVENT_BLOCK,
+			Account:   account.Account,
+			AccountRS: account.AccountRS,
+			AmountNQT: int64(foundBlock.GetRewardNQT()),
+			Height:    foundBlock.Height,
+		},
+	})
+}
 
-	account.DbAccount.LastBlockID = foundBlock.Block
-	account.DbAccount.LastBlockH = foundBlock.Height
-	if err := n.db.Save(&account.DbAccount).Error; err != nil {
-		n.logger.Errorf("Error saving account: %v", err)
+// saveForgedBlock keeps the forging history of monitored accounts, the block could be scanned again after restart
+func (n *Notifier) saveForgedBlock(block *signumapi.Block) {
+	blockReward, _ := strconv.ParseFloat(block.BlockReward, 64)
+	err := n.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ForgedBlock{
+		Account:        block.Generator,
+		Height:         block.Height,
+		BlockRewardNQT: uint64(blockReward * 1e8),
+		FeesNQT:        block.TotalFeeNQT,
+		ForgedAt:       common.ChainTimeToTime(block.Timestamp),
+	}).Error
+	if err != nil {
+		n.logger.Errorf("Error saving forged block #%v of %v: %v", block.Height, block.Generator, err)
 	}
 }
@@ -0,0 +1,224 @@
+package notifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"gorm.io/gorm"
+)
+
+type digestAccount struct {
+	DigestHour     int
+	DigestTimezone string
+	MonitoredAccount
+}
+
+func (n *Notifier) addTransactionToDigest(account *MonitoredAccount, transaction *signumapi.Transaction) {
+	var updates = map[string]interface{}{}
+	if transaction.Sender == account.Account {
+		updates["fees_nqt"] = gorm.Expr("fees_nqt + ?", transaction.FeeNQT)
+		if transaction.GetAmountNQT() > 0 {
+			updates["outgo_nqt"] = gorm.Expr("outgo_nqt + ?", transaction.GetAmountNQT())
+			updates["outgo_count"] = gorm.Expr("outgo_count + 1")
+		}
+	} else {
+		switch transaction.Type {
+		case signumapi.TT_PAYMENT:
+			updates["income_nqt"] = gorm.Expr("income_nqt + ?", getPaymentAmountNQT(account, transaction))
+			updates["income_count"] = gorm.Expr("income_count + 1")
+		case signumapi.TT_AUTOMATED_TRANSACTIONS:
+			if transaction.Subtype == signumapi.TST_AT_PAYMENT {
+				updates["at_income_nqt"] = gorm.Expr("at_income_nqt + ?", transaction.GetAmountNQT())
+			}
+		case signumapi.TT_TOKENIZATION:
+			if transaction.Subtype == signumapi.TST_TOKENIZATION_DISTRIBUTION_TO_HOLDER {
+				distributionAmount, err := n.signumClient.GetDistributionAmount(n.logger, transaction.TransactionID, account.Account)
+				if err != nil {
+					n.logger.Errorf("%v: cant get distribution amount for transaction %v", account.Account, transaction.TransactionID)
+					return
+				}
+				updates["token_income_nqt"] = gorm.Expr("token_income_nqt + ?", distributionAmount.AmountNQT)
+			}
+		}
+	}
+	n.updateDigestStat(account, updates)
+}
+
+func (n *Notifier) addBlockToDigest(account *MonitoredAccount, block *signumapi.Block) {
+	n.updateDigestStat(account, map[string]interface{}{
+		"blocks_forged":     gorm.Expr("blocks_forged + 1"),
+		"block_rewards_nqt": gorm.Expr("block_rewards_nqt + ?", block.GetRewardNQT()),
+	})
+}
+
+// updateDigestStat does nothing until the stat is created by sendDigests
+func (n *Notifier) updateDigestStat(account *MonitoredAccount, updates map[string]interface{}) {
+	if len(updates) == 0 {
+		return
+	}
+	err := n.db.Model(&models.DigestStat{}).Where("db_account_id = ?", account.ID).Updates(updates).Error
+	if err != nil {
+		n.logger.Errorf("Error updating digest stat of %v: %v", account.Account, err)
+	}
+}
+
+func (n *Notifier) sendDigests(shutdownChannel chan interface{}) {
+	var digestAccounts []digestAccount
+	err := n.db.Model(&models.DbUser{}).
+		Select("exbot_db_users.user_name, exbot_db_users.chat_id, exbot_db_users.digest_period, " +
+			"exbot_db_users.digest_hour, exbot_db_users.digest_timezone, exbot_db_accounts.*").
+		Joins("join exbot_db_accounts on exbot_db_accounts.db_user_id = exbot_db_users.id").
+		Where("exbot_db_accounts.deleted_at IS NULL").
+		Where("exbot_db_users.inactive = false").
+		Where("exbot_db_users.digest_period <> ''").
+		Scan(&digestAccounts).Error
+	if err != nil {
+		n.logger.Errorf("Can't get digest accounts: %v", err)
+		return
+	}
+	if len(digestAccounts) == 0 {
+		return
+	}
+
+	var ids = make([]uint, 0, len(digestAccounts))
+	for _, account := range digestAccounts {
+		ids = append(ids, account.ID)
+	}
+	var digestStats []models.DigestStat
+	if err := n.db.Where("db_account_id IN ?", ids).Find(&digestStats).Error; err != nil {
+		n.logger.Errorf("Can't get digest stats: %v", err)
+		return
+	}
+	var stats = make(map[uint]*models.DigestStat, len(digestStats))
+	for i := range digestStats {
+		stats[digestStats[i].DbAccountID] = &digestStats[i]
+	}
+
+	var now = time.Now()
+	for i := range digestAccounts {
+		select {
+		case <-shutdownChannel:
+			return
+		default:
+		}
+
+		account := &digestAccounts[i]
+		location := common.LoadLocation(account.DigestTimezone)
+		dueTime := getLastDigestTime(now.In(location), account.DigestPeriod, account.DigestHour)
+
+		stat := stats[account.ID]
+		if stat != nil && !stat.PeriodStart.Before(dueTime) {
+			continue
+		}
+
+		signumAccount, err := n.signumClient.GetAccount(n.logger, account.Account)
+		if err != nil {
+			n.logger.Errorf("Can't get account %v for the digest: %v", account.Account, err)
+			continue
+		}
+
+		if stat == nil { // the digest has just been enabled, start collecting
+			stat = &models.DigestStat{
+				DbAccountID:     account.ID,
+				PeriodStart:     now,
+				StartBalanceNQT: signumAccount.TotalBalanceNQT,
+			}
+			if err := n.db.Create(stat).Error; err != nil {
+				n.logger.Errorf("Error creating digest stat of %v: %v", account.Account, err)
+			}
+			continue
+		}
+
+		n.notify(NotifierMessage{
+			UserName: account.UserName,
+			ChatID:   account.ChatID,
+			Message:  n.formatDigest(account, stat, dueTime, signumAccount.TotalBalanceNQT, location),
+			Event:    newAccountEvent(EVENT_DIGEST, &account.MonitoredAccount, int64(signumAccount.TotalBalanceNQT)-int64(stat.StartBalanceNQT)),
+		})
+
+		err = n.db.Model(stat).Updates(map[string]interface{}{
+			"period_start":      dueTime,
+			"start_balance_nqt": signumAccount.TotalBalanceNQT,
+			"income_nqt":        0,
+			"income_count":      0,
+			"outgo_nqt":         0,
+			"outgo_count":       0,
+			"fees_nqt":          0,
+			"blocks_forged":     0,
+			"block_rewards_nqt": 0,
+			"at_income_nqt":     0,
+			"token_income_nqt":  0,
+		}).Error
+		if err != nil {
+			n.logger.Errorf("Error resetting digest stat of %v: %v", account.Account, err)
+		}
+	}
+}
+
+func (n *Notifier) formatDigest(account *digestAccount, stat *models.DigestStat, periodEnd time.Time, totalBalanceNQT uint64, location *time.Location) string {
+	msg, accountIfAlias := formatAccountHeader("📰", &account.MonitoredAccount)
+	msg += fmt.Sprintf("%v digest:"+accountIfAlias+
+		"\n<i>Period:</i> %v - %v (%v)"+
+		"\n<i>Income:</i> +%v SIGNA (%v TXs)"+
+		"\n<i>Outgo:</i> -%v SIGNA (%v TXs)"+
+		"\n<i>Fees paid:</i> %v SIGNA",
+		account.DigestPeriod,
+		stat.PeriodStart.In(location).Format("2006-01-02 15:04"), periodEnd.In(location).Format("2006-01-02 15:04"), location,
+		common.FormatNQT(stat.IncomeNQT), stat.IncomeCount,
+		common.FormatNQT(stat.OutgoNQT), stat.OutgoCount,
+		common.FormatNQT(stat.FeesNQT))
+
+	if stat.BlocksForged > 0 {
+		msg += fmt.Sprintf("\n<i>Blocks forged:</i> %v (+%v SIGNA)", stat.BlocksForged, common.FormatNQT(stat.BlockRewardsNQT))
+	}
+	if stat.ATIncomeNQT > 0 {
+		msg += fmt.Sprintf("\n<i>AT payments:</i> +%v SIGNA", common.FormatNQT(stat.ATIncomeNQT))
+	}
+	if stat.TokenIncomeNQT > 0 {
+		msg += fmt.Sprintf("\n<i>Distributions to holders:</i> +%v SIGNA", common.FormatNQT(stat.TokenIncomeNQT))
+	}
+
+	var sign = "+"
+	var changeNQT = totalBalanceNQT - stat.StartBalanceNQT
+	if totalBalanceNQT < stat.StartBalanceNQT {
+		sign = "-"
+		changeNQT = stat.StartBalanceNQT - totalBalanceNQT
+	}
+	msg += fmt.Sprintf("\n<i>Net change:</i> %v%v SIGNA", sign, common.FormatNQT(changeNQT))
+	if price := n.getAveragePrice(stat.PeriodStart, periodEnd); price > 0 {
+		msg += fmt.Sprintf(" (%v$%v)", sign, common.FormatNumber(float64(changeNQT)/1e8*price, 2))
+	}
+
+	return msg + formatTotalBalance(totalBalanceNQT)
+}
+
+// getAveragePrice returns the average SIGNA price during the period or the last known one before it
+func (n *Notifier) getAveragePrice(from, to time.Time) float64 {
+	var averagePrice float64
+	n.db.Model(&models.Price{}).Select("COALESCE(AVG(signa_price), 0)").
+		Where("created_at BETWEEN ? AND ?", from, to).Scan(&averagePrice)
+	if averagePrice > 0 {
+		return averagePrice
+	}
+
+	var lastPrice models.Price
+	n.db.Where("created_at <= ?", to).Order("created_at desc").Limit(1).Find(&lastPrice)
+	return lastPrice.SignaPrice
+}
+
+// getLastDigestTime returns the last scheduled digest time which isn't after now, weekly digests are on Monday
+func getLastDigestTime(now time.Time, period models.DigestPeriod, hour int) time.Time {
+	dueTime := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, now.Location())
+	if dueTime.After(now) {
+		dueTime = dueTime.AddDate(0, 0, -1)
+	}
+	if period == models.DIGEST_WEEKLY {
+		for dueTime.Weekday() != time.Monday {
+			dueTime = dueTime.AddDate(0, 0, -1)
+		}
+	}
+	return dueTime
+}
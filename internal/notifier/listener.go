@@ -1,9 +1,12 @@
 package notifier
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
 	"github.com/xDWart/signum-explorer-bot/internal/database/models"
 )
 
@@ -12,57 +15,385 @@ func (n *Notifier) startListener(wg *sync.WaitGroup, shutdownChannel chan interf
 
 	n.logger.Infof("Start Notifier")
 	ticker := time.NewTicker(n.config.NotifierPeriod)
+	mempoolTicker := time.NewTicker(n.config.MempoolPeriod)
+	advancedPaymentsTicker := time.NewTicker(n.config.AdvancedPaymentsPeriod)
+	watchdogTicker := time.NewTicker(n.config.WatchdogPeriod)
 
-	var counter uint
-
-	n.checkAccounts()
+	n.scanBlocks(shutdownChannel)
+	n.checkWatchdogs(shutdownChannel, nil)
 	for {
 		select {
 		case <-shutdownChannel:
 			n.logger.Infof("Notify Listener received shutdown signal")
 			ticker.Stop()
+			mempoolTicker.Stop()
+			advancedPaymentsTicker.Stop()
+			watchdogTicker.Stop()
 			return
 
+		case <-mempoolTicker.C:
+			n.scanMempool()
+
+		case <-advancedPaymentsTicker.C:
+			n.checkAdvancedPayments(shutdownChannel)
+
+		case <-watchdogTicker.C:
+			n.checkWatchdogs(shutdownChannel, nil)
+
 		case <-ticker.C:
-			counter++
-			n.logger.Infof("Notify Listener starts checking")
+			n.logger.Infof("Notify Listener starts scanning from height %v", n.lastScannedHeight+1)
 			startTime := time.Now()
-			n.checkAccounts()
-			n.logger.Infof("Notify Listener has finished checking in %v", time.Since(startTime))
+			n.scanBlocks(shutdownChannel)
+			n.logger.Infof("Notify Listener has finished scanning up to height %v in %v", n.lastScannedHeight, time.Since(startTime))
+			n.checkBalances(shutdownChannel)
+			n.checkChangedWatchdogs(shutdownChannel)
+			n.sendDigests(shutdownChannel)
 		}
 	}
 }
 
-func (n *Notifier) checkAccounts() {
-	var monitoredAccounts []MonitoredAccount
+func (n *Notifier) readLastScannedHeight() {
+	n.lastScannedHeight = n.readHeight(config.DB_CONFIG_NOTIFIER_LAST_HEIGHT)
+	n.logger.Infof("Notifier has loaded the last scanned height from DB: %v", n.lastScannedHeight)
+}
+
+func (n *Notifier) saveLastScannedHeight() {
+	n.saveHeight(config.DB_CONFIG_NOTIFIER_LAST_HEIGHT, n.lastScannedHeight)
+}
+
+func (n *Notifier) readAdvancedPaymentsHeight() {
+	n.advancedPaymentsHeight = n.readHeight(config.DB_CONFIG_NOTIFIER_AP_HEIGHT)
+}
+
+func (n *Notifier) saveAdvancedPaymentsHeight() {
+	n.saveHeight(config.DB_CONFIG_NOTIFIER_AP_HEIGHT, n.advancedPaymentsHeight)
+}
+
+func (n *Notifier) readHeight(name string) uint64 {
+	height := models.Config{Name: name}
+	n.db.Where(&height).First(&height)
+	return uint64(height.ValueI)
+}
+
+func (n *Notifier) saveHeight(name string, value uint64) {
+	height := models.Config{Name: name}
+	n.db.Where(&height).First(&height)
+	height.ValueI = int(value)
+	if err := n.db.Save(&height).Error; err != nil {
+		n.logger.Errorf("Error saving %v: %v", name, err)
+	}
+}
+
+func (n *Notifier) scanBlocks(shutdownChannel chan interface{}) {
+	blockchainStatus, err := n.signumClient.GetBlockchainStatus(n.logger)
+	if err != nil {
+		n.logger.Errorf("Can't get blockchain status: %v", err)
+		return
+	}
+
+	if blockchainStatus.NumberOfBlocks <= n.config.Confirmations+1 {
+		return
+	}
+	lastHeight := blockchainStatus.NumberOfBlocks - 1 - n.config.Confirmations
+
+	if n.lastScannedHeight == 0 { // the very first start, there is nothing to catch up
+		n.lastScannedHeight = lastHeight
+		n.saveLastScannedHeight()
+		return
+	}
+
+	var scannedBlocks uint64
+	var tradesByHeight map[uint64][]*signumapi.Trade // requested once for the whole range
+	for height := n.lastScannedHeight + 1; height <= lastHeight; height++ {
+		if scannedBlocks >= n.config.MaxBlocksPerScan {
+			n.logger.Infof("Notifier is %v blocks behind, will continue with the next tick", lastHeight-n.lastScannedHeight)
+			return
+		}
+
+		select {
+		case <-shutdownChannel:
+			return
+		default:
+		}
+
+		block, err := n.signumClient.GetBlockWithTransactions(n.logger, height)
+		if err != nil {
+			n.logger.Errorf("Can't get block #%v: %v", height, err)
+			return // the same height will be requested again by the next tick
+		}
+
+		for _, handler := range n.config.BlockHandlers {
+			if err := handler(block); err != nil {
+				n.logger.Errorf("Can't handle block #%v: %v", height, err)
+				return // the same height will be requested again by the next tick
+			}
+		}
+		if tradesByHeight == nil {
+			if tradesByHeight, err = n.getTradesByHeight(&block.Block); err != nil {
+				n.logger.Errorf("Can't process block #%v: %v", height, err)
+				return // the same height will be requested again by the next tick
+			}
+		}
+		if err := n.processBlock(block, tradesByHeight[height]); err != nil {
+			n.logger.Errorf("Can't process block #%v: %v", height, err)
+			return // the same height will be requested again by the next tick
+		}
+		n.checkTokenWatches(block)
+		for i := range block.Transactions {
+			if isWatchdogTransaction(&block.Transactions[i]) {
+				n.watchdogAccounts[block.Transactions[i].Sender] = true
+			}
+		}
+
+		n.lastScannedHeight = height
+		n.saveLastScannedHeight()
+		scannedBlocks++
+	}
+}
+
+// processBlock notifies about transactions and filled orders of the block,
+// it returns an error if notifications can't be checked, the block should be scanned again then
+func (n *Notifier) processBlock(block *signumapi.BlockWithTransactions, trades []*signumapi.Trade) error {
+	monitoredHolders, err := n.getDistributionsHolders(block)
+	if err != nil {
+		return err
+	}
+
+	var involvedAccounts = map[string]bool{block.Generator: true}
+	var transactionAccounts = make([][]string, len(block.Transactions))
+	for i := range block.Transactions {
+		accounts := getTransactionAccounts(&block.Transactions[i], monitoredHolders)
+		transactionAccounts[i] = accounts
+		for _, account := range accounts {
+			involvedAccounts[account] = true
+		}
+	}
+	for _, trade := range trades {
+		involvedAccounts[trade.Seller] = true
+		involvedAccounts[trade.Buyer] = true
+	}
+
+	monitoredAccounts, err := n.getMonitoredAccounts(involvedAccounts)
+	if err != nil {
+		return err
+	}
+	if len(monitoredAccounts) == 0 {
+		return nil
+	}
+
+	n.logger.Debugf("Block #%v: %v transactions, %v trades, %v monitored accounts involved",
+		block.Height, len(block.Transactions), len(trades), len(monitoredAccounts))
+
+	for i := range block.Transactions {
+		transaction := &block.Transactions[i]
+		for _, account := range transactionAccounts[i] {
+			for _, monitoredAccount := range monitoredAccounts[account] {
+				if monitoredAccount.DigestPeriod != "" {
+					n.addTransactionToDigest(monitoredAccount, transaction)
+				}
+				if !monitoredAccount.DigestOnly {
+					n.checkTransaction(monitoredAccount, transaction)
+				}
+			}
+		}
+	}
+
+	for _, trade := range trades {
+		for _, monitoredAccount := range monitoredAccounts[trade.Seller] {
+			n.checkTrade(monitoredAccount, trade)
+		}
+		for _, monitoredAccount := range monitoredAccounts[trade.Buyer] {
+			n.checkTrade(monitoredAccount, trade)
+		}
+	}
+
+	if len(monitoredAccounts[block.Generator]) > 0 {
+		n.saveForgedBlock(&block.Block)
+	}
+	for _, monitoredAccount := range monitoredAccounts[block.Generator] {
+		if monitoredAccount.DigestPeriod != "" {
+			n.addBlockToDigest(monitoredAccount, &block.Block)
+		}
+		if monitoredAccount.NotifyNewBlocks && !monitoredAccount.DigestOnly {
+			n.checkBlock(monitoredAccount, &block.Block)
+		}
+	}
+	return nil
+}
+
+func isDistribution(transaction *signumapi.Transaction) bool {
+	return transaction.Type == signumapi.TT_TOKENIZATION && transaction.Subtype == signumapi.TST_TOKENIZATION_DISTRIBUTION_TO_HOLDER
+}
+
+// getDistributionsHolders returns monitored holders of every asset distributed in the block,
+// one holders list is requested per asset instead of looking up every monitored account
+func (n *Notifier) getDistributionsHolders(block *signumapi.BlockWithTransactions) (map[string][]string, error) {
+	var holders = make(map[string][]string)
+	var monitoredAccountIDs map[string]bool
+	for i := range block.Transactions {
+		transaction := &block.Transactions[i]
+		if !isDistribution(transaction) {
+			continue
+		}
+		if _, ok := holders[transaction.Attachment.Asset]; ok {
+			continue
+		}
+		if monitoredAccountIDs == nil {
+			var err error
+			if monitoredAccountIDs, err = n.getMonitoredAccountIDs(); err != nil {
+				return nil, err
+			}
+		}
+		assetHolders, err := n.getMonitoredHolders(transaction.Attachment.Asset, monitoredAccountIDs)
+		if err != nil {
+			return nil, err
+		}
+		holders[transaction.Attachment.Asset] = assetHolders
+	}
+	return holders, nil
+}
+
+// getTransactionAccounts returns the sender and all direct and indirect recipients of the transaction,
+// recipients of distributions are the monitored holders of the asset only
+func getTransactionAccounts(transaction *signumapi.Transaction, monitoredHolders map[string][]string) []string {
+	var accounts = map[string]bool{transaction.Sender: true}
+	if transaction.Recipient != "" {
+		accounts[transaction.Recipient] = true
+	}
+
+	switch {
+	case transaction.Type == signumapi.TT_PAYMENT:
+		for _, recipient := range transaction.Attachment.Recipients.GetRecipients() {
+			accounts[recipient] = true
+		}
+	case isDistribution(transaction):
+		for _, holder := range monitoredHolders[transaction.Attachment.Asset] {
+			accounts[holder] = true
+		}
+	}
+
+	result := make([]string, 0, len(accounts))
+	for account := range accounts {
+		result = append(result, account)
+	}
+	return result
+}
+
+// getMonitoredHolders returns monitored accounts among the current holders of the asset.
+// The node lists holders at the chain head only, so an account which has sold the whole asset
+// after the distribution is missed while catching up, the exact amounts are checked by getIndirectIncoming later
+func (n *Notifier) getMonitoredHolders(asset string, monitoredAccountIDs map[string]bool) ([]string, error) {
+	assetAccounts, err := n.signumClient.GetAssetAccounts(n.logger, asset)
+	if err != nil {
+		return nil, fmt.Errorf("can't get holders of asset %v: %v", asset, err)
+	}
+	var holders []string
+	for _, assetAccount := range assetAccounts.AccountAssets {
+		if monitoredAccountIDs[assetAccount.Account] && assetAccount.QuantityQNT > 0 {
+			holders = append(holders, assetAccount.Account)
+		}
+	}
+	return holders, nil
+}
+
+// monitoredAccountsCondition selects accounts with any notifications or digests of active users
+const monitoredAccountsCondition = "exbot_db_accounts.notify_income_transactions = true OR exbot_db_accounts.notify_outgo_transactions = true " +
+	"OR exbot_db_accounts.notify_new_blocks = true OR exbot_db_accounts.notify_other_t_xs = true " +
+	"OR exbot_db_users.digest_period <> ''"
 
-	err := n.db.Model(&models.DbUser{}).Select("*").
+func (n *Notifier) getMonitoredAccountIDs() (map[string]bool, error) {
+	var accountIDs []string
+	err := n.db.Model(&models.DbUser{}).
 		Joins("join exbot_db_accounts on exbot_db_accounts.db_user_id = exbot_db_users.id").
-		Where("exbot_db_accounts.notify_income_transactions = true OR exbot_db_accounts.notify_outgo_transactions = true " +
-			"OR exbot_db_accounts.notify_new_blocks = true OR exbot_db_accounts.notify_other_t_xs = true").
+		Where("exbot_db_accounts.deleted_at IS NULL").
+		Where("exbot_db_users.inactive = false").
+		Where(monitoredAccountsCondition).
+		Distinct().Pluck("exbot_db_accounts.account", &accountIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("can't get monitored accounts: %v", err)
+	}
+	result := make(map[string]bool, len(accountIDs))
+	for _, accountID := range accountIDs {
+		result[accountID] = true
+	}
+	return result, nil
+}
+
+func (n *Notifier) getMonitoredAccounts(involvedAccounts map[string]bool) (map[string][]*MonitoredAccount, error) {
+	accounts := make([]string, 0, len(involvedAccounts))
+	for account := range involvedAccounts {
+		accounts = append(accounts, account)
+	}
+
+	var monitoredAccounts []MonitoredAccount
+	err := n.db.Model(&models.DbUser{}).
+		Select("exbot_db_users.user_name, exbot_db_users.chat_id, exbot_db_users.notification_threshold_nqt, "+
+			"exbot_db_users.digest_period, exbot_db_users.digest_only, exbot_db_accounts.*").
+		Joins("join exbot_db_accounts on exbot_db_accounts.db_user_id = exbot_db_users.id").
+		Where("exbot_db_accounts.account IN ?", accounts).
+		Where("exbot_db_users.inactive = false").
+		Where(monitoredAccountsCondition).
 		Scan(&monitoredAccounts).Error
 	if err != nil {
-		n.logger.Errorf("Can't get monitored accounts: %v", err)
-		return
+		return nil, fmt.Errorf("can't get monitored accounts: %v", err)
 	}
+	n.loadRules(monitoredAccounts)
 
-	for _, account := range monitoredAccounts {
-		n.logger.Debugf("Notifier will request data for account %v (intx %v, outtx %v, block %v)", account.AccountRS,
-			account.NotifyIncomeTransactions, account.NotifyOutgoTransactions, account.NotifyNewBlocks)
+	result := make(map[string][]*MonitoredAccount, len(monitoredAccounts))
+	for i := range monitoredAccounts {
+		account := &monitoredAccounts[i]
+		result[account.Account] = append(result[account.Account], account)
+	}
+	return result, nil
+}
+
+func (n *Notifier) checkTransaction(account *MonitoredAccount, transaction *signumapi.Transaction) {
+	n.logger.Debugf("Notifier checks transaction %v for account %v (intx %v, outtx %v, other %v)", transaction.TransactionID,
+		account.AccountRS, account.NotifyIncomeTransactions, account.NotifyOutgoTransactions, account.NotifyOtherTXs)
 
+	switch transaction.Type {
+	case signumapi.TT_PAYMENT:
 		if account.NotifyIncomeTransactions || account.NotifyOutgoTransactions {
-			n.checkPaymentTransactions(&account)
-			n.checkATPaymentTransactions(&account)
-			n.checkTokenizationTransactions(&account)
+			n.checkPaymentTransaction(account, transaction)
 		}
-
+	case signumapi.TT_AUTOMATED_TRANSACTIONS:
+		if transaction.Subtype == signumapi.TST_AT_PAYMENT &&
+			(account.NotifyIncomeTransactions || account.NotifyOutgoTransactions) {
+			n.checkATPaymentTransaction(account, transaction)
+		}
+	case signumapi.TT_TOKENIZATION:
+		switch transaction.Subtype {
+		case signumapi.TST_TOKENIZATION_DISTRIBUTION_TO_HOLDER:
+			if account.NotifyIncomeTransactions || account.NotifyOutgoTransactions {
+				n.checkTokenizationTransaction(account, transaction)
+			}
+		case signumapi.TST_ASSET_TRANSFER:
+			if account.NotifyIncomeTransactions || account.NotifyOutgoTransactions {
+				n.checkAssetTransferTransaction(account, transaction)
+			}
+		case signumapi.TST_ASK_ORDER_PLACEMENT, signumapi.TST_BID_ORDER_PLACEMENT,
+			signumapi.TST_ASK_ORDER_CANCELLATION, signumapi.TST_BID_ORDER_CANCELLATION:
+			if account.NotifyOtherTXs {
+				n.checkAssetOrderTransaction(account, transaction)
+			}
+		}
+	case signumapi.TT_BURST_MINING, signumapi.TT_ACCOUNT_CONTROL:
 		if account.NotifyOtherTXs {
-			n.checkMiningTransactions(&account)
-			n.checkMessageTransactions(&account)
+			n.checkMiningTransaction(account, transaction)
 		}
-
-		if account.NotifyNewBlocks {
-			n.checkBlocks(&account)
+	case signumapi.TT_MESSAGING:
+		if !account.NotifyOtherTXs {
+			return
+		}
+		switch transaction.Subtype {
+		case signumapi.TST_ARBITRARY_MESSAGE:
+			n.checkMessageTransaction(account, transaction)
+		case signumapi.TST_ALIAS_SELL, signumapi.TST_ALIAS_BUY:
+			n.checkAliasTransaction(account, transaction)
+		}
+	case signumapi.TT_DIGITAL_GOODS:
+		if account.NotifyOtherTXs {
+			n.checkMarketplaceTransaction(account, transaction)
 		}
 	}
 }
@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"fmt"
+	"html"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+// checkMarketplaceTransaction notifies sellers about purchase orders and feedback and buyers about deliveries and refunds,
+// own marketplace transactions of the account aren't notified
+func (n *Notifier) checkMarketplaceTransaction(account *MonitoredAccount, transaction *signumapi.Transaction) {
+	if transaction.Sender == account.Account {
+		return
+	}
+	if !matchRules(account.Rules, newRuleSubject(account, transaction)) {
+		return
+	}
+
+	senderName := n.signumClient.GetCachedAccountName(n.logger, transaction.Sender)
+	if senderName != "" {
+		senderName = "\n<i>Name:</i> " + senderName
+	}
+
+	msg, accountIfAlias := formatAccountHeader("🛒", account)
+
+	var eventType models.EventType
+	var amountNQT int64
+	switch transaction.Subtype {
+	case signumapi.TST_DGS_PURCHASE:
+		var goodsName = transaction.Attachment.Goods
+		goods, err := n.signumClient.GetGoods(n.logger, transaction.Attachment.Goods)
+		if err != nil {
+			n.logger.Errorf("Can't get goods %v: %v", transaction.Attachment.Goods, err)
+		} else {
+			goodsName = goods.Name
+		}
+		priceNQT := transaction.GetPriceNQT()
+		eventType = EVENT_MARKETPLACE_PURCHASE
+		msg += fmt.Sprintf("new purchase order:"+accountIfAlias+
+			"\n<i>Goods:</i> %v"+
+			"\n<i>Buyer:</i> %v"+senderName+
+			"\n<i>Quantity:</i> %v"+
+			"\n<i>Price:</i> %v SIGNA"+
+			"\n<i>Total:</i> %v SIGNA"+
+			"\n<i>Deliver until:</i> %v",
+			html.EscapeString(goodsName), transaction.SenderRS, transaction.Attachment.Quantity,
+			common.FormatNQT(priceNQT), common.FormatNQT(transaction.Attachment.Quantity*priceNQT),
+			common.FormatChainTimeToStringDatetimeUTC(transaction.Attachment.DeliveryDeadline))
+	case signumapi.TST_DGS_DELIVERY, signumapi.TST_DGS_FEEDBACK, signumapi.TST_DGS_REFUND:
+		purchase, err := n.signumClient.GetDGSPurchase(n.logger, transaction.Attachment.Purchase)
+		if err != nil {
+			n.logger.Errorf("Can't get purchase %v: %v", transaction.Attachment.Purchase, err)
+			purchase = &signumapi.Purchase{Name: transaction.Attachment.Purchase}
+		}
+		switch transaction.Subtype {
+		case signumapi.TST_DGS_DELIVERY:
+			eventType = EVENT_MARKETPLACE_DELIVERY
+			paidNQT := purchase.GetAmountNQT() - transaction.Attachment.DiscountNQT
+			amountNQT = -int64(paidNQT)
+			msg += fmt.Sprintf("goods delivered:"+accountIfAlias+
+				"\n<i>Goods:</i> %v"+
+				"\n<i>Seller:</i> %v"+senderName+
+				"\n<i>Amount:</i> -%v SIGNA",
+				html.EscapeString(purchase.Name), transaction.SenderRS, common.FormatNQT(paidNQT))
+			if transaction.Attachment.DiscountNQT > 0 {
+				msg += fmt.Sprintf("\n<i>Discount:</i> %v SIGNA", common.FormatNQT(transaction.Attachment.DiscountNQT))
+			}
+		case signumapi.TST_DGS_FEEDBACK:
+			eventType = EVENT_MARKETPLACE_FEEDBACK
+			msg += fmt.Sprintf("new feedback:"+accountIfAlias+
+				"\n<i>Goods:</i> %v"+
+				"\n<i>Buyer:</i> %v"+senderName,
+				html.EscapeString(purchase.Name), transaction.SenderRS)
+			if transaction.Attachment.MessageIsText && transaction.Attachment.Message != "" {
+				msg += "\n<i>Feedback:</i> " + html.EscapeString(transaction.Attachment.Message)
+			}
+		default:
+			eventType = EVENT_MARKETPLACE_REFUND
+			amountNQT = int64(transaction.Attachment.RefundNQT)
+			msg += fmt.Sprintf("purchase refunded:"+accountIfAlias+
+				"\n<i>Goods:</i> %v"+
+				"\n<i>Seller:</i> %v"+senderName+
+				"\n<i>Amount:</i> +%v SIGNA",
+				html.EscapeString(purchase.Name), transaction.SenderRS, common.FormatNQT(transaction.Attachment.RefundNQT))
+		}
+	default:
+		return // listings of other accounts aren't interesting
+	}
+
+	n.notify(NotifierMessage{
+		UserName: account.UserName,
+		ChatID:   account.ChatID,
+		BatchKey: account.AccountRS,
+		Message:  msg,
+		Event:    newTransactionEvent(eventType, account, transaction, amountNQT),
+	})
+}
@@ -2,84 +2,97 @@ package notifier
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/xDWart/signum-explorer-bot/api/signumapi"
 	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
 )
 
-func (n *Notifier) checkMiningTransactions(account *MonitoredAccount) {
-	userTransactions, err := n.signumClient.GetCachedAccountMiningTransactions(n.logger, account.Account)
-	if err != nil {
-		n.logger.Errorf("Can't get last account %v mining transactions: %v", account.Account, err)
+func (n *Notifier) checkMiningTransaction(account *MonitoredAccount, transaction *signumapi.Transaction) {
+	if !matchRules(account.Rules, newRuleSubject(account, transaction)) {
 		return
 	}
 
-	if userTransactions == nil || len(userTransactions.Transactions) == 0 {
-		return
+	var msg, accountIfAlias string
+	if account.Alias != "" {
+		msg = fmt.Sprintf("📝 <b>%v</b> ", account.Alias)
+		accountIfAlias = "\n<i>Account:</i> " + account.AccountRS
+	} else {
+		msg = fmt.Sprintf("📝 <b>%v</b> ", account.AccountRS)
 	}
 
-	lastTransaction := userTransactions.Transactions[0]
-	if lastTransaction.TransactionID == account.LastMiningTX ||
-		lastTransaction.Height <= account.LastMiningH {
-		return
+	var totalCommitment string
+	newAccount, err := n.signumClient.GetAccount(n.logger, account.Account)
+	if err != nil {
+		n.logger.Errorf("Error getting account %v: %v", account.Account, err)
+	} else {
+		totalCommitment = fmt.Sprintf("\n<b>Total commitment: %v SIGNA</b>", common.FormatNQT(newAccount.CommittedBalanceNQT))
 	}
 
-	for _, transaction := range userTransactions.Transactions {
-		if transaction.TransactionID == account.LastMiningTX {
-			break
-		}
-
-		var msg, accountIfAlias string
-		if account.Alias != "" {
-			msg = fmt.Sprintf("📝 <b>%v</b> ", account.Alias)
-			accountIfAlias = "\n<i>Account:</i> " + account.AccountRS
-		} else {
-			msg = fmt.Sprintf("📝 <b>%v</b> ", account.AccountRS)
-		}
-
-		var totalCommitment string
-		newAccount, err := n.signumClient.GetAccount(n.logger, account.Account)
-		if err != nil {
-			n.logger.Errorf("Error getting account %v: %v", account.Account, err)
-		} else {
-			totalCommitment = fmt.Sprintf("\n<b>Total commitment: %v SIGNA</b>", common.FormatNQT(newAccount.CommittedBalanceNQT))
-		}
-
-		switch transaction.Subtype {
-		case signumapi.TST_REWARD_RECIPIENT_ASSIGNMENT:
-			recipientName := n.signumClient.GetCachedAccountName(n.logger, transaction.Recipient)
-			if recipientName != "" {
-				recipientName = "\n<i>Name:</i> " + recipientName
+	var eventType = EVENT_COMMITMENT
+	var amountNQT int64
+	switch {
+	case transaction.Type == signumapi.TT_ACCOUNT_CONTROL && transaction.Subtype == signumapi.TST_EFFECTIVE_BALANCE_LEASING:
+		eventType = EVENT_LEASING
+		var period = fmt.Sprintf("%v blocks (~%v)", transaction.Attachment.Period,
+			common.FormatPeriod(time.Duration(transaction.Attachment.Period)*config.BLOCK_TIME))
+		if transaction.Sender == account.Account {
+			lesseeName := n.signumClient.GetCachedAccountName(n.logger, transaction.Recipient)
+			if lesseeName != "" {
+				lesseeName = "\n<i>Name:</i> " + lesseeName
 			}
-
-			msg += fmt.Sprintf("new reward recipient assigned:"+accountIfAlias+
-				"\n<i>Recipient:</i> %v"+recipientName+
-				"\n<i>Fee:</i> %v SIGNA",
-				transaction.RecipientRS, common.ConvertFeeNQT(transaction.FeeNQT))
-		case signumapi.TST_ADD_COMMITMENT:
-			msg += fmt.Sprintf("new commitment added:"+accountIfAlias+
-				"\n<i>Amount:</i> +%v SIGNA"+
+			msg += fmt.Sprintf("effective balance leased:"+accountIfAlias+
+				"\n<i>Lessee:</i> %v"+lesseeName+
+				"\n<i>Period:</i> %v"+
 				"\n<i>Fee:</i> %v SIGNA",
-				common.FormatNQT(transaction.Attachment.AmountNQT), common.ConvertFeeNQT(transaction.FeeNQT))
-		case signumapi.TST_REMOVE_COMMITMENT:
-			msg += fmt.Sprintf("commitment revoked:"+accountIfAlias+
-				"\n<i>Amount:</i> -%v SIGNA"+
-				"\n<i>Fee:</i> %v SIGNA",
-				common.FormatNQT(transaction.Attachment.AmountNQT), common.ConvertFeeNQT(transaction.FeeNQT))
-		default:
-			n.logger.Errorf("%v: unknown SubType (%v) for transaction %v", account.Account, transaction.Subtype, transaction.TransactionID)
-			continue
+				transaction.RecipientRS, period, common.ConvertFeeNQT(transaction.FeeNQT))
+		} else {
+			lessorName := n.signumClient.GetCachedAccountName(n.logger, transaction.Sender)
+			if lessorName != "" {
+				lessorName = "\n<i>Name:</i> " + lessorName
+			}
+			msg += fmt.Sprintf("effective balance leased to you:"+accountIfAlias+
+				"\n<i>Lessor:</i> %v"+lessorName+
+				"\n<i>Period:</i> %v",
+				transaction.SenderRS, period)
 		}
-
-		n.notifierCh <- NotifierMessage{
-			UserName: account.UserName,
-			ChatID:   account.ChatID,
-			Message:  msg + totalCommitment,
+	case transaction.Type == signumapi.TT_ACCOUNT_CONTROL:
+		n.logger.Errorf("%v: unknown SubType (%v) for transaction %v", account.Account, transaction.Subtype, transaction.TransactionID)
+		return
+	case transaction.Subtype == signumapi.TST_REWARD_RECIPIENT_ASSIGNMENT:
+		eventType = EVENT_REWARD_RECIPIENT
+		recipientName := n.signumClient.GetCachedAccountName(n.logger, transaction.Recipient)
+		if recipientName != "" {
+			recipientName = "\n<i>Name:</i> " + recipientName
 		}
-	}
 
-	account.DbAccount.LastMiningTX = lastTransaction.TransactionID
-	account.DbAccount.LastMiningH = lastTransaction.Height
-	if err := n.db.Save(&account.DbAccount).Error; err != nil {
-		n.logger.Errorf("Error saving account: %v", err)
+		msg += fmt.Sprintf("new reward recipient assigned:"+accountIfAlias+
+			"\n<i>Recipient:</i> %v"+recipientName+
+			"\n<i>Fee:</i> %v SIGNA",
+			transaction.RecipientRS, common.ConvertFeeNQT(transaction.FeeNQT))
+	case transaction.Subtype == signumapi.TST_ADD_COMMITMENT:
+		amountNQT = int64(transaction.Attachment.AmountNQT)
+		msg += fmt.Sprintf("new commitment added:"+accountIfAlias+
+			"\n<i>Amount:</i> +%v SIGNA"+
+			"\n<i>Fee:</i> %v SIGNA",
+			common.FormatNQT(transaction.Attachment.AmountNQT), common.ConvertFeeNQT(transaction.FeeNQT))
+	case transaction.Subtype == signumapi.TST_REMOVE_COMMITMENT:
+		amountNQT = -int64(transaction.Attachment.AmountNQT)
+		msg += fmt.Sprintf("commitment revoked:"+accountIfAlias+
+			"\n<i>Amount:</i> -%v SIGNA"+
+			"\n<i>Fee:</i> %v SIGNA",
+			common.FormatNQT(transaction.Attachment.AmountNQT), common.ConvertFeeNQT(transaction.FeeNQT))
+	default:
+		n.logger.Errorf("%v: unknown SubType (%v) for transaction %v", account.Account, transaction.Subtype, transaction.TransactionID)
+		return
 	}
+
+	n.notify(NotifierMessage{
+		UserName: account.UserName,
+		ChatID:   account.ChatID,
+		BatchKey: account.AccountRS,
+		Message:  msg + totalCommitment,
+		Event:    newTransactionEvent(eventType, account, transaction, amountNQT),
+	})
 }
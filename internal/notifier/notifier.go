@@ -1,10 +1,12 @@
 package notifier
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
 	"github.com/xDWart/signum-explorer-bot/internal/database/models"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -12,39 +14,77 @@ import (
 
 type Notifier struct {
 	sync.RWMutex
-	db           *gorm.DB
-	logger       *zap.SugaredLogger
-	signumClient *signumapi.SignumApiClient
-	notifierCh   chan NotifierMessage
-	config       *Config
+	db                *gorm.DB
+	logger            *zap.SugaredLogger
+	signumClient      *signumapi.SignumApiClient
+	sink              Sink
+	config            *Config
+	lastScannedHeight uint64
+	watchdogAccounts  map[string]bool // senders of watchdog transactions in the scanned blocks, they are checked after the scan
+
+	advancedPaymentsHeight uint64 // advanced payments are checked up to this height, it is stored like the last scanned height
 }
 
 type Config struct {
-	NotifierPeriod time.Duration
+	NotifierPeriod         time.Duration
+	Confirmations          uint64 // scan only blocks having at least this number of confirmations
+	MaxBlocksPerScan       uint64 // limit for catching up after downtime, the rest will be scanned by the next ticks
+	MempoolPeriod          time.Duration
+	MempoolMissedScans     uint          // the transaction is reported as dropped if it is not found after this number of scans
+	AdvancedPaymentsPeriod time.Duration // subscription payments and escrow results are requested for every monitored account
+	WatchdogPeriod         time.Duration // all accounts are checked besides the ones having watchdog transactions in the scanned blocks
+	BlockHandlers          []BlockHandler
+	ForgingStats           func(account string, plot *models.MiningAlert) string // forged blocks summary of the found block message
 }
 
+// BlockHandler is called for every scanned block before its notifications, the block is scanned again if an error is returned
+type BlockHandler func(block *signumapi.BlockWithTransactions) error
+
 type NotifierMessage struct {
 	UserName string
 	ChatID   int64
+	BatchKey string // messages with the same key could be combined by the outbox, empty if the message is edited later
 	Message  string
+	Event    *models.NotificationEvent
+	Urgent   bool // delivered during quiet hours too
+
+	NotificationID uint // set by the outbox sink, the notification is edited if the message is sent with it
 }
 
 type MonitoredAccount struct {
 	UserName                 string
 	ChatID                   int64
 	NotificationThresholdNQT uint64
+	DigestPeriod             models.DigestPeriod
+	DigestOnly               bool
 	models.DbAccount
+	Rules []models.NotificationRule `gorm:"-"`
 }
 
-func NewNotifier(logger *zap.SugaredLogger, db *gorm.DB, signumClient *signumapi.SignumApiClient, notifierCh chan NotifierMessage, wg *sync.WaitGroup, shutdownChannel chan interface{}, config *Config) *Notifier {
+func NewNotifier(logger *zap.SugaredLogger, db *gorm.DB, signumClient *signumapi.SignumApiClient, sink Sink, wg *sync.WaitGroup, shutdownChannel chan interface{}, config *Config) *Notifier {
 	notifier := &Notifier{
-		db:           db,
-		logger:       logger,
-		signumClient: signumClient,
-		notifierCh:   notifierCh,
-		config:       config,
+		db:               db,
+		logger:           logger,
+		signumClient:     signumClient,
+		sink:             sink,
+		config:           config,
+		watchdogAccounts: make(map[string]bool),
 	}
+	notifier.readLastScannedHeight()
+	notifier.readAdvancedPaymentsHeight()
 	wg.Add(1)
 	go notifier.startListener(wg, shutdownChannel)
 	return notifier
 }
+
+// formatAccountHeader returns the message beginning and the account line to be added if the account has an alias
+func formatAccountHeader(icon string, account *MonitoredAccount) (msg string, accountIfAlias string) {
+	if account.Alias != "" {
+		return fmt.Sprintf("%v <b>%v</b> ", icon, account.Alias), "\n<i>Account:</i> " + account.AccountRS
+	}
+	return fmt.Sprintf("%v <b>%v</b> ", icon, account.AccountRS), ""
+}
+
+func formatTotalBalance(totalBalanceNQT uint64) string {
+	return fmt.Sprintf("\n<b>Total balance: %v SIGNA</b>", common.FormatNQT(totalBalanceNQT))
+}
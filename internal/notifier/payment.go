@@ -10,209 +10,177 @@ import (
 	"github.com/xDWart/signum-explorer-bot/internal/database/models"
 )
 
-func (n *Notifier) checkPaymentTransactions(account *MonitoredAccount) {
-	userTransactions, err := n.signumClient.GetCachedAccountPaymentTransactions(n.logger, account.Account)
-	if err != nil {
-		n.logger.Errorf("Can't get last account %v payment transactions: %v", account.Account, err)
-		return
-	}
+func (n *Notifier) checkPaymentTransaction(account *MonitoredAccount, transaction *signumapi.Transaction) {
+	msg, accountIfAlias := formatAccountHeader("💸", account)
+
+	var incomeTransaction = transaction.Sender != account.Account
+	var name string
+	if incomeTransaction {
+		if !account.NotifyIncomeTransactions {
+			return
+		}
 
-	if userTransactions == nil || len(userTransactions.Transactions) == 0 {
+		name = n.signumClient.GetCachedAccountName(n.logger, transaction.Sender)
+		if name != "" {
+			name = "\n<i>Name:</i> " + name
+		}
+	} else if account.NotifyOutgoTransactions { // outgo
+		if transaction.Recipient != "" {
+			name = n.signumClient.GetCachedAccountName(n.logger, transaction.Recipient)
+			if name != "" {
+				name = "\n<i>Name:</i> " + name
+			}
+		}
+	} else {
 		return
 	}
 
-	lastTransaction := userTransactions.Transactions[0]
-	if lastTransaction.TransactionID == account.LastTransactionID ||
-		lastTransaction.Height <= account.LastTransactionH {
-		return
+	var message string
+	if transaction.Attachment.MessageIsText && transaction.Attachment.Message != "" {
+		transaction.Attachment.Message = strings.ReplaceAll(transaction.Attachment.Message, "\n", " ")
+		message = fmt.Sprintf("\n<i>Message:</i> %v", transaction.Attachment.Message)
+	} else if transaction.Attachment.EncryptedMessage != nil {
+		message = fmt.Sprintf("\n<i>Message:</i> [encrypted]")
 	}
 
-	n.logger.Debugf("Account %v: lastTransaction.TransactionID = %v (%v), account.LastTransactionID = %v (%v)",
-		account.Account, lastTransaction.TransactionID, lastTransaction.Height,
-		account.LastTransactionID, account.LastTransactionH)
+	var amount float64
+	var outgoAccount string
+	var outgoAccountRS string
+	switch transaction.Subtype {
+	case signumapi.TST_ORDINARY_PAYMENT:
+		amount = transaction.GetAmount()
 
-	var totalBalance string
-	newAccount, err := n.signumClient.GetAccount(n.logger, account.Account)
-	if err == nil {
-		totalBalance = fmt.Sprintf("\n<b>Total balance: %v SIGNA</b>", common.FormatNQT(newAccount.TotalBalanceNQT))
-	}
-
-	for _, transaction := range userTransactions.Transactions {
-		if transaction.TransactionID == account.LastTransactionID {
-			break
+		if transaction.GetAmountNQT() < account.NotificationThresholdNQT &&
+			account.AccountRS != config.FAUCET_ACCOUNT {
+			return
 		}
 
-		var msg, accountIfAlias string
-		if account.Alias != "" {
-			msg = fmt.Sprintf("💸 <b>%v</b> ", account.Alias)
-			accountIfAlias = "\n<i>Account:</i> " + account.AccountRS
+		if incomeTransaction {
+			msg += fmt.Sprintf("new income:"+accountIfAlias+
+				"\n<i>Payment:</i> Ordinary"+
+				"\n<i>Sender:</i> %v"+
+				name+
+				"\n<i>Amount:</i> +%v SIGNA"+
+				message+
+				"\n<i>Fee:</i> %v SIGNA",
+				transaction.SenderRS, common.FormatNQT(transaction.GetAmountNQT()), common.ConvertFeeNQT(transaction.FeeNQT))
 		} else {
-			msg = fmt.Sprintf("💸 <b>%v</b> ", account.AccountRS)
+			msg += fmt.Sprintf("new outgo:"+accountIfAlias+
+				"\n<i>Payment:</i> Ordinary"+
+				"\n<i>Recipient:</i> %v"+
+				name+
+				"\n<i>Amount:</i> -%v SIGNA"+
+				message+
+				"\n<i>Fee:</i> %v SIGNA",
+				transaction.RecipientRS, common.FormatNQT(transaction.GetAmountNQT()), common.ConvertFeeNQT(transaction.FeeNQT))
+			outgoAccount = transaction.Recipient
+			outgoAccountRS = transaction.RecipientRS
 		}
-
-		var incomeTransaction = transaction.Sender != account.Account
-		var name string
+	case signumapi.TST_MULTI_OUT_PAYMENT:
 		if incomeTransaction {
-			if !account.NotifyIncomeTransactions {
-				continue
-			}
+			amount = transaction.GetMyMultiOutAmount(account.Account)
 
-			name = n.signumClient.GetCachedAccountName(n.logger, transaction.Sender)
-			if name != "" {
-				name = "\n<i>Name:</i> " + name
-			}
-		} else if account.NotifyOutgoTransactions { // outgo
-			if transaction.Recipient != "" {
-				name = n.signumClient.GetCachedAccountName(n.logger, transaction.Recipient)
-				if name != "" {
-					name = "\n<i>Name:</i> " + name
-				}
+			amountNQT := transaction.GetMyMultiOutAmountNQT(account.Account)
+			if amountNQT < account.NotificationThresholdNQT {
+				return
 			}
+
+			msg += fmt.Sprintf("new income:"+accountIfAlias+
+				"\n<i>Payment:</i> Multi-out"+
+				"\n<i>Sender:</i> %v"+
+				name+
+				"\n<i>Amount:</i> +%v SIGNA"+
+				message+
+				"\n<i>Fee:</i> %v SIGNA",
+				transaction.SenderRS, common.FormatNQT(amountNQT), common.ConvertFeeNQT(transaction.FeeNQT))
 		} else {
-			continue
-		}
+			amount = transaction.GetAmount()
+
+			if transaction.GetAmountNQT() < account.NotificationThresholdNQT {
+				return
+			}
 
-		var message string
-		if transaction.Attachment.MessageIsText && transaction.Attachment.Message != "" {
-			transaction.Attachment.Message = strings.ReplaceAll(transaction.Attachment.Message, "\n", " ")
-			message = fmt.Sprintf("\n<i>Message:</i> %v", transaction.Attachment.Message)
-		} else if transaction.Attachment.EncryptedMessage != nil {
-			message = fmt.Sprintf("\n<i>Message:</i> [encrypted]")
+			msg += fmt.Sprintf("new outgo:"+accountIfAlias+
+				"\n<i>Payment:</i> Multi-out"+
+				"\n<i>Recipients:</i> %v"+
+				"\n<i>Amount:</i> -%v SIGNA"+
+				message+
+				"\n<i>Fee:</i> %v SIGNA",
+				len(transaction.Attachment.Recipients), common.FormatNQT(transaction.GetAmountNQT()), common.ConvertFeeNQT(transaction.FeeNQT))
 		}
+	case signumapi.TST_MULTI_OUT_SAME_PAYMENT:
+		if incomeTransaction {
+			amount = transaction.GetMultiOutSameAmount()
 
-		var amount float64
-		var outgoAccount string
-		var outgoAccountRS string
-		switch transaction.Subtype {
-		case signumapi.TST_ORDINARY_PAYMENT:
+			amountNQT := transaction.GetMultiOutSameAmountNQT()
+			if amountNQT < account.NotificationThresholdNQT {
+				return
+			}
+
+			msg += fmt.Sprintf("new income:"+accountIfAlias+
+				"\n<i>Payment:</i> Multi-out same"+
+				"\n<i>Sender:</i> %v"+
+				name+
+				"\n<i>Amount:</i> +%v SIGNA"+
+				message+
+				"\n<i>Fee:</i> %v SIGNA",
+				transaction.SenderRS, common.FormatNQT(transaction.GetMultiOutSameAmountNQT()), common.ConvertFeeNQT(transaction.FeeNQT))
+		} else {
 			amount = transaction.GetAmount()
 
-			if transaction.GetAmountNQT() < account.NotificationThresholdNQT &&
-				account.AccountRS != config.FAUCET_ACCOUNT {
-				continue
+			if transaction.GetAmountNQT() < account.NotificationThresholdNQT {
+				return
 			}
 
-			if incomeTransaction {
-				msg += fmt.Sprintf("new income:"+accountIfAlias+
-					"\n<i>Payment:</i> Ordinary"+
-					"\n<i>Sender:</i> %v"+
-					name+
-					"\n<i>Amount:</i> +%v SIGNA"+
-					message+
-					"\n<i>Fee:</i> %v SIGNA",
-					transaction.SenderRS, common.FormatNQT(transaction.GetAmountNQT()), common.ConvertFeeNQT(transaction.FeeNQT))
-			} else {
-				msg += fmt.Sprintf("new outgo:"+accountIfAlias+
-					"\n<i>Payment:</i> Ordinary"+
-					"\n<i>Recipient:</i> %v"+
-					name+
-					"\n<i>Amount:</i> -%v SIGNA"+
-					message+
-					"\n<i>Fee:</i> %v SIGNA",
-					transaction.RecipientRS, common.FormatNQT(transaction.GetAmountNQT()), common.ConvertFeeNQT(transaction.FeeNQT))
-				outgoAccount = transaction.Recipient
-				outgoAccountRS = transaction.RecipientRS
-			}
-		case signumapi.TST_MULTI_OUT_PAYMENT:
-			if incomeTransaction {
-				amount = transaction.GetMyMultiOutAmount(account.Account)
-
-				amountNQT := transaction.GetMyMultiOutAmountNQT(account.Account)
-				if amountNQT < account.NotificationThresholdNQT {
-					continue
-				}
-
-				msg += fmt.Sprintf("new income:"+accountIfAlias+
-					"\n<i>Payment:</i> Multi-out"+
-					"\n<i>Sender:</i> %v"+
-					name+
-					"\n<i>Amount:</i> +%v SIGNA"+
-					message+
-					"\n<i>Fee:</i> %v SIGNA",
-					transaction.SenderRS, common.FormatNQT(amountNQT), common.ConvertFeeNQT(transaction.FeeNQT))
-			} else {
-				amount = transaction.GetAmount()
-
-				if transaction.GetAmountNQT() < account.NotificationThresholdNQT {
-					continue
-				}
-
-				msg += fmt.Sprintf("new outgo:"+accountIfAlias+
-					"\n<i>Payment:</i> Multi-out"+
-					"\n<i>Recipients:</i> %v"+
-					"\n<i>Amount:</i> -%v SIGNA"+
-					message+
-					"\n<i>Fee:</i> %v SIGNA",
-					len(transaction.Attachment.Recipients), common.FormatNQT(transaction.GetAmountNQT()), common.ConvertFeeNQT(transaction.FeeNQT))
-			}
-		case signumapi.TST_MULTI_OUT_SAME_PAYMENT:
-			if incomeTransaction {
-				amount = transaction.GetMultiOutSameAmount()
-
-				amountNQT := transaction.GetMultiOutSameAmountNQT()
-				if amountNQT < account.NotificationThresholdNQT {
-					continue
-				}
-
-				msg += fmt.Sprintf("new income:"+accountIfAlias+
-					"\n<i>Payment:</i> Multi-out same"+
-					"\n<i>Sender:</i> %v"+
-					name+
-					"\n<i>Amount:</i> +%v SIGNA"+
-					message+
-					"\n<i>Fee:</i> %v SIGNA",
-					transaction.SenderRS, common.FormatNQT(transaction.GetMultiOutSameAmountNQT()), common.ConvertFeeNQT(transaction.FeeNQT))
-			} else {
-				amount = transaction.GetAmount()
-
-				if transaction.GetAmountNQT() < account.NotificationThresholdNQT {
-					continue
-				}
-
-				msg += fmt.Sprintf("new outgo:"+accountIfAlias+
-					"\n<i>Payment:</i> Multi-out same"+
-					"\n<i>Recipients:</i> %v"+
-					"\n<i>Amount:</i> -%v SIGNA"+
-					message+
-					"\n<i>Fee:</i> %v SIGNA",
-					len(transaction.Attachment.Recipients), common.FormatNQT(transaction.GetAmountNQT()), common.ConvertFeeNQT(transaction.FeeNQT))
-			}
-		default:
-			n.logger.Errorf("%v: unknown SubType (%v) for transaction %v", account.Account, transaction.Subtype, transaction.TransactionID)
-			continue
+			msg += fmt.Sprintf("new outgo:"+accountIfAlias+
+				"\n<i>Payment:</i> Multi-out same"+
+				"\n<i>Recipients:</i> %v"+
+				"\n<i>Amount:</i> -%v SIGNA"+
+				message+
+				"\n<i>Fee:</i> %v SIGNA",
+				len(transaction.Attachment.Recipients), common.FormatNQT(transaction.GetAmountNQT()), common.ConvertFeeNQT(transaction.FeeNQT))
 		}
+	default:
+		n.logger.Errorf("%v: unknown SubType (%v) for transaction %v", account.Account, transaction.Subtype, transaction.TransactionID)
+		return
+	}
 
-		if account.AccountRS == config.FAUCET_ACCOUNT {
-			if incomeTransaction { // it's donate
-				newDonate := models.Donation{
-					Account:       transaction.Sender,
-					AccountRS:     transaction.SenderRS,
-					TransactionID: transaction.TransactionID,
-					Amount:        amount,
-				}
-				n.db.Save(&newDonate)
-			} else { // it's faucet
-				newFaucet := models.Faucet{
-					Account:       outgoAccount,
-					AccountRS:     outgoAccountRS,
-					TransactionID: transaction.TransactionID,
-					Amount:        amount,
-					Fee:           common.ConvertFeeNQT(transaction.FeeNQT),
-				}
-				n.db.Save(&newFaucet)
+	if account.AccountRS == config.FAUCET_ACCOUNT {
+		if incomeTransaction { // it's donate
+			newDonate := models.Donation{
+				Account:       transaction.Sender,
+				AccountRS:     transaction.SenderRS,
+				TransactionID: transaction.TransactionID,
+				Amount:        amount,
+			}
+			n.db.Save(&newDonate)
+		} else { // it's faucet
+			newFaucet := models.Faucet{
+				Account:       outgoAccount,
+				AccountRS:     outgoAccountRS,
+				TransactionID: transaction.TransactionID,
+				Amount:        amount,
+				Fee:           common.ConvertFeeNQT(transaction.FeeNQT),
 			}
+			n.db.Save(&newFaucet)
 		}
+	}
 
-		n.notifierCh <- NotifierMessage{
-			UserName: account.UserName,
-			ChatID:   account.ChatID,
-			Message:  msg + totalBalance,
-		}
+	if !matchRules(account.Rules, newRuleSubject(account, transaction)) {
+		return
 	}
 
-	account.DbAccount.LastTransactionID = lastTransaction.TransactionID
-	account.DbAccount.LastTransactionH = lastTransaction.Height
-	if err := n.db.Save(&account.DbAccount).Error; err != nil {
-		n.logger.Errorf("Error saving account: %v", err)
+	var totalBalance string
+	newAccount, err := n.signumClient.GetAccount(n.logger, account.Account)
+	if err == nil {
+		totalBalance = formatTotalBalance(newAccount.TotalBalanceNQT)
 	}
+
+	n.addOrConfirmPending(NotifierMessage{
+		UserName: account.UserName,
+		ChatID:   account.ChatID,
+		BatchKey: account.AccountRS,
+		Message:  msg + totalBalance,
+		Event:    newTransactionEvent(EVENT_PAYMENT, account, transaction, getSignedAmountNQT(account, transaction, getPaymentAmountNQT(account, transaction))),
+	}, transaction)
 }
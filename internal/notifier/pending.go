@@ -0,0 +1,267 @@
+package notifier
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+type pendingKey struct {
+	transactionID string
+	chatID        int64
+}
+
+func (n *Notifier) scanMempool() {
+	unconfirmedTransactions, err := n.signumClient.GetUnconfirmedTransactions(n.logger, "", true)
+	if err != nil {
+		n.logger.Errorf("Can't get unconfirmed transactions: %v", err)
+		return
+	}
+
+	var pendingTransactions []models.PendingTransaction
+	if err := n.db.Find(&pendingTransactions).Error; err != nil {
+		n.logger.Errorf("Can't get pending transactions: %v", err)
+		return
+	}
+	var alreadyNotified = make(map[pendingKey]bool, len(pendingTransactions))
+	for _, pendingTransaction := range pendingTransactions {
+		alreadyNotified[pendingKey{pendingTransaction.TransactionID, pendingTransaction.ChatID}] = true
+	}
+
+	var mempool = make(map[string]bool, len(unconfirmedTransactions.UnconfirmedTransactions))
+	var involvedAccounts = make(map[string]bool)
+	var transactionAccounts = make([][]string, len(unconfirmedTransactions.UnconfirmedTransactions))
+	for i := range unconfirmedTransactions.UnconfirmedTransactions {
+		transaction := &unconfirmedTransactions.UnconfirmedTransactions[i]
+		mempool[transaction.TransactionID] = true
+		if !isPendingTracked(transaction) {
+			continue
+		}
+		transactionAccounts[i] = getTransactionAccounts(transaction, nil) // tracked types are not distributions
+		for _, account := range transactionAccounts[i] {
+			involvedAccounts[account] = true
+		}
+	}
+
+	if len(involvedAccounts) > 0 {
+		monitoredAccounts, err := n.getMonitoredAccounts(involvedAccounts)
+		if err != nil {
+			n.logger.Errorf("Can't check mempool: %v", err)
+			return
+		}
+		for i := range unconfirmedTransactions.UnconfirmedTransactions {
+			transaction := &unconfirmedTransactions.UnconfirmedTransactions[i]
+			for _, account := range transactionAccounts[i] {
+				for _, monitoredAccount := range monitoredAccounts[account] {
+					if monitoredAccount.DigestOnly || alreadyNotified[pendingKey{transaction.TransactionID, monitoredAccount.ChatID}] {
+						continue
+					}
+					n.checkPendingTransaction(monitoredAccount, transaction)
+				}
+			}
+		}
+	}
+
+	for i := range pendingTransactions {
+		if !mempool[pendingTransactions[i].TransactionID] {
+			n.checkLeftMempoolTransaction(&pendingTransactions[i])
+		}
+	}
+}
+
+// isPendingTracked reports whether the unconfirmed transaction is notified, only payments and token transfers are tracked
+func isPendingTracked(transaction *signumapi.Transaction) bool {
+	return transaction.Type == signumapi.TT_PAYMENT ||
+		transaction.Type == signumapi.TT_TOKENIZATION && transaction.Subtype == signumapi.TST_ASSET_TRANSFER
+}
+
+func (n *Notifier) checkPendingTransaction(account *MonitoredAccount, transaction *signumapi.Transaction) {
+	var incomeTransaction = transaction.Sender != account.Account
+	if incomeTransaction && !account.NotifyIncomeTransactions {
+		return
+	}
+	if !incomeTransaction && !account.NotifyOutgoTransactions {
+		return
+	}
+	if !matchRules(account.Rules, newRuleSubject(account, transaction)) {
+		return
+	}
+
+	var msg string
+	var event *models.NotificationEvent
+	if transaction.Type == signumapi.TT_PAYMENT {
+		var amountNQT = getPaymentAmountNQT(account, transaction)
+		if amountNQT < account.NotificationThresholdNQT {
+			return
+		}
+		msg = formatPendingPayment(account, transaction, amountNQT)
+		event = newTransactionEvent(EVENT_PENDING_PAYMENT, account, transaction, getSignedAmountNQT(account, transaction, amountNQT))
+	} else {
+		asset, err := n.signumClient.GetCachedAsset(n.logger, transaction.Attachment.Asset)
+		if err != nil {
+			n.logger.Errorf("%v: can't get asset %v for transaction %v: %v", account.Account, transaction.Attachment.Asset, transaction.TransactionID, err)
+			return
+		}
+		msg = formatPendingAssetTransfer(account, transaction, asset)
+		event = newTransactionEvent(EVENT_PENDING_ASSET_TRANSFER, account, transaction, 0)
+	}
+
+	notificationID := n.notify(NotifierMessage{
+		UserName: account.UserName,
+		ChatID:   account.ChatID,
+		Message:  msg,
+		Event:    event,
+	})
+	if notificationID == 0 {
+		return
+	}
+
+	pendingTransaction := models.PendingTransaction{
+		TransactionID:  transaction.TransactionID,
+		ChatID:         account.ChatID,
+		Account:        account.Account,
+		NotificationID: notificationID,
+		Message:        msg,
+		AmountNQT:      event.AmountNQT,
+		ExpiresAt:      common.ChainTimeToTime(transaction.Timestamp).Add(time.Duration(transaction.Deadline) * time.Minute),
+	}
+	if err := n.db.Create(&pendingTransaction).Error; err != nil {
+		n.logger.Errorf("Error saving pending transaction %v: %v", transaction.TransactionID, err)
+	}
+}
+
+func formatPendingPayment(account *MonitoredAccount, transaction *signumapi.Transaction, amountNQT uint64) string {
+	msg, accountIfAlias := formatAccountHeader("⏳", account)
+	if transaction.Sender != account.Account {
+		return msg + fmt.Sprintf("pending income:"+accountIfAlias+
+			"\n<i>Sender:</i> %v"+
+			"\n<i>Amount:</i> +%v SIGNA"+
+			"\n<i>Fee:</i> %v SIGNA",
+			transaction.SenderRS, common.FormatNQT(amountNQT), common.ConvertFeeNQT(transaction.FeeNQT))
+	}
+	if transaction.Subtype == signumapi.TST_ORDINARY_PAYMENT {
+		return msg + fmt.Sprintf("pending outgo:"+accountIfAlias+
+			"\n<i>Recipient:</i> %v"+
+			"\n<i>Amount:</i> -%v SIGNA"+
+			"\n<i>Fee:</i> %v SIGNA",
+			transaction.RecipientRS, common.FormatNQT(amountNQT), common.ConvertFeeNQT(transaction.FeeNQT))
+	}
+	return msg + fmt.Sprintf("pending outgo:"+accountIfAlias+
+		"\n<i>Recipients:</i> %v"+
+		"\n<i>Amount:</i> -%v SIGNA"+
+		"\n<i>Fee:</i> %v SIGNA",
+		len(transaction.Attachment.Recipients), common.FormatNQT(amountNQT), common.ConvertFeeNQT(transaction.FeeNQT))
+}
+
+func formatPendingAssetTransfer(account *MonitoredAccount, transaction *signumapi.Transaction, asset *signumapi.Asset) string {
+	msg, accountIfAlias := formatAccountHeader("⏳", account)
+	if transaction.Sender != account.Account {
+		return msg + fmt.Sprintf("pending token income:"+accountIfAlias+
+			"\n<i>Token:</i> %v"+
+			"\n<i>Sender:</i> %v"+
+			"\n<i>Quantity:</i> +%v",
+			html.EscapeString(asset.Name), transaction.SenderRS, formatAssetQuantity(asset, transaction.GetQuantityQNT()))
+	}
+	return msg + fmt.Sprintf("pending token outgo:"+accountIfAlias+
+		"\n<i>Token:</i> %v"+
+		"\n<i>Recipient:</i> %v"+
+		"\n<i>Quantity:</i> -%v"+
+		"\n<i>Fee:</i> %v SIGNA",
+		html.EscapeString(asset.Name), transaction.RecipientRS, formatAssetQuantity(asset, transaction.GetQuantityQNT()),
+		common.ConvertFeeNQT(transaction.FeeNQT))
+}
+
+// checkLeftMempoolTransaction finds out whether the transaction has been confirmed, dropped or expired
+func (n *Notifier) checkLeftMempoolTransaction(pendingTransaction *models.PendingTransaction) {
+	transaction, err := n.signumClient.GetTransaction(n.logger, pendingTransaction.TransactionID)
+	if err != nil {
+		if !strings.Contains(err.Error(), "Unknown transaction") {
+			n.logger.Errorf("Can't get transaction %v: %v", pendingTransaction.TransactionID, err)
+			return
+		}
+
+		if time.Now().After(pendingTransaction.ExpiresAt) {
+			n.resolvePendingTransaction(pendingTransaction, NotifierMessage{
+				ChatID:  pendingTransaction.ChatID,
+				Message: pendingTransaction.Message + "\n⌛ <b>Expired</b>: the transaction deadline has passed",
+				Event:   newPendingEvent(EVENT_PENDING_EXPIRED, pendingTransaction),
+			})
+			return
+		}
+
+		// the node could be out of sync, so give it a few scans before reporting
+		pendingTransaction.MissedScans++
+		if pendingTransaction.MissedScans < n.config.MempoolMissedScans {
+			n.db.Save(pendingTransaction)
+			return
+		}
+		n.resolvePendingTransaction(pendingTransaction, NotifierMessage{
+			ChatID:  pendingTransaction.ChatID,
+			Message: pendingTransaction.Message + "\n❌ <b>Dropped</b> from the mempool",
+			Event:   newPendingEvent(EVENT_PENDING_DROPPED, pendingTransaction),
+		})
+		return
+	}
+
+	if transaction.Block == 0 || transaction.Height > n.lastScannedHeight {
+		return // still unconfirmed or the block will be checked by the block scanning
+	}
+
+	var eventType = EVENT_PAYMENT
+	if transaction.Type != signumapi.TT_PAYMENT {
+		eventType = EVENT_ASSET_TRANSFER
+	}
+
+	// the block has been scanned, but the confirmed transaction wasn't notified (e.g. notifications were disabled)
+	n.resolvePendingTransaction(pendingTransaction, NotifierMessage{
+		ChatID:  pendingTransaction.ChatID,
+		Message: pendingTransaction.Message + getConfirmedText(transaction.Height),
+		Event: &models.NotificationEvent{
+			Type:          eventType,
+			Account:       pendingTransaction.Account,
+			TransactionID: transaction.TransactionID,
+			AmountNQT:     pendingTransaction.AmountNQT,
+			Height:        transaction.Height,
+		},
+	})
+}
+
+// addOrConfirmPending edits the pending notification if the transaction has been already notified from the mempool
+func (n *Notifier) addOrConfirmPending(message NotifierMessage, transaction *signumapi.Transaction) {
+	var pendingTransaction models.PendingTransaction
+	n.db.Where("transaction_id = ? AND chat_id = ?", transaction.TransactionID, message.ChatID).Limit(1).Find(&pendingTransaction)
+	if pendingTransaction.ID == 0 {
+		n.notify(message)
+		return
+	}
+
+	message.Message += getConfirmedText(transaction.Height)
+	n.resolvePendingTransaction(&pendingTransaction, message)
+}
+
+// resolvePendingTransaction edits the pending notification, the webhook receives the message as a new one
+func (n *Notifier) resolvePendingTransaction(pendingTransaction *models.PendingTransaction, message NotifierMessage) {
+	message.NotificationID = pendingTransaction.NotificationID
+	n.notify(message)
+	if err := n.db.Unscoped().Delete(pendingTransaction).Error; err != nil {
+		n.logger.Errorf("Error deleting pending transaction %v: %v", pendingTransaction.TransactionID, err)
+	}
+}
+
+func newPendingEvent(eventType models.EventType, pendingTransaction *models.PendingTransaction) *models.NotificationEvent {
+	return &models.NotificationEvent{
+		Type:          eventType,
+		Account:       pendingTransaction.Account,
+		TransactionID: pendingTransaction.TransactionID,
+		AmountNQT:     pendingTransaction.AmountNQT,
+	}
+}
+
+func getConfirmedText(height uint64) string {
+	return fmt.Sprintf("\n✅ Confirmed in block <b>#%v</b>", height)
+}
@@ -0,0 +1,172 @@
+package notifier
+
+import (
+	"strings"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/contracts"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+// ruleSubject is a transaction from the point of view of the monitored account
+type ruleSubject struct {
+	transactionType    signumapi.TransactionType
+	transactionSubtype signumapi.TransactionSubType
+	counterparties     []string
+	hasAmount          bool // amount rules are skipped for transactions without amount
+	amountNQT          uint64
+	message            string
+}
+
+func newRuleSubject(account *MonitoredAccount, transaction *signumapi.Transaction) *ruleSubject {
+	subject := ruleSubject{
+		transactionType:    transaction.Type,
+		transactionSubtype: transaction.Subtype,
+	}
+
+	if transaction.Sender != account.Account {
+		subject.counterparties = []string{transaction.Sender}
+	} else if transaction.Recipient != "" {
+		subject.counterparties = []string{transaction.Recipient}
+	} else {
+		subject.counterparties = transaction.Attachment.Recipients.GetRecipients()
+	}
+
+	switch transaction.Type {
+	case signumapi.TT_PAYMENT:
+		subject.hasAmount = true
+		subject.amountNQT = getPaymentAmountNQT(account, transaction)
+	case signumapi.TT_AUTOMATED_TRANSACTIONS:
+		subject.hasAmount = true
+		subject.amountNQT = transaction.GetAmountNQT()
+	case signumapi.TT_TOKENIZATION:
+		switch transaction.Subtype {
+		case signumapi.TST_TOKENIZATION_DISTRIBUTION_TO_HOLDER:
+			subject.hasAmount = true
+			subject.amountNQT = transaction.GetAmountNQT()
+		case signumapi.TST_ASK_ORDER_PLACEMENT, signumapi.TST_BID_ORDER_PLACEMENT:
+			subject.hasAmount = true
+			subject.amountNQT = transaction.GetQuantityQNT() * transaction.GetPriceNQT()
+		}
+	case signumapi.TT_DIGITAL_GOODS:
+		switch transaction.Subtype {
+		case signumapi.TST_DGS_PURCHASE:
+			subject.hasAmount = true
+			subject.amountNQT = transaction.Attachment.Quantity * transaction.GetPriceNQT()
+		case signumapi.TST_DGS_REFUND:
+			subject.hasAmount = true
+			subject.amountNQT = transaction.Attachment.RefundNQT
+		}
+	case signumapi.TT_BURST_MINING:
+		if transaction.Subtype != signumapi.TST_REWARD_RECIPIENT_ASSIGNMENT {
+			subject.hasAmount = true
+			subject.amountNQT = transaction.Attachment.AmountNQT
+		}
+	}
+
+	if transaction.Attachment.MessageIsText {
+		subject.message = transaction.Attachment.Message
+	} else if transaction.Attachment.Message != "" { // AT messages are hex encoded
+		subject.message = contracts.DecodeMessage(transaction.Attachment.Message)
+	}
+
+	return &subject
+}
+
+// newTradeRuleSubject treats the trade as the filled order of the account
+func newTradeRuleSubject(account *MonitoredAccount, trade *signumapi.Trade) *ruleSubject {
+	subject := ruleSubject{
+		transactionType:    signumapi.TT_TOKENIZATION,
+		transactionSubtype: signumapi.TST_ASK_ORDER_PLACEMENT,
+		counterparties:     []string{trade.Buyer},
+		hasAmount:          true,
+		amountNQT:          trade.GetAmountNQT(),
+	}
+	if trade.Buyer == account.Account {
+		subject.transactionSubtype = signumapi.TST_BID_ORDER_PLACEMENT
+		subject.counterparties = []string{trade.Seller}
+	}
+	return &subject
+}
+
+// getPaymentAmountNQT returns the amount which has been received or sent by the account
+func getPaymentAmountNQT(account *MonitoredAccount, transaction *signumapi.Transaction) uint64 {
+	if transaction.Sender != account.Account {
+		switch transaction.Subtype {
+		case signumapi.TST_MULTI_OUT_PAYMENT:
+			return transaction.GetMyMultiOutAmountNQT(account.Account)
+		case signumapi.TST_MULTI_OUT_SAME_PAYMENT:
+			return transaction.GetMultiOutSameAmountNQT()
+		}
+	}
+	return transaction.GetAmountNQT()
+}
+
+// matchRules is the only place where the account rules are evaluated,
+// rules of different kinds are combined by AND and rules of the same kind by OR
+func matchRules(rules []models.NotificationRule, subject *ruleSubject) bool {
+	var matchedKinds = make(map[models.RuleKind]bool)
+	for _, rule := range rules {
+		var matched bool
+		switch rule.Kind {
+		case models.RULE_DENY:
+			for _, counterparty := range subject.counterparties {
+				if counterparty == rule.Account {
+					return false
+				}
+			}
+			continue
+		case models.RULE_ALLOW:
+			for _, counterparty := range subject.counterparties {
+				if counterparty == rule.Account {
+					matched = true
+				}
+			}
+		case models.RULE_AMOUNT:
+			if !subject.hasAmount {
+				continue
+			}
+			matched = subject.amountNQT >= rule.MinAmountNQT && (rule.MaxAmountNQT == 0 || subject.amountNQT <= rule.MaxAmountNQT)
+		case models.RULE_TYPE:
+			matched = int(subject.transactionType) == rule.TxType &&
+				(rule.TxSubtype < 0 || int(subject.transactionSubtype) == rule.TxSubtype)
+		case models.RULE_CONTAINS:
+			matched = strings.Contains(strings.ToLower(subject.message), strings.ToLower(rule.Text))
+		default:
+			continue
+		}
+		matchedKinds[rule.Kind] = matchedKinds[rule.Kind] || matched
+	}
+
+	for _, matched := range matchedKinds {
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *Notifier) loadRules(monitoredAccounts []MonitoredAccount) {
+	if len(monitoredAccounts) == 0 {
+		return
+	}
+
+	var accountsByID = make(map[uint][]*MonitoredAccount, len(monitoredAccounts))
+	var ids = make([]uint, 0, len(monitoredAccounts))
+	for i := range monitoredAccounts {
+		account := &monitoredAccounts[i]
+		accountsByID[account.ID] = append(accountsByID[account.ID], account)
+		ids = append(ids, account.ID)
+	}
+
+	var rules []models.NotificationRule
+	if err := n.db.Where("db_account_id IN ?", ids).Order("id").Find(&rules).Error; err != nil {
+		n.logger.Errorf("Can't get notification rules: %v", err)
+		return
+	}
+	for _, rule := range rules {
+		for _, account := range accountsByID[rule.DbAccountID] {
+			account.Rules = append(account.Rules, rule)
+		}
+	}
+}
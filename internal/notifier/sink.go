@@ -0,0 +1,144 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"gorm.io/gorm"
+)
+
+// Sink receives every notification, the telegram outbox and the webhook deliveries are sinks
+type Sink interface {
+	Send(message *NotifierMessage) error
+}
+
+// Sinks passes the message to all sinks, it is used by the price, mining and token alerts too
+type Sinks []Sink
+
+func (sinks Sinks) Send(message *NotifierMessage) error {
+	var errs []string
+	for _, sink := range sinks {
+		if err := sink.Send(message); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// OutboxSink stores the message into the telegram outbox
+type OutboxSink struct {
+	db *gorm.DB
+}
+
+func NewOutboxSink(db *gorm.DB) *OutboxSink {
+	return &OutboxSink{db: db}
+}
+
+// Send sets the id of the new notification to the message, the notification is edited instead if the message already has the id
+func (ob *OutboxSink) Send(message *NotifierMessage) error {
+	if message.NotificationID != 0 {
+		return ob.update(message.NotificationID, message.Message)
+	}
+
+	notification := models.NewNotification(message.ChatID, message.UserName, message.Message)
+	notification.BatchKey = message.BatchKey
+	notification.Urgent = message.Urgent
+	if err := ob.db.Create(notification).Error; err != nil {
+		return fmt.Errorf("can't add notification to outbox: %v", err)
+	}
+	message.NotificationID = notification.ID
+	return nil
+}
+
+// update replaces the message text, the already sent message will be edited
+func (ob *OutboxSink) update(notificationID uint, message string) error {
+	err := ob.db.Model(&models.Notification{}).
+		Where("id = ? AND status <> ?", notificationID, models.NOTIFICATION_FAILED).
+		Updates(map[string]interface{}{
+			"message":         message,
+			"status":          models.NOTIFICATION_PENDING,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("can't update notification %v in outbox: %v", notificationID, err)
+	}
+	return nil
+}
+
+const (
+	EVENT_PAYMENT                  models.EventType = "payment"
+	EVENT_PENDING_PAYMENT          models.EventType = "pending_payment"
+	EVENT_PENDING_ASSET_TRANSFER   models.EventType = "pending_asset_transfer"
+	EVENT_PENDING_DROPPED          models.EventType = "pending_dropped"
+	EVENT_PENDING_EXPIRED          models.EventType = "pending_expired"
+	EVENT_AT_PAYMENT               models.EventType = "at_payment"
+	EVENT_DISTRIBUTION             models.EventType = "distribution"
+	EVENT_ASSET_TRANSFER           models.EventType = "asset_transfer"
+	EVENT_ASSET_ORDER              models.EventType = "asset_order"
+	EVENT_ASSET_ORDER_CANCELLED    models.EventType = "asset_order_cancelled"
+	EVENT_ASSET_TRADE              models.EventType = "asset_trade"
+	EVENT_LARGE_TOKEN_TRANSFER     models.EventType = "large_token_transfer"
+	EVENT_ALIAS_TRANSFER           models.EventType = "alias_transfer"
+	EVENT_ALIAS_SALE               models.EventType = "alias_sale"
+	EVENT_ALIAS_PURCHASE           models.EventType = "alias_purchase"
+	EVENT_SUBSCRIPTION_PAYMENT     models.EventType = "subscription_payment"
+	EVENT_ESCROW_RESULT            models.EventType = "escrow_result"
+	EVENT_MARKETPLACE_PURCHASE     models.EventType = "marketplace_purchase"
+	EVENT_MARKETPLACE_DELIVERY     models.EventType = "marketplace_delivery"
+	EVENT_MARKETPLACE_FEEDBACK     models.EventType = "marketplace_feedback"
+	EVENT_MARKETPLACE_REFUND       models.EventType = "marketplace_refund"
+	EVENT_BLOCK                    models.EventType = "block"
+	EVENT_REWARD_RECIPIENT         models.EventType = "reward_recipient"
+	EVENT_COMMITMENT               models.EventType = "commitment"
+	EVENT_LEASING                  models.EventType = "leasing"
+	EVENT_MESSAGE                  models.EventType = "message"
+	EVENT_BALANCE_BELOW_FLOOR      models.EventType = "balance_below_floor"
+	EVENT_BALANCE_ABOVE_FLOOR      models.EventType = "balance_above_floor"
+	EVENT_BALANCE_CHANGE           models.EventType = "balance_change"
+	EVENT_DIGEST                   models.EventType = "digest"
+	EVENT_REWARD_RECIPIENT_CHANGED models.EventType = "reward_recipient_changed"
+	EVENT_COMMITMENT_CHANGED       models.EventType = "commitment_changed"
+	EVENT_PRICE_ALERT              models.EventType = "price_alert"
+	EVENT_DIFFICULTY_ALERT         models.EventType = "difficulty_alert"
+	EVENT_COMMITMENT_ALERT         models.EventType = "network_commitment_alert"
+	EVENT_TOKEN_PRICE_ALERT        models.EventType = "token_price_alert"
+)
+
+func newAccountEvent(eventType models.EventType, account *MonitoredAccount, amountNQT int64) *models.NotificationEvent {
+	return &models.NotificationEvent{
+		Type:      eventType,
+		Account:   account.Account,
+		AccountRS: account.AccountRS,
+		AmountNQT: amountNQT,
+	}
+}
+
+func newTransactionEvent(eventType models.EventType, account *MonitoredAccount, transaction *signumapi.Transaction, amountNQT int64) *models.NotificationEvent {
+	event := newAccountEvent(eventType, account, amountNQT)
+	event.TransactionID = transaction.TransactionID
+	event.Height = transaction.Height
+	return event
+}
+
+// getSignedAmountNQT returns the negative amount if the account is the sender
+func getSignedAmountNQT(account *MonitoredAccount, transaction *signumapi.Transaction, amountNQT uint64) int64 {
+	if transaction.Sender == account.Account {
+		return -int64(amountNQT)
+	}
+	return int64(amountNQT)
+}
+
+// notify passes the message to all sinks, returns the notification id
+func (n *Notifier) notify(message NotifierMessage) uint {
+	if err := n.sink.Send(&message); err != nil {
+		n.logger.Errorf("Can't send notification for user %v (Chat.ID %v): %v", message.UserName, message.ChatID, err)
+	}
+	return message.NotificationID
+}
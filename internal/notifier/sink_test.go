@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testSink struct {
+	messages []*NotifierMessage
+	err      error
+}
+
+func (s *testSink) Send(message *NotifierMessage) error {
+	s.messages = append(s.messages, message)
+	return s.err
+}
+
+func TestSinksSendToAll(t *testing.T) {
+	failing := &testSink{err: errors.New("outbox is down")}
+	working := &testSink{}
+	message := &NotifierMessage{ChatID: 1, Message: "test"}
+
+	err := Sinks{failing, working}.Send(message)
+	if err == nil || !strings.Contains(err.Error(), "outbox is down") {
+		t.Fatalf("expected the error of the failing sink, got %v", err)
+	}
+	if len(working.messages) != 1 || working.messages[0] != message {
+		t.Fatalf("the failing sink mustn't stop the next one, got %v messages", len(working.messages))
+	}
+}
@@ -0,0 +1,202 @@
+package notifier
+
+import (
+	"fmt"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+type watchedState struct {
+	rewardRecipient     string
+	committedBalanceNQT uint64
+}
+
+// isWatchdogTransaction returns true for reward recipient assignments and commitment changes, the sender is the changed account
+func isWatchdogTransaction(transaction *signumapi.Transaction) bool {
+	return transaction.Type == signumapi.TT_BURST_MINING && (transaction.Subtype == signumapi.TST_REWARD_RECIPIENT_ASSIGNMENT ||
+		transaction.Subtype == signumapi.TST_ADD_COMMITMENT || transaction.Subtype == signumapi.TST_REMOVE_COMMITMENT)
+}
+
+// checkChangedWatchdogs checks the accounts having watchdog transactions in the scanned blocks
+func (n *Notifier) checkChangedWatchdogs(shutdownChannel chan interface{}) {
+	if len(n.watchdogAccounts) == 0 {
+		return
+	}
+	var accounts = make([]string, 0, len(n.watchdogAccounts))
+	for account := range n.watchdogAccounts {
+		accounts = append(accounts, account)
+	}
+	if n.checkWatchdogs(shutdownChannel, accounts) {
+		n.watchdogAccounts = make(map[string]bool)
+	}
+}
+
+// checkWatchdogs compares reward recipients and commitments of the accounts (all if nil) with the last observed ones,
+// returns false if the check has been interrupted or some accounts couldn't be loaded
+func (n *Notifier) checkWatchdogs(shutdownChannel chan interface{}, accounts []string) bool {
+	query := n.db.Model(&models.DbUser{}).
+		Select("exbot_db_users.user_name, exbot_db_users.chat_id, exbot_db_accounts.*").
+		Joins("join exbot_db_accounts on exbot_db_accounts.db_user_id = exbot_db_users.id").
+		Where("exbot_db_accounts.deleted_at IS NULL").
+		Where("exbot_db_users.inactive = false")
+	if accounts != nil {
+		query = query.Where("exbot_db_accounts.account IN ?", accounts)
+	}
+
+	var monitoredAccounts []MonitoredAccount
+	err := query.Scan(&monitoredAccounts).Error
+	if err != nil {
+		n.logger.Errorf("Can't get accounts for the watchdog: %v", err)
+		return false
+	}
+	if len(monitoredAccounts) == 0 {
+		return true
+	}
+
+	var ids = make([]uint, 0, len(monitoredAccounts))
+	for _, account := range monitoredAccounts {
+		ids = append(ids, account.ID)
+	}
+	var accountWatchdogs []models.AccountWatchdog
+	if err := n.db.Where("db_account_id IN ?", ids).Find(&accountWatchdogs).Error; err != nil {
+		n.logger.Errorf("Can't get last observed reward recipients: %v", err)
+		return false
+	}
+	var watchdogs = make(map[uint]*models.AccountWatchdog, len(accountWatchdogs))
+	for i := range accountWatchdogs {
+		watchdogs[accountWatchdogs[i].DbAccountID] = &accountWatchdogs[i]
+	}
+
+	// the same account could be in the menu of several users
+	var complete = true
+	var states = make(map[string]*watchedState)
+	for i := range monitoredAccounts {
+		select {
+		case <-shutdownChannel:
+			return false
+		default:
+		}
+
+		account := &monitoredAccounts[i]
+		state, ok := states[account.Account]
+		if !ok {
+			state = n.getWatchedState(account.Account)
+			states[account.Account] = state
+		}
+		if state == nil {
+			complete = false
+			continue
+		}
+
+		watchdog := watchdogs[account.ID]
+		if watchdog == nil {
+			watchdog = &models.AccountWatchdog{DbAccountID: account.ID}
+		}
+		n.checkWatchdog(account, watchdog, state)
+	}
+	return complete
+}
+
+func (n *Notifier) getWatchedState(account string) *watchedState {
+	signumAccount, err := n.signumClient.GetAccount(n.logger, account)
+	if err != nil {
+		n.logger.Errorf("Can't get account %v for the watchdog: %v", account, err)
+		return nil
+	}
+	rewardRecipient, err := n.signumClient.GetRewardRecipient(n.logger, account)
+	if err != nil {
+		n.logger.Errorf("Can't get reward recipient of %v: %v", account, err)
+		return nil
+	}
+	return &watchedState{
+		rewardRecipient:     rewardRecipient.RewardRecipient,
+		committedBalanceNQT: signumAccount.CommittedBalanceNQT,
+	}
+}
+
+func (n *Notifier) checkWatchdog(account *MonitoredAccount, watchdog *models.AccountWatchdog, state *watchedState) {
+	if watchdog.ID == 0 { // the first observation, nothing to compare with
+		watchdog.RewardRecipient = state.rewardRecipient
+		watchdog.CommittedBalanceNQT = state.committedBalanceNQT
+		if err := n.db.Create(watchdog).Error; err != nil {
+			n.logger.Errorf("Error saving the watchdog of %v: %v", account.Account, err)
+		}
+		return
+	}
+
+	msg, accountIfAlias := formatAccountHeader("🚨", account)
+	var updates = map[string]interface{}{}
+
+	if watchdog.RewardRecipient != state.rewardRecipient {
+		var unknownPool string
+		if state.rewardRecipient != account.Account && !n.isKnownPool(state.rewardRecipient) {
+			unknownPool = "\n⚠ The new recipient is not a known pool"
+		}
+		n.notify(NotifierMessage{
+			UserName: account.UserName,
+			ChatID:   account.ChatID,
+			Message: msg + fmt.Sprintf("reward recipient has changed!"+accountIfAlias+
+				"\n<i>Was:</i> %v"+
+				"\n<i>Now:</i> %v"+unknownPool+
+				"\nIf you didn't do it, your passphrase may be compromised",
+				n.formatRewardRecipient(account, watchdog.RewardRecipient), n.formatRewardRecipient(account, state.rewardRecipient)),
+			Event:  newAccountEvent(EVENT_REWARD_RECIPIENT_CHANGED, account, 0),
+			Urgent: true,
+		})
+		updates["reward_recipient"] = state.rewardRecipient
+	}
+
+	if watchdog.CommittedBalanceNQT != state.committedBalanceNQT {
+		var sign = "+"
+		var changeNQT = state.committedBalanceNQT - watchdog.CommittedBalanceNQT
+		if state.committedBalanceNQT < watchdog.CommittedBalanceNQT {
+			sign = "-"
+			changeNQT = watchdog.CommittedBalanceNQT - state.committedBalanceNQT
+		}
+		n.notify(NotifierMessage{
+			UserName: account.UserName,
+			ChatID:   account.ChatID,
+			Message: msg + fmt.Sprintf("commitment has changed!"+accountIfAlias+
+				"\n<i>Was:</i> %v SIGNA"+
+				"\n<i>Now:</i> %v SIGNA (%v%v SIGNA)"+
+				"\nIf you didn't do it, your passphrase may be compromised",
+				common.FormatNQT(watchdog.CommittedBalanceNQT), common.FormatNQT(state.committedBalanceNQT), sign, common.FormatNQT(changeNQT)),
+			Event:  newAccountEvent(EVENT_COMMITMENT_CHANGED, account, int64(state.committedBalanceNQT)-int64(watchdog.CommittedBalanceNQT)),
+			Urgent: true,
+		})
+		updates["committed_balance_nqt"] = state.committedBalanceNQT
+	}
+
+	if len(updates) > 0 {
+		if err := n.db.Model(watchdog).Updates(updates).Error; err != nil {
+			n.logger.Errorf("Error saving the watchdog of %v: %v", account.Account, err)
+		}
+	}
+}
+
+// isKnownPool returns true only for pools of the big wallets list, exchanges are there too
+func (n *Notifier) isKnownPool(account string) bool {
+	var count int64
+	if err := n.db.Model(&models.BigWallet{}).Where("account = ? AND pool = true", account).Count(&count).Error; err != nil {
+		n.logger.Errorf("Can't check whether %v is a pool: %v", account, err)
+	}
+	return count > 0
+}
+
+// formatRewardRecipient returns the pool name with its address
+func (n *Notifier) formatRewardRecipient(account *MonitoredAccount, recipient string) string {
+	if recipient == account.Account {
+		return "the account itself (solo mining)"
+	}
+
+	var recipientRS = recipient
+	if signumAccount, err := n.signumClient.GetCachedAccount(n.logger, recipient); err == nil {
+		recipientRS = signumAccount.AccountRS
+	}
+	if name := n.signumClient.GetCachedAccountName(n.logger, recipient); name != "" {
+		return fmt.Sprintf("<b>%v</b> (%v)", name, recipientRS)
+	}
+	return recipientRS
+}
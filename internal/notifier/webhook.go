@@ -0,0 +1,287 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const WEBHOOK_SIGNATURE_HEADER = "X-Signature-256"
+
+var ErrWebhookAddress = errors.New("loopback, private, link-local and unspecified addresses are not allowed")
+
+type WebhookConfig struct {
+	Timeout     time.Duration
+	MaxAttempts uint
+	RetryDelay  time.Duration // doubles with every failed attempt
+	PollPeriod  time.Duration
+	BatchSize   int
+}
+
+type pendingWebhookDelivery struct {
+	models.WebhookDelivery
+	WebhookURL    string
+	WebhookSecret string
+}
+
+// WebhookSink stores deliveries of the users having a webhook and posts them,
+// every destination is posted by its own worker, so a slow endpoint delays only itself
+type WebhookSink struct {
+	sync.Mutex
+	db               *gorm.DB
+	logger           *zap.SugaredLogger
+	client           *http.Client
+	config           *WebhookConfig
+	busyDestinations map[string]bool
+	wg               *sync.WaitGroup
+	shutdownChannel  chan interface{}
+}
+
+func NewWebhookSink(logger *zap.SugaredLogger, db *gorm.DB, wg *sync.WaitGroup, shutdownChannel chan interface{}, config *WebhookConfig) *WebhookSink {
+	webhookSink := &WebhookSink{
+		db:               db,
+		logger:           logger,
+		client:           newWebhookClient(config.Timeout),
+		config:           config,
+		busyDestinations: make(map[string]bool),
+		wg:               wg,
+		shutdownChannel:  shutdownChannel,
+	}
+	wg.Add(1)
+	go webhookSink.startListener()
+	return webhookSink
+}
+
+// Send stores the delivery if the user has a webhook, it is posted by the next poll
+func (ws *WebhookSink) Send(message *NotifierMessage) error {
+	var dbUser models.DbUser
+	err := ws.db.Where("chat_id = ? AND webhook_url <> ''", message.ChatID).Limit(1).Find(&dbUser).Error
+	if err != nil {
+		return fmt.Errorf("can't get webhook: %v", err)
+	}
+	if dbUser.ID == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(models.WebhookPayload{
+		Event:     message.Event,
+		ChatID:    message.ChatID,
+		Message:   message.Message,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("can't marshal webhook payload: %v", err)
+	}
+
+	if err := ws.db.Create(&models.WebhookDelivery{ChatID: message.ChatID, Body: string(body)}).Error; err != nil {
+		return fmt.Errorf("can't save webhook delivery: %v", err)
+	}
+	return nil
+}
+
+func (ws *WebhookSink) startListener() {
+	defer ws.wg.Done()
+
+	ws.logger.Infof("Start Webhook Sink")
+	ticker := time.NewTicker(ws.config.PollPeriod)
+	for {
+		select {
+		case <-ws.shutdownChannel:
+			ws.logger.Infof("Webhook Sink received shutdown signal")
+			ticker.Stop()
+			return
+
+		case <-ticker.C:
+			ws.deliverWebhooks()
+		}
+	}
+}
+
+// deliverWebhooks starts a worker for every destination having deliveries, busy destinations are skipped until their worker finishes
+func (ws *WebhookSink) deliverWebhooks() {
+	query := ws.db.Model(&models.WebhookDelivery{}).
+		Select("exbot_webhook_deliveries.*, exbot_db_users.webhook_url, exbot_db_users.webhook_secret").
+		Joins("left join exbot_db_users on exbot_db_users.chat_id = exbot_webhook_deliveries.chat_id and exbot_db_users.deleted_at is null")
+	if busyURLs := ws.getBusyDestinations(); len(busyURLs) > 0 { // their deliveries mustn't fill the batch
+		query = query.Where("exbot_db_users.webhook_url is null or exbot_db_users.webhook_url not in ?", busyURLs)
+	}
+
+	var deliveries []pendingWebhookDelivery
+	err := query.Order("exbot_webhook_deliveries.id").Limit(ws.config.BatchSize).Find(&deliveries).Error
+	if err != nil {
+		ws.logger.Errorf("Can't get webhook deliveries: %v", err)
+		return
+	}
+
+	var destinations = make(map[string][]*pendingWebhookDelivery)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		if delivery.WebhookURL == "" { // the webhook has been disabled
+			ws.deleteDelivery(&delivery.WebhookDelivery)
+			continue
+		}
+		destinations[delivery.WebhookURL] = append(destinations[delivery.WebhookURL], delivery)
+	}
+
+	ws.Lock()
+	defer ws.Unlock()
+	for url, destinationDeliveries := range destinations {
+		if ws.busyDestinations[url] {
+			continue
+		}
+		ws.busyDestinations[url] = true
+		ws.wg.Add(1)
+		go ws.deliverToDestination(url, destinationDeliveries)
+	}
+}
+
+func (ws *WebhookSink) getBusyDestinations() []string {
+	ws.Lock()
+	defer ws.Unlock()
+	var urls []string
+	for url := range ws.busyDestinations {
+		urls = append(urls, url)
+	}
+	return urls
+}
+
+func (ws *WebhookSink) deliverToDestination(url string, deliveries []*pendingWebhookDelivery) {
+	defer ws.wg.Done()
+	defer func() {
+		ws.Lock()
+		delete(ws.busyDestinations, url)
+		ws.Unlock()
+	}()
+
+	for _, delivery := range deliveries {
+		err := ws.Post(url, delivery.WebhookSecret, []byte(delivery.Body))
+		if err != nil {
+			select {
+			case <-ws.shutdownChannel: // keep the delivery for the next start
+				return
+			default:
+			}
+			ws.logger.Errorf("Can't deliver webhook %v for Chat.ID %v to %v: %v", delivery.ID, delivery.ChatID, url, err)
+		}
+		ws.deleteDelivery(&delivery.WebhookDelivery)
+	}
+}
+
+func (ws *WebhookSink) deleteDelivery(delivery *models.WebhookDelivery) {
+	if err := ws.db.Unscoped().Delete(delivery).Error; err != nil {
+		ws.logger.Errorf("Error deleting webhook delivery %v: %v", delivery.ID, err)
+	}
+}
+
+// Post makes the signed request and retries it on network errors, 429 and 5xx responses
+func (ws *WebhookSink) Post(url, secret string, body []byte) error {
+	var err error
+	var retry bool
+	var delay = ws.config.RetryDelay
+	for attempt := uint(1); attempt <= ws.config.MaxAttempts; attempt++ {
+		retry, err = ws.post(url, secret, body)
+		if err == nil || !retry {
+			return err
+		}
+		if attempt == ws.config.MaxAttempts {
+			break
+		}
+
+		ws.logger.Debugf("Webhook %v attempt %v failed, retry in %v: %v", url, attempt, delay, err)
+		select {
+		case <-ws.shutdownChannel:
+			return fmt.Errorf("shutdown before retry: %v", err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return fmt.Errorf("%v attempts failed, last error: %v", ws.config.MaxAttempts, err)
+}
+
+func (ws *WebhookSink) post(url, secret string, body []byte) (retry bool, err error) {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(WEBHOOK_SIGNATURE_HEADER, "sha256="+SignWebhook(secret, body))
+
+	response, err := ws.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, response.Body)
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return false, nil
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return true, fmt.Errorf("bad status: %v", response.Status)
+	default:
+		return false, fmt.Errorf("bad status: %v", response.Status)
+	}
+}
+
+// newWebhookClient checks every dialed address, so the host can't be rebound to a private address after CheckWebhookHost
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%v: %w", address, ErrWebhookAddress)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // the proxy address would be checked instead of the webhook one
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// CheckWebhookHost resolves the host and returns an error if any of its addresses isn't public
+func CheckWebhookHost(host string) error {
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("can't resolve %v: %v", host, err)
+	}
+	for _, ip := range ips {
+		if !isPublicIP(ip) {
+			return fmt.Errorf("%v: %w", ip, ErrWebhookAddress)
+		}
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast()
+}
+
+// SignWebhook returns the hex encoded HMAC-SHA256 of the body
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,154 @@
+package notifier
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newTestWebhookSink(server *httptest.Server) *WebhookSink {
+	return &WebhookSink{
+		logger: zap.NewNop().Sugar(),
+		client: server.Client(), // the test server is on the loopback, so the dial check is skipped
+		config: &WebhookConfig{
+			Timeout:     time.Second,
+			MaxAttempts: 3,
+			RetryDelay:  time.Millisecond,
+		},
+		shutdownChannel: make(chan interface{}),
+	}
+}
+
+// newStatusServer responds with the statuses in turn, the last one is repeated
+func newStatusServer(requests *int32, statuses ...int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := int(atomic.AddInt32(requests, 1))
+		if attempt > len(statuses) {
+			attempt = len(statuses)
+		}
+		w.WriteHeader(statuses[attempt-1])
+	}))
+}
+
+func TestPostSignature(t *testing.T) {
+	const secret = "secret"
+	var body = []byte(`{"chat_id":1,"message":"test","timestamp":1}`)
+
+	var signature, contentType string
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(WEBHOOK_SIGNATURE_HEADER)
+		contentType = r.Header.Get("Content-Type")
+		receivedBody, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	if err := newTestWebhookSink(server).Post(server.URL, secret, body); err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	if string(receivedBody) != string(body) {
+		t.Errorf("body = %s, want %s", receivedBody, body)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+	if want := "sha256=" + SignWebhook(secret, body); signature != want {
+		t.Errorf("%v = %q, want %q", WEBHOOK_SIGNATURE_HEADER, signature, want)
+	}
+	if SignWebhook("other secret", body) == SignWebhook(secret, body) {
+		t.Errorf("signature doesn't depend on the secret")
+	}
+}
+
+func TestPostRetriesServerErrors(t *testing.T) {
+	var requests int32
+	server := newStatusServer(&requests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK)
+	defer server.Close()
+
+	if err := newTestWebhookSink(server).Post(server.URL, "secret", []byte("{}")); err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %v, want 3", requests)
+	}
+}
+
+func TestPostGivesUpAfterMaxAttempts(t *testing.T) {
+	var requests int32
+	server := newStatusServer(&requests, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	if err := newTestWebhookSink(server).Post(server.URL, "secret", []byte("{}")); err == nil {
+		t.Fatalf("Post returned no error")
+	}
+	if requests != 3 {
+		t.Errorf("requests = %v, want 3", requests)
+	}
+}
+
+func TestPostDoesNotRetryClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound} {
+		var requests int32
+		server := newStatusServer(&requests, status, http.StatusOK)
+
+		if err := newTestWebhookSink(server).Post(server.URL, "secret", []byte("{}")); err == nil {
+			t.Errorf("status %v: Post returned no error", status)
+		}
+		if requests != 1 {
+			t.Errorf("status %v: requests = %v, want 1", status, requests)
+		}
+		server.Close()
+	}
+}
+
+func TestWebhookClientRejectsLoopback(t *testing.T) {
+	var requests int32
+	server := newStatusServer(&requests, http.StatusOK)
+	defer server.Close()
+
+	_, err := newWebhookClient(time.Second).Post(server.URL, "application/json", nil)
+	if !errors.Is(err, ErrWebhookAddress) {
+		t.Errorf("error = %v, want %v", err, ErrWebhookAddress)
+	}
+	if requests != 0 {
+		t.Errorf("requests = %v, want 0", requests)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	var tests = map[string]bool{
+		"8.8.8.8":         true,
+		"2001:4860::8888": true,
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"fd00::1":         false,
+		"169.254.169.254": false,
+		"fe80::1":         false,
+		"0.0.0.0":         false,
+		"::":              false,
+		"::ffff:10.0.0.1": false,
+	}
+	for address, want := range tests {
+		if got := isPublicIP(net.ParseIP(address)); got != want {
+			t.Errorf("isPublicIP(%v) = %v, want %v", address, got, want)
+		}
+	}
+}
+
+func TestCheckWebhookHost(t *testing.T) {
+	for _, host := range []string{"127.0.0.1", "10.0.0.1", "::1"} {
+		if err := CheckWebhookHost(host); !errors.Is(err, ErrWebhookAddress) {
+			t.Errorf("CheckWebhookHost(%v) = %v, want %v", host, err, ErrWebhookAddress)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+type OutboxConfig struct {
+	PollPeriod     time.Duration
+	BatchSize      int
+	MaxAttempts    uint
+	BaseRetryDelay time.Duration // doubles with every failed attempt
+	MaxRetryDelay  time.Duration
+	CleanupPeriod  time.Duration
+	KeepDelivered  time.Duration // sent and failed notifications are deleted after this time
+	CoalesceWindow time.Duration // notifications having a batch key are held for this time to be combined
+	CoalesceCount  int           // minimum number of notifications for the same account to be combined
+}
+
+func (bot *TelegramBot) startOutboxListener() {
+	defer bot.overallWg.Done()
+
+	bot.logger.Infof("Start Outbox Listener")
+	ticker := time.NewTicker(bot.outboxConfig.PollPeriod)
+	cleanupTicker := time.NewTicker(bot.outboxConfig.CleanupPeriod)
+
+	for {
+		select {
+		case <-bot.overallShutdownChannel:
+			bot.logger.Infof("Outbox Listener received shutdown signal")
+			ticker.Stop()
+			cleanupTicker.Stop()
+			return
+
+		case <-ticker.C:
+			now := time.Now()
+			bot.coalesceNotifications(now)
+			bot.deliverNotifications(now)
+
+		case <-cleanupTicker.C:
+			bot.cleanupOutbox()
+		}
+	}
+}
+
+func (bot *TelegramBot) deliverNotifications(now time.Time) {
+	var notifications []models.Notification
+	err := bot.db.Where("status = ? AND next_attempt_at <= ?", models.NOTIFICATION_PENDING, now).
+		Where("batch_key = '' OR created_at <= ?", now.Add(-bot.outboxConfig.CoalesceWindow)).
+		Order("id").Limit(bot.outboxConfig.BatchSize).Find(&notifications).Error
+	if err != nil {
+		bot.logger.Errorf("Can't get pending notifications: %v", err)
+		return
+	}
+
+	var chatIDs []int64
+	for _, notification := range notifications {
+		chatIDs = append(chatIDs, notification.ChatID)
+	}
+	quietUsers := bot.getQuietUsers(chatIDs)
+
+	var inactiveChats = make(map[int64]bool)
+	for i := range notifications {
+		select {
+		case <-bot.overallShutdownChannel:
+			return
+		default:
+		}
+
+		notification := &notifications[i]
+		if inactiveChats[notification.ChatID] { // already failed by deactivateChat
+			continue
+		}
+
+		// edits are silent, new messages wait for the end of quiet hours unless they are urgent
+		if quietEnd := getQuietEnd(quietUsers[notification.ChatID], now); notification.MessageID == 0 && !notification.Urgent && !quietEnd.IsZero() {
+			notification.NextAttemptAt = quietEnd
+			bot.saveNotification(notification)
+			continue
+		}
+
+		var err error
+		var messageID = notification.MessageID
+		if messageID == 0 {
+			bot.logger.Infof("Send notification to user %v (Chat.ID %v), attempt %v: %v", notification.UserName, notification.ChatID,
+				notification.Attempts+1, strings.Replace(notification.Message, "\n", " ", -1))
+			messageID, err = bot.SendNotification(notification.ChatID, notification.Message)
+		} else {
+			bot.logger.Infof("Edit notification %v for user %v (Chat.ID %v), attempt %v: %v", messageID, notification.UserName, notification.ChatID,
+				notification.Attempts+1, strings.Replace(notification.Message, "\n", " ", -1))
+			err = bot.EditNotification(notification.ChatID, messageID, notification.Message)
+			if err != nil && strings.Contains(err.Error(), "message is not modified") {
+				err = nil
+			}
+		}
+		notification.Attempts++
+
+		if err == nil {
+			bot.markNotificationSent(notification, messageID)
+			continue
+		}
+
+		bot.logger.Errorf("Can't send notification %v to user %v (Chat.ID %v): %v", notification.ID, notification.UserName, notification.ChatID, err)
+		notification.LastError = err.Error()
+
+		var telegramError tgbotapi.Error
+		isTelegramError := errors.As(err, &telegramError)
+
+		switch {
+		case isTelegramError && isChatUnavailable(&telegramError):
+			notification.Status = models.NOTIFICATION_FAILED
+			bot.saveNotification(notification)
+			bot.deactivateChat(notification.ChatID)
+			inactiveChats[notification.ChatID] = true
+		case isTelegramError && strings.HasPrefix(telegramError.Message, "Bad Request"): // retrying won't help
+			notification.Status = models.NOTIFICATION_FAILED
+			bot.saveNotification(notification)
+		case notification.Attempts >= bot.outboxConfig.MaxAttempts:
+			notification.Status = models.NOTIFICATION_FAILED
+			bot.saveNotification(notification)
+		default:
+			var retryAfter time.Duration
+			if isTelegramError {
+				retryAfter = time.Duration(telegramError.RetryAfter) * time.Second
+			}
+			notification.NextAttemptAt = time.Now().Add(bot.getRetryDelay(notification.Attempts, retryAfter))
+			bot.saveNotification(notification)
+
+			if retryAfter > 0 { // flood control, all other messages will be rejected too
+				bot.logger.Infof("Telegram asks to retry after %v, the rest of notifications are postponed", retryAfter)
+				return
+			}
+		}
+	}
+}
+
+func (bot *TelegramBot) getRetryDelay(attempts uint, retryAfter time.Duration) time.Duration {
+	delay := bot.outboxConfig.MaxRetryDelay
+	if attempts <= 30 { // avoid overflow
+		delay = bot.outboxConfig.BaseRetryDelay << (attempts - 1)
+	}
+	if delay > bot.outboxConfig.MaxRetryDelay {
+		delay = bot.outboxConfig.MaxRetryDelay
+	}
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay
+}
+
+// isChatUnavailable returns true if the bot was blocked or the chat was deleted
+func isChatUnavailable(err *tgbotapi.Error) bool {
+	return strings.HasPrefix(err.Message, "Forbidden") || err.Message == "Bad Request: chat not found"
+}
+
+func (bot *TelegramBot) deactivateChat(chatID int64) {
+	bot.logger.Infof("Chat %v is unavailable, mark the user as inactive", chatID)
+	bot.usersManager.DeactivateUser(chatID)
+
+	err := bot.db.Model(&models.Notification{}).
+		Where("chat_id = ? AND status = ?", chatID, models.NOTIFICATION_PENDING).
+		Updates(map[string]interface{}{"status": models.NOTIFICATION_FAILED, "last_error": "chat is inactive"}).Error
+	if err != nil {
+		bot.logger.Errorf("Can't fail pending notifications for chat %v: %v", chatID, err)
+	}
+}
+
+// saveNotification doesn't touch the message, it could be updated by the notifier in the meantime
+func (bot *TelegramBot) saveNotification(notification *models.Notification) {
+	err := bot.db.Model(notification).
+		Select("status", "attempts", "next_attempt_at", "last_error", "sent_at", "message_id").
+		Updates(notification).Error
+	if err != nil {
+		bot.logger.Errorf("Error saving notification %v: %v", notification.ID, err)
+	}
+}
+
+func (bot *TelegramBot) markNotificationSent(notification *models.Notification, messageID int) {
+	now := time.Now()
+	result := bot.db.Model(&models.Notification{}).
+		Where("id = ? AND message = ?", notification.ID, notification.Message).
+		Updates(map[string]interface{}{
+			"status":     models.NOTIFICATION_SENT,
+			"attempts":   notification.Attempts,
+			"last_error": "",
+			"sent_at":    now,
+			"message_id": messageID,
+		})
+	if result.Error != nil {
+		bot.logger.Errorf("Error saving notification %v: %v", notification.ID, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 { // the message has been changed while sending, keep it pending to edit by the next poll
+		notification.MessageID = messageID
+		notification.Attempts = 0
+		bot.saveNotification(notification)
+	}
+}
+
+func (bot *TelegramBot) cleanupOutbox() {
+	err := bot.db.Unscoped().
+		Where("status <> ? AND updated_at < ?", models.NOTIFICATION_PENDING, time.Now().Add(-bot.outboxConfig.KeepDelivered)).
+		Delete(&models.Notification{}).Error
+	if err != nil {
+		bot.logger.Errorf("Can't clean up outbox: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package pools
+
+import (
+	"fmt"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// updateMiners replaces the stored miners of every pool by the actual reward recipient assignments
+func (pc *PoolCollector) updateMiners(shutdownChannel chan interface{}) {
+	var miners = make(map[string]string)
+	for _, pool := range pc.getPools() {
+		select {
+		case <-shutdownChannel:
+			return
+		default:
+		}
+
+		rewardRecipientAccounts, err := pc.signumClient.GetAccountsWithRewardRecipient(pc.logger, pool.Account)
+		if err != nil {
+			pc.logger.Errorf("Can't get miners of the pool %v: %v", pool.Account, err)
+			// keep the previous miners of this pool
+			pc.RLock()
+			for miner, minerPool := range pc.miners {
+				if minerPool == pool.Account {
+					miners[miner] = minerPool
+				}
+			}
+			pc.RUnlock()
+			continue
+		}
+
+		var poolMiners = make([]models.PoolMiner, 0, len(rewardRecipientAccounts.Accounts))
+		for _, account := range rewardRecipientAccounts.Accounts {
+			if account == pool.Account { // the pool is always assigned to itself
+				continue
+			}
+			miners[account] = pool.Account
+			poolMiners = append(poolMiners, models.PoolMiner{Pool: pool.Account, Account: account})
+		}
+
+		err = pc.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Where("pool = ?", pool.Account).Delete(&models.PoolMiner{}).Error; err != nil {
+				return err
+			}
+			if len(poolMiners) == 0 {
+				return nil
+			}
+			return tx.CreateInBatches(&poolMiners, 500).Error
+		})
+		if err != nil {
+			pc.logger.Errorf("Error saving miners of the pool %v: %v", pool.Account, err)
+		}
+	}
+
+	pc.Lock()
+	pc.miners = miners
+	pc.minersLoaded = true
+	pc.Unlock()
+}
+
+// ProcessBlock records blocks forged by pool miners and pool payouts, it's called by the notifier block scanning,
+// the block is scanned again if an error is returned.
+// Blocks are credited to the current pool of the generator, the node doesn't report the reward recipient at a past height,
+// so blocks forged before the miner has switched pools are credited to the new pool while the notifier is catching up
+func (pc *PoolCollector) ProcessBlock(block *signumapi.BlockWithTransactions) error {
+	var pools = make(map[string]bool)
+	for _, pool := range pc.getPools() {
+		pools[pool.Account] = true
+	}
+
+	pc.RLock()
+	pool, ok := pc.miners[block.Generator]
+	minersLoaded := pc.minersLoaded
+	pc.RUnlock()
+	if !minersLoaded {
+		return fmt.Errorf("pool miners aren't loaded yet")
+	}
+
+	if ok {
+		err := pc.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.PoolBlock{
+			Pool:      pool,
+			Height:    block.Height,
+			Generator: block.Generator,
+			RewardNQT: block.GetRewardNQT(),
+			ForgedAt:  common.ChainTimeToTime(block.Timestamp),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("can't save block #%v of the pool %v: %v", block.Height, pool, err)
+		}
+	}
+
+	for _, transaction := range block.Transactions {
+		if !pools[transaction.Sender] || transaction.Type != signumapi.TT_PAYMENT ||
+			(transaction.Subtype != signumapi.TST_MULTI_OUT_PAYMENT && transaction.Subtype != signumapi.TST_MULTI_OUT_SAME_PAYMENT) {
+			continue
+		}
+		err := pc.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.PoolPayout{
+			Pool:          transaction.Sender,
+			TransactionID: transaction.TransactionID,
+			AmountNQT:     transaction.AmountNQT,
+			Recipients:    len(transaction.Attachment.Recipients.GetRecipients()),
+			PaidAt:        common.ChainTimeToTime(transaction.Timestamp),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("can't save payout %v of the pool %v: %v", transaction.TransactionID, transaction.Sender, err)
+		}
+	}
+	return nil
+}
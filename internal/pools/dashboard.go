@@ -0,0 +1,145 @@
+package pools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"github.com/xDWart/signum-explorer-bot/internal/rsaddress"
+)
+
+type dayStat struct {
+	blocks    int
+	rewardNQT uint64
+}
+
+// GetPoolInfo returns the dashboard of the pool given by ID, address or name, or the list of pools
+func (pc *PoolCollector) GetPoolInfo(message string) string {
+	var pools = pc.getPools()
+	if len(pools) == 0 {
+		return "🚫 There are no pools yet, please try again later"
+	}
+
+	splittedMessage := strings.SplitN(message, " ", 2)
+	if len(splittedMessage) < 2 {
+		return pc.getPoolList(pools)
+	}
+
+	pool := pc.findPool(pools, splittedMessage[1])
+	if pool == nil {
+		return "🚫 Pool not found\n\n" + pc.getPoolList(pools)
+	}
+	return pc.getPoolDashboard(pool)
+}
+
+func (pc *PoolCollector) findPool(pools []models.BigWallet, arg string) *models.BigWallet {
+	var account = arg
+	if address, err := rsaddress.Parse(arg); err == nil {
+		account = strconv.FormatUint(address.ID, 10)
+	}
+	for i := range pools {
+		if pools[i].Account == account || strings.EqualFold(pools[i].Name, arg) {
+			return &pools[i]
+		}
+	}
+	return nil
+}
+
+func (pc *PoolCollector) getPoolList(pools []models.BigWallet) string {
+	var text = "⛏ <b>Known pools:</b>"
+	for _, pool := range pools {
+		var miners int64
+		pc.db.Model(&models.PoolMiner{}).Where("pool = ?", pool.Account).Count(&miners)
+		text += fmt.Sprintf("\n<code>%v</code> %v (%v miners)", pool.Account, pc.getPoolName(&pool), miners)
+	}
+	return text + fmt.Sprintf("\n\nSend <b>%v ACCOUNT</b> or <b>%v NAME</b> to get the pool dashboard", config.COMMAND_POOL, config.COMMAND_POOL)
+}
+
+func (pc *PoolCollector) getPoolName(pool *models.BigWallet) string {
+	if name := pc.signumClient.GetCachedAccountName(pc.logger, pool.Account); name != "" {
+		return name
+	}
+	if pool.Name != "" {
+		return pool.Name
+	}
+	return pool.Account
+}
+
+func (pc *PoolCollector) getPoolDashboard(pool *models.BigWallet) string {
+	var now = time.Now().UTC()
+	var since = now.Truncate(config.DAY).Add(-time.Duration(pc.config.StatDays-1) * config.DAY)
+
+	var miners int64
+	pc.db.Model(&models.PoolMiner{}).Where("pool = ?", pool.Account).Count(&miners)
+
+	var text = fmt.Sprintf("⛏ <b>%v</b>", pc.getPoolName(pool))
+	var accountRS = pool.Account
+	var balanceNQT uint64
+	signumAccount, err := pc.signumClient.GetCachedAccount(pc.logger, pool.Account)
+	if err == nil {
+		accountRS = signumAccount.AccountRS
+		balanceNQT = signumAccount.TotalBalanceNQT
+	}
+	text += fmt.Sprintf("\n<i>Account:</i> <code>%v</code>"+
+		"\n<i>Assigned miners:</i> %v", accountRS, miners)
+
+	var poolBlocks []models.PoolBlock
+	pc.db.Where("pool = ? AND forged_at >= ?", pool.Account, since).Order("forged_at").Find(&poolBlocks)
+	var days = make(map[string]*dayStat)
+	for _, block := range poolBlocks {
+		day := block.ForgedAt.UTC().Format("2006-01-02")
+		if days[day] == nil {
+			days[day] = &dayStat{}
+		}
+		days[day].blocks++
+		days[day].rewardNQT += block.RewardNQT
+	}
+	text += fmt.Sprintf("\n\n💽 <b>Forged blocks for %v days:</b> %v", pc.config.StatDays, len(poolBlocks))
+	for date := since; !date.After(now); date = date.Add(config.DAY) {
+		day := date.Format("2006-01-02")
+		if days[day] == nil {
+			text += fmt.Sprintf("\n<i>%v:</i> 0", day)
+			continue
+		}
+		text += fmt.Sprintf("\n<i>%v:</i> %v (+%v SIGNA)", day, days[day].blocks, common.FormatNQT(days[day].rewardNQT))
+	}
+
+	var payouts struct {
+		Count      int64
+		AmountNQT  uint64
+		Recipients int64
+	}
+	pc.db.Model(&models.PoolPayout{}).
+		Select("count(*) as count, coalesce(sum(amount_nqt), 0) as amount_nqt, coalesce(sum(recipients), 0) as recipients").
+		Where("pool = ? AND paid_at >= ?", pool.Account, since).
+		Scan(&payouts)
+	text += fmt.Sprintf("\n\n💸 <b>Payouts for %v days:</b> %v transactions, %v SIGNA to %v recipients",
+		pc.config.StatDays, payouts.Count, common.FormatNQT(payouts.AmountNQT), payouts.Recipients)
+
+	// rewards of blocks forged since the last payout aren't paid out yet
+	var lastPayout models.PoolPayout
+	pc.db.Where("pool = ?", pool.Account).Order("paid_at desc").Limit(1).Find(&lastPayout)
+	var pendingNQT uint64
+	pc.db.Model(&models.PoolBlock{}).
+		Select("coalesce(sum(reward_nqt), 0)").
+		Where("pool = ? AND forged_at > ?", pool.Account, lastPayout.PaidAt).
+		Scan(&pendingNQT)
+	if lastPayout.ID != 0 {
+		text += fmt.Sprintf("\n<i>Last payout:</i> %v UTC", lastPayout.PaidAt.UTC().Format("2006-01-02 15:04"))
+	}
+
+	if err != nil {
+		return text + "\n\n🚫 Can't get the pool balance, please try again later"
+	}
+	var sign = "✅"
+	if balanceNQT < pendingNQT {
+		sign = "⚠"
+	}
+	text += fmt.Sprintf("\n\n💰 <i>Balance:</i> %v SIGNA"+
+		"\n<i>Pending payouts:</i> %v SIGNA %v", common.FormatNQT(balanceNQT), common.FormatNQT(pendingNQT), sign)
+	return text
+}
@@ -0,0 +1,139 @@
+package pools
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// exchanges and service accounts from the default list, the others are pools
+var notPools = map[string]bool{
+	"13383190289605706987": true, // Bittrex
+	"5346619515173992638":  true,
+	"13736966403016142704": true, // Signum Activation Account
+}
+
+type PoolCollector struct {
+	sync.RWMutex
+	db           *gorm.DB
+	logger       *zap.SugaredLogger
+	signumClient *signumapi.SignumApiClient
+	bigWallets   []models.BigWallet
+	miners       map[string]string // miner -> pool
+	minersLoaded bool
+	config       *Config
+}
+
+type Config struct {
+	CollectPeriod time.Duration
+	StatDays      int
+}
+
+func NewPoolCollector(logger *zap.SugaredLogger, db *gorm.DB, signumClient *signumapi.SignumApiClient, wg *sync.WaitGroup, shutdownChannel chan interface{}, config *Config) *PoolCollector {
+	pc := &PoolCollector{
+		db:           db,
+		logger:       logger,
+		signumClient: signumClient,
+		miners:       map[string]string{},
+		config:       config,
+	}
+	pc.seedBigWallets()
+	wg.Add(1)
+	go pc.startListener(wg, shutdownChannel)
+	return pc
+}
+
+func (pc *PoolCollector) startListener(wg *sync.WaitGroup, shutdownChannel chan interface{}) {
+	defer wg.Done()
+
+	pc.logger.Infof("Start Pool Collector")
+	ticker := time.NewTicker(pc.config.CollectPeriod)
+
+	pc.collect(shutdownChannel) // the notifier block scanning waits for miners, ProcessBlock fails until they are loaded
+
+	for {
+		select {
+		case <-shutdownChannel:
+			pc.logger.Infof("Pool Collector received shutdown signal")
+			ticker.Stop()
+			return
+
+		case <-ticker.C:
+			pc.collect(shutdownChannel)
+		}
+	}
+}
+
+func (pc *PoolCollector) collect(shutdownChannel chan interface{}) {
+	startTime := time.Now()
+	pc.loadBigWallets()
+	pc.updateMiners(shutdownChannel)
+	pc.logger.Infof("Pool Collector has updated miners in %v", time.Since(startTime))
+}
+
+// seedBigWallets fills the empty table by the compiled-in list
+func (pc *PoolCollector) seedBigWallets() {
+	var count int64
+	if err := pc.db.Model(&models.BigWallet{}).Count(&count).Error; err != nil {
+		pc.logger.Errorf("Can't count big wallets: %v", err)
+		return
+	}
+	if count > 0 {
+		return
+	}
+
+	var bigWallets = make([]models.BigWallet, 0, len(signumapi.DefaultBigWallets))
+	for account, name := range signumapi.DefaultBigWallets {
+		bigWallets = append(bigWallets, models.BigWallet{Account: account, Name: name, Pool: !notPools[account]})
+	}
+	if err := pc.db.Create(&bigWallets).Error; err != nil {
+		pc.logger.Errorf("Error saving default big wallets: %v", err)
+	}
+}
+
+// loadBigWallets reloads the list from DB and passes it to the signum client if it has changed
+func (pc *PoolCollector) loadBigWallets() {
+	var bigWallets []models.BigWallet
+	if err := pc.db.Order("id").Find(&bigWallets).Error; err != nil {
+		pc.logger.Errorf("Can't get big wallets: %v", err)
+		return
+	}
+
+	pc.RLock()
+	var changed = len(bigWallets) != len(pc.bigWallets)
+	for i := 0; !changed && i < len(bigWallets); i++ {
+		changed = bigWallets[i].Account != pc.bigWallets[i].Account ||
+			bigWallets[i].Name != pc.bigWallets[i].Name || bigWallets[i].Pool != pc.bigWallets[i].Pool
+	}
+	pc.RUnlock()
+	if !changed {
+		return
+	}
+
+	pc.Lock()
+	pc.bigWallets = bigWallets
+	pc.Unlock()
+
+	var wallets = make(map[string]string, len(bigWallets))
+	for _, bigWallet := range bigWallets {
+		wallets[bigWallet.Account] = bigWallet.Name
+	}
+	pc.signumClient.SetBigWallets(pc.logger, wallets)
+	pc.logger.Infof("Pool Collector has loaded %v big wallets from DB", len(bigWallets))
+}
+
+func (pc *PoolCollector) getPools() []models.BigWallet {
+	pc.RLock()
+	defer pc.RUnlock()
+	var pools = make([]models.BigWallet, 0, len(pc.bigWallets))
+	for _, bigWallet := range pc.bigWallets {
+		if bigWallet.Pool {
+			pools = append(pools, bigWallet)
+		}
+	}
+	return pools
+}
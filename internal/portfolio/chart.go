@@ -0,0 +1,92 @@
+package portfolio
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+// GetPortfolioChart plots stored snapshots of the user with the actual summary as the last point if it is complete
+func (pm *PortfolioManager) GetPortfolioChart(dbUserID uint, summary *Summary, signaPrice float64) []byte {
+	var snapshots []models.PortfolioSnapshot
+	result := pm.db.Where("db_user_id = ?", dbUserID).Order("created_at asc").Find(&snapshots)
+	if result.Error != nil {
+		pm.logger.Errorf("Error getting portfolio snapshots from DB for plotting chart: %v", result.Error)
+		return nil
+	}
+	if len(snapshots) == 0 || summary.Incomplete && len(snapshots) == 1 { // nothing to plot yet
+		return nil
+	}
+
+	graph := chart.Chart{
+		Title: "Portfolio value",
+		Background: chart.Style{
+			Padding: chart.Box{
+				Top:  50,
+				Left: 20,
+			},
+		},
+		XAxis: chart.XAxis{
+			ValueFormatter: chart.TimeDateValueFormatter,
+		},
+		YAxis: chart.YAxis{
+			Name: "SIGNA",
+		},
+		YAxisSecondary: chart.YAxis{
+			Name: "USD, $",
+		},
+		Series: []chart.Series{},
+	}
+
+	signaChartTimeSeries := chart.TimeSeries{
+		Name: "SIGNA",
+		Style: chart.Style{
+			StrokeColor: chart.GetDefaultColor(1),
+			FillColor:   chart.GetDefaultColor(1).WithAlpha(80),
+		},
+		XValues: []time.Time{},
+		YValues: []float64{},
+	}
+
+	usdChartTimeSeries := chart.TimeSeries{
+		Name: "USD",
+		Style: chart.Style{
+			StrokeColor: chart.GetDefaultColor(0),
+		},
+		YAxis:   chart.YAxisSecondary,
+		XValues: []time.Time{},
+		YValues: []float64{},
+	}
+
+	for _, snapshot := range snapshots {
+		value := float64(snapshot.TotalBalanceNQT+snapshot.TokensValueNQT) / 1e8
+		signaChartTimeSeries.XValues = append(signaChartTimeSeries.XValues, snapshot.CreatedAt)
+		signaChartTimeSeries.YValues = append(signaChartTimeSeries.YValues, value)
+		usdChartTimeSeries.XValues = append(usdChartTimeSeries.XValues, snapshot.CreatedAt)
+		usdChartTimeSeries.YValues = append(usdChartTimeSeries.YValues, value*snapshot.SignaPrice)
+	}
+
+	if !summary.Incomplete { // partial totals would be plotted as a drop
+		value := float64(summary.TotalBalanceNQT+summary.TokensValueNQT) / 1e8
+		signaChartTimeSeries.XValues = append(signaChartTimeSeries.XValues, time.Now())
+		signaChartTimeSeries.YValues = append(signaChartTimeSeries.YValues, value)
+		usdChartTimeSeries.XValues = append(usdChartTimeSeries.XValues, time.Now())
+		usdChartTimeSeries.YValues = append(usdChartTimeSeries.YValues, value*signaPrice)
+	}
+
+	graph.Series = append(graph.Series, signaChartTimeSeries, usdChartTimeSeries)
+	graph.Elements = []chart.Renderable{
+		chart.Legend(&graph),
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	err := graph.Render(chart.PNG, buffer)
+	if err != nil {
+		pm.logger.Errorf("Could not render chart: %v", err)
+		return nil
+	}
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,136 @@
+package portfolio
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/api/geckoapi"
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type PortfolioManager struct {
+	db           *gorm.DB
+	logger       *zap.SugaredLogger
+	geckoClient  *geckoapi.GeckoClient
+	signumClient *signumapi.SignumApiClient
+	config       *Config
+}
+
+type Config struct {
+	SnapshotPeriod time.Duration
+	RetryPeriod    time.Duration // users without a fresh snapshot (failed or interrupted) are taken again after it
+}
+
+func NewPortfolioManager(logger *zap.SugaredLogger, db *gorm.DB, geckoClient *geckoapi.GeckoClient, signumClient *signumapi.SignumApiClient, wg *sync.WaitGroup, shutdownChannel chan interface{}, config *Config) *PortfolioManager {
+	pm := &PortfolioManager{
+		db:           db,
+		logger:       logger,
+		geckoClient:  geckoClient,
+		signumClient: signumClient,
+		config:       config,
+	}
+	wg.Add(1)
+	go pm.startListener(wg, shutdownChannel)
+	return pm
+}
+
+func (pm *PortfolioManager) startListener(wg *sync.WaitGroup, shutdownChannel chan interface{}) {
+	defer wg.Done()
+
+	pm.logger.Infof("Start Portfolio Manager")
+	ticker := time.NewTicker(pm.config.RetryPeriod)
+
+	pm.takeSnapshots(shutdownChannel)
+	for {
+		select {
+		case <-shutdownChannel:
+			pm.logger.Infof("Portfolio Manager received shutdown signal")
+			ticker.Stop()
+			return
+
+		case <-ticker.C:
+			pm.takeSnapshots(shutdownChannel)
+		}
+	}
+}
+
+// takeSnapshots stores portfolios of active users having no fresh snapshot,
+// the user is skipped if any account or token can't be loaded, so partial totals don't get into the history
+func (pm *PortfolioManager) takeSnapshots(shutdownChannel chan interface{}) {
+	var freshUserIDs []uint
+	err := pm.db.Model(&models.PortfolioSnapshot{}).
+		Where("created_at > ?", time.Now().Add(-pm.config.SnapshotPeriod+time.Minute)).
+		Distinct().Pluck("db_user_id", &freshUserIDs).Error
+	if err != nil {
+		pm.logger.Errorf("Can't get fresh portfolio snapshots: %v", err)
+		return
+	}
+	var freshUsers = make(map[uint]bool, len(freshUserIDs))
+	for _, userID := range freshUserIDs {
+		freshUsers[userID] = true
+	}
+
+	var dbAccounts []*models.DbAccount
+	err = pm.db.Joins("join exbot_db_users on exbot_db_users.id = exbot_db_accounts.db_user_id").
+		Where("exbot_db_users.inactive = false").
+		Order("exbot_db_accounts.id").
+		Find(&dbAccounts).Error
+	if err != nil {
+		pm.logger.Errorf("Can't get accounts for portfolio snapshots: %v", err)
+		return
+	}
+
+	var userIDs []uint
+	var userAccounts = make(map[uint][]*models.DbAccount)
+	for _, dbAccount := range dbAccounts {
+		if freshUsers[dbAccount.DbUserID] {
+			continue
+		}
+		if userAccounts[dbAccount.DbUserID] == nil {
+			userIDs = append(userIDs, dbAccount.DbUserID)
+		}
+		userAccounts[dbAccount.DbUserID] = append(userAccounts[dbAccount.DbUserID], dbAccount)
+	}
+
+	if len(userIDs) == 0 {
+		return
+	}
+
+	startTime := time.Now()
+	prices := pm.geckoClient.GetPrices(pm.logger)
+	var savedSnapshots int
+	for _, userID := range userIDs {
+		select {
+		case <-shutdownChannel:
+			return
+		default:
+		}
+
+		summary := pm.getSummary(userAccounts[userID])
+		if summary.Incomplete {
+			pm.logger.Infof("Portfolio snapshot of user %v is postponed, some accounts or tokens can't be loaded", userID)
+			continue
+		}
+		if len(summary.Accounts) == 0 {
+			continue
+		}
+		err := pm.db.Create(&models.PortfolioSnapshot{
+			DbUserID:            userID,
+			AvailableBalanceNQT: summary.AvailableBalanceNQT,
+			CommittedBalanceNQT: summary.CommittedBalanceNQT,
+			TotalBalanceNQT:     summary.TotalBalanceNQT,
+			TokensValueNQT:      summary.TokensValueNQT,
+			SignaPrice:          prices["SIGNA"].Usd,
+			BtcPrice:            prices["BTC"].Usd,
+		}).Error
+		if err != nil {
+			pm.logger.Errorf("Error saving portfolio snapshot of user %v: %v", userID, err)
+			continue
+		}
+		savedSnapshots++
+	}
+	pm.logger.Infof("Portfolio Manager has saved snapshots of %v of %v users in %v", savedSnapshots, len(userIDs), time.Since(startTime))
+}
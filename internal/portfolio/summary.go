@@ -0,0 +1,139 @@
+package portfolio
+
+import (
+	"fmt"
+	"html"
+	"sort"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+type AccountSummary struct {
+	Name    string // alias or RS
+	Account *signumapi.Account
+}
+
+type TokenHolding struct {
+	Asset       *signumapi.Asset
+	QuantityQNT uint64
+}
+
+type Summary struct {
+	Accounts            []AccountSummary
+	AvailableBalanceNQT uint64
+	CommittedBalanceNQT uint64
+	TotalBalanceNQT     uint64
+	Tokens              []*TokenHolding
+	TokensValueNQT      uint64
+	Incomplete          bool // some accounts or tokens can't be loaded, the totals are lower than the real ones
+}
+
+// getSummary sums up balances and token holdings of the accounts, accounts and tokens which can't be loaded are skipped
+func (pm *PortfolioManager) getSummary(dbAccounts []*models.DbAccount) *Summary {
+	var summary Summary
+	var tokens = make(map[string]*TokenHolding)
+	for _, dbAccount := range dbAccounts {
+		account, err := pm.signumClient.GetCachedAccount(pm.logger, dbAccount.Account)
+		if err != nil {
+			if err.Error() == "Unknown account" { // not activated yet, there is nothing to sum up
+				continue
+			}
+			pm.logger.Errorf("Can't get account %v for the portfolio: %v", dbAccount.Account, err)
+			summary.Incomplete = true
+			continue
+		}
+
+		var name = account.AccountRS
+		if dbAccount.Alias != "" {
+			name = dbAccount.Alias
+		}
+		summary.Accounts = append(summary.Accounts, AccountSummary{Name: name, Account: account})
+		summary.AvailableBalanceNQT += account.AvailableBalanceNQT
+		summary.CommittedBalanceNQT += account.CommittedBalanceNQT
+		summary.TotalBalanceNQT += account.TotalBalanceNQT
+
+		for _, assetBalance := range account.AssetBalances {
+			if assetBalance.BalanceQNT == 0 {
+				continue
+			}
+			if tokens[assetBalance.Asset] == nil {
+				asset, err := pm.signumClient.GetCachedAsset(pm.logger, assetBalance.Asset)
+				if err != nil {
+					pm.logger.Errorf("Can't get asset %v for the portfolio: %v", assetBalance.Asset, err)
+					summary.Incomplete = true
+					continue
+				}
+				tokens[assetBalance.Asset] = &TokenHolding{Asset: asset}
+				summary.Tokens = append(summary.Tokens, tokens[assetBalance.Asset])
+			}
+			tokens[assetBalance.Asset].QuantityQNT += assetBalance.BalanceQNT
+		}
+	}
+
+	for _, token := range summary.Tokens {
+		summary.TokensValueNQT += token.Asset.GetValueNQT(token.QuantityQNT)
+	}
+	sort.SliceStable(summary.Tokens, func(i, j int) bool {
+		return summary.Tokens[i].Asset.GetValueNQT(summary.Tokens[i].QuantityQNT) > summary.Tokens[j].Asset.GetValueNQT(summary.Tokens[j].QuantityQNT)
+	})
+	return &summary
+}
+
+// GetPortfolio returns the summary of all menu accounts of the user with the value chart
+func (pm *PortfolioManager) GetPortfolio(dbUser *models.DbUser) (string, []byte) {
+	if len(dbUser.Accounts) == 0 {
+		return fmt.Sprintf("🚫 There are no accounts in your menu, please add them via <b>%v ACCOUNT</b> at first", config.COMMAND_ADD), nil
+	}
+
+	summary := pm.getSummary(dbUser.Accounts)
+	if len(summary.Accounts) == 0 {
+		return "🚫 Can't get your accounts, please try again later", nil
+	}
+
+	prices := pm.geckoClient.GetPrices(pm.logger)
+	signaPrice := prices["SIGNA"].Usd
+	btcPrice := prices["BTC"].Usd
+	formatValue := func(amountNQT uint64) string {
+		value := float64(amountNQT) / 1e8 * signaPrice
+		var btcValue float64
+		if btcPrice > 0 {
+			btcValue = value / btcPrice
+		}
+		return fmt.Sprintf("<i>($%v | %v BTC)</i>", common.FormatNumber(value, 2), common.FormatNumber(btcValue, 4))
+	}
+
+	var text = fmt.Sprintf("💼 <b>Portfolio</b> of %v accounts:\n", len(summary.Accounts))
+	for _, account := range summary.Accounts {
+		text += fmt.Sprintf("\n<b>%v</b>: %v SIGNA", account.Name, common.FormatNQT(account.Account.TotalBalanceNQT))
+	}
+	text += fmt.Sprintf("\n\nAvailable: %v SIGNA %v"+
+		"\nCommitment: %v SIGNA %v"+
+		"\n<b>Total: %v SIGNA</b> %v",
+		common.FormatNQT(summary.AvailableBalanceNQT), formatValue(summary.AvailableBalanceNQT),
+		common.FormatNQT(summary.CommittedBalanceNQT), formatValue(summary.CommittedBalanceNQT),
+		common.FormatNQT(summary.TotalBalanceNQT), formatValue(summary.TotalBalanceNQT))
+
+	if len(summary.Tokens) > 0 {
+		text += "\n\n📇 <b>Tokens:</b>"
+		for _, token := range summary.Tokens {
+			text += fmt.Sprintf("\n%v: %v", html.EscapeString(token.Asset.Name),
+				common.FormatNumber(token.Asset.GetQuantity(token.QuantityQNT), int(token.Asset.Decimals)))
+			if valueNQT := token.Asset.GetValueNQT(token.QuantityQNT); valueNQT > 0 {
+				text += fmt.Sprintf(" <i>(~%v SIGNA)</i>", common.FormatNQT(valueNQT))
+			}
+		}
+		text += fmt.Sprintf("\n<i>Tokens value:</i> %v SIGNA %v"+
+			"\n\n<b>Portfolio value: %v SIGNA</b> %v",
+			common.FormatNQT(summary.TokensValueNQT), formatValue(summary.TokensValueNQT),
+			common.FormatNQT(summary.TotalBalanceNQT+summary.TokensValueNQT), formatValue(summary.TotalBalanceNQT+summary.TokensValueNQT))
+	}
+
+	if summary.Incomplete {
+		text += "\n\n⚠️ Some accounts or tokens can't be loaded now, the totals are incomplete"
+	}
+
+	return text, pm.GetPortfolioChart(dbUser.ID, summary, signaPrice)
+}
@@ -0,0 +1,142 @@
+package prices
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"github.com/xDWart/signum-explorer-bot/internal/notifier"
+)
+
+type userPriceAlert struct {
+	UserName string
+	ChatID   int64
+	models.PriceAlert
+}
+
+func (pm *PriceManager) checkAlerts(signaPrice map[string]float64) {
+	if signaPrice["USD"] == 0 || signaPrice["BTC"] == 0 {
+		return // prices haven't been received
+	}
+
+	var alerts []userPriceAlert
+	err := pm.db.Model(&models.DbUser{}).
+		Select("exbot_db_users.user_name, exbot_db_users.chat_id, exbot_price_alerts.*").
+		Joins("join exbot_price_alerts on exbot_price_alerts.db_user_id = exbot_db_users.id").
+		Where("exbot_price_alerts.deleted_at IS NULL").
+		Where("exbot_db_users.inactive = false").
+		Scan(&alerts).Error
+	if err != nil {
+		pm.logger.Errorf("Can't get price alerts: %v", err)
+		return
+	}
+
+	var pastPrices = make(map[time.Duration]*models.Price)
+	for i := range alerts {
+		alert := &alerts[i]
+		price := signaPrice[alert.Currency]
+
+		var triggered bool
+		var msg string
+		switch alert.Kind {
+		case models.PRICE_ALERT_ABOVE:
+			triggered = price > alert.Value
+			msg = fmt.Sprintf("📈 <b>SIGNA</b> price is above %v:", FormatPrice(alert.Value, alert.Currency))
+		case models.PRICE_ALERT_BELOW:
+			triggered = price < alert.Value
+			msg = fmt.Sprintf("📉 <b>SIGNA</b> price is below %v:", FormatPrice(alert.Value, alert.Currency))
+		case models.PRICE_ALERT_CHANGE:
+			pastPrice, ok := pastPrices[alert.Period]
+			if !ok {
+				pastPrice = pm.getPastPrice(alert.Period)
+				pastPrices[alert.Period] = pastPrice
+			}
+			if pastPrice == nil {
+				continue
+			}
+			var oldPrice = pastPrice.SignaPrice
+			if alert.Currency == "BTC" {
+				oldPrice = pastPrice.SignaPrice / pastPrice.BtcPrice
+			}
+			change := (price - oldPrice) / oldPrice * 100
+			triggered = math.Abs(change) >= alert.Value
+			var sign string
+			if change > 0 {
+				sign = "+"
+			}
+			msg = fmt.Sprintf("📊 <b>SIGNA</b> price has changed by %v%v%% in %v:"+
+				"\n<i>Was:</i> %v",
+				sign, common.FormatNumber(change, 2), common.FormatPeriod(alert.Period), FormatPrice(oldPrice, alert.Currency))
+		default:
+			continue
+		}
+
+		if !triggered {
+			if alert.Triggered { // re-arm the recurring alert
+				pm.db.Model(&alert.PriceAlert).Update("triggered", false)
+			}
+			continue
+		}
+		if alert.Triggered {
+			continue
+		}
+
+		msg += fmt.Sprintf("\n<i>Price:</i> %v", FormatPrice(price, alert.Currency))
+		if alert.Recurring {
+			msg += "\n<i>Alert:</i> recurring, it will be sent again after the condition has ended"
+		} else {
+			msg += "\n<i>Alert:</i> one-shot, it has been deleted"
+		}
+		err := pm.sink.Send(&notifier.NotifierMessage{
+			UserName: alert.UserName,
+			ChatID:   alert.ChatID,
+			Message:  msg,
+			Event:    &models.NotificationEvent{Type: notifier.EVENT_PRICE_ALERT},
+		})
+		if err != nil {
+			pm.logger.Errorf("Can't send price alert for user %v (Chat.ID %v): %v", alert.UserName, alert.ChatID, err)
+		}
+
+		if alert.Recurring {
+			now := time.Now()
+			pm.db.Model(&alert.PriceAlert).Updates(map[string]interface{}{"triggered": true, "triggered_at": now})
+		} else {
+			pm.db.Unscoped().Delete(&alert.PriceAlert)
+		}
+	}
+}
+
+// getPastPrice returns the last saved price which is older than the period
+func (pm *PriceManager) getPastPrice(period time.Duration) *models.Price {
+	var price models.Price
+	pm.db.Where("created_at <= ?", time.Now().Add(-period)).Order("created_at desc").Limit(1).Find(&price)
+	if price.ID == 0 || price.SignaPrice == 0 || price.BtcPrice == 0 {
+		return nil
+	}
+	return &price
+}
+
+func FormatPrice(price float64, currency string) string {
+	text := strings.TrimRight(strconv.FormatFloat(price, 'f', 8, 64), "0")
+	return strings.TrimSuffix(text, ".") + " " + currency
+}
+
+func FormatPriceAlert(alert *models.PriceAlert) string {
+	var text string
+	switch alert.Kind {
+	case models.PRICE_ALERT_ABOVE, models.PRICE_ALERT_BELOW:
+		text = fmt.Sprintf("price %v %v", alert.Kind, FormatPrice(alert.Value, alert.Currency))
+	case models.PRICE_ALERT_CHANGE:
+		text = fmt.Sprintf("change %v%% in %v (%v)", common.FormatNumber(alert.Value, 2), common.FormatPeriod(alert.Period), alert.Currency)
+	default:
+		text = string(alert.Kind)
+	}
+	if alert.Recurring {
+		text += ", recurring"
+	}
+	return text
+}
@@ -0,0 +1,37 @@
+package prices
+
+import (
+	"sort"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+// GetPriceHistory returns saved prices for the period with one more price before it, ordered by time
+func (pm *PriceManager) GetPriceHistory(from, to time.Time) []models.Price {
+	var prices []models.Price
+	var before models.Price
+	pm.db.Where("created_at < ?", from).Order("created_at desc").Limit(1).Find(&before)
+	if before.ID != 0 {
+		prices = append(prices, before)
+	}
+
+	var period []models.Price
+	if err := pm.db.Where("created_at >= ? AND created_at <= ?", from, to).Order("created_at").Find(&period).Error; err != nil {
+		pm.logger.Errorf("Can't get prices from %v to %v: %v", from, to, err)
+	}
+	return append(prices, period...)
+}
+
+// FindPrice returns the last price saved before the time or the first one after it if there is none,
+// prices are ordered by time
+func FindPrice(prices []models.Price, t time.Time) *models.Price {
+	if len(prices) == 0 {
+		return nil
+	}
+	i := sort.Search(len(prices), func(i int) bool { return prices[i].CreatedAt.After(t) })
+	if i > 0 {
+		i--
+	}
+	return &prices[i]
+}
@@ -31,6 +31,10 @@ func (pm *PriceManager) startListener(wg *sync.WaitGroup, shutdownChannel chan i
 				SignaPrice: prices["SIGNA"].Usd,
 				BtcPrice:   prices["BTC"].Usd,
 			}
+			pm.checkAlerts(map[string]float64{
+				"USD": prices["SIGNA"].Usd,
+				"BTC": prices["SIGNA"].Btc,
+			})
 			sampleIndex = (sampleIndex + 1) % pm.config.SmoothingFactor
 			timeToSave = (timeToSave + 1) % pm.config.SaveEveryNSamples
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/xDWart/signum-explorer-bot/api/geckoapi"
 	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/notifier"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -16,6 +17,7 @@ type PriceManager struct {
 	db          *gorm.DB
 	logger      *zap.SugaredLogger
 	geckoClient *geckoapi.GeckoClient
+	sink        notifier.Sink
 	config      *Config
 }
 
@@ -28,11 +30,12 @@ type Config struct {
 	DelayFuncB        time.Duration
 }
 
-func NewPricesManager(logger *zap.SugaredLogger, db *gorm.DB, geckoClient *geckoapi.GeckoClient, wg *sync.WaitGroup, shutdownChannel chan interface{}, config *Config) *PriceManager {
+func NewPricesManager(logger *zap.SugaredLogger, db *gorm.DB, geckoClient *geckoapi.GeckoClient, sink notifier.Sink, wg *sync.WaitGroup, shutdownChannel chan interface{}, config *Config) *PriceManager {
 	pm := PriceManager{
 		db:          db,
 		logger:      logger,
 		geckoClient: geckoClient,
+		sink:        sink,
 		config:      config,
 	}
 	wg.Add(1)
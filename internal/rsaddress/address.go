@@ -0,0 +1,148 @@
+package rsaddress
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	PREFIX_SIGNUM  = "S"
+	PREFIX_BURST   = "BURST" // legacy addresses, still accepted by the nodes
+	PREFIX_TESTNET = "TS"
+)
+
+var (
+	ErrFormat    = errors.New("incorrect address format")
+	ErrPrefix    = errors.New("unknown address prefix")
+	ErrPublicKey = errors.New("the public key doesn't match the address")
+)
+
+// ChecksumError is returned for addresses with a typo, valid addresses similar to the given one are suggested
+type ChecksumError struct {
+	Suggestions []string
+}
+
+func newChecksumError(prefix string, suggestions []codeword) *ChecksumError {
+	checksumError := &ChecksumError{}
+	for _, suggestion := range suggestions {
+		checksumError.Suggestions = append(checksumError.Suggestions, prefix+"-"+suggestion.String())
+	}
+	return checksumError
+}
+
+func (e *ChecksumError) Error() string {
+	return "invalid address checksum"
+}
+
+type Address struct {
+	Prefix    string
+	ID        uint64
+	PublicKey []byte // only in the extended form
+}
+
+// String returns the address in the short form
+func (a *Address) String() string {
+	return Format(a.Prefix, a.ID)
+}
+
+// Extended returns the address with the public key in base36 if it's known
+func (a *Address) Extended() string {
+	if len(a.PublicKey) == 0 {
+		return a.String()
+	}
+	return a.String() + "-" + strings.ToUpper(new(big.Int).SetBytes(a.PublicKey).Text(36))
+}
+
+// Format returns the address of the ID with the prefix like S-XXXX-XXXX-XXXX-XXXXX
+func Format(prefix string, id uint64) string {
+	cw := encode(id)
+	return prefix + "-" + cw.String()
+}
+
+// ToRS returns the Signum mainnet address of the ID
+func ToRS(id uint64) string {
+	return Format(PREFIX_SIGNUM, id)
+}
+
+// Parse decodes the address in the short or extended form, the prefix and the letters are case-insensitive
+func Parse(address string) (*Address, error) {
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(address)), "-")
+	if len(parts) != 5 && len(parts) != 6 {
+		return nil, ErrFormat
+	}
+	switch parts[0] {
+	case PREFIX_SIGNUM, PREFIX_BURST, PREFIX_TESTNET:
+	default:
+		return nil, ErrPrefix
+	}
+	if len(parts[1]) != 4 || len(parts[2]) != 4 || len(parts[3]) != 4 || len(parts[4]) != 5 {
+		return nil, ErrFormat
+	}
+
+	cw, unknown, ok := parseCodeword(strings.Join(parts[1:5], ""))
+	if !ok || len(unknown) > 1 {
+		return nil, ErrFormat
+	}
+	if len(unknown) == 1 { // like O instead of 0
+		return nil, newChecksumError(parts[0], cw.guess(unknown[0]))
+	}
+	id, ok := cw.decode()
+	if !ok || !cw.isValid() {
+		return nil, newChecksumError(parts[0], cw.suggest())
+	}
+
+	result := &Address{Prefix: parts[0], ID: id}
+	if len(parts) == 6 {
+		publicKey, err := parsePublicKey(parts[5])
+		if err != nil {
+			return nil, err
+		}
+		if GetID(publicKey) != id {
+			return nil, ErrPublicKey
+		}
+		result.PublicKey = publicKey
+	}
+	return result, nil
+}
+
+func parsePublicKey(base36 string) ([]byte, error) {
+	value, ok := new(big.Int).SetString(base36, 36)
+	if !ok || value.BitLen() > 256 {
+		return nil, ErrFormat
+	}
+	return value.FillBytes(make([]byte, 32)), nil
+}
+
+// GetID returns the account ID of the public key: the first 8 bytes of its SHA-256 hash
+func GetID(publicKey []byte) uint64 {
+	hash := sha256.Sum256(publicKey)
+	return binary.LittleEndian.Uint64(hash[:8])
+}
+
+// ParseAccount returns the account ID of the numeric ID or the address in any form
+func ParseAccount(account string) (uint64, error) {
+	account = strings.TrimSpace(account)
+	if account != "" && strings.Trim(account, "0123456789") == "" {
+		id, err := strconv.ParseUint(account, 10, 64)
+		if err != nil {
+			return 0, ErrFormat
+		}
+		return id, nil
+	}
+	address, err := Parse(account)
+	if err != nil {
+		return 0, err
+	}
+	return address.ID, nil
+}
+
+// IsAddress reports whether the text looks like an address, the checksum isn't verified
+func IsAddress(text string) bool {
+	_, err := Parse(text)
+	var checksumError *ChecksumError
+	return err == nil || errors.As(err, &checksumError)
+}
@@ -0,0 +1,155 @@
+package rsaddress
+
+import "strings"
+
+// the Reed-Solomon code over GF(32) used by Signum addresses: 13 data symbols of the account ID and 4 check symbols
+const (
+	alphabet       = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+	dataLength     = 13
+	codewordLength = 17
+)
+
+var (
+	gexp = [32]int{1, 2, 4, 8, 16, 5, 10, 20, 13, 26, 17, 7, 14, 28, 29, 31, 27, 19, 3, 6, 12, 24, 21, 15, 30, 25, 23, 11, 22, 9, 18, 1}
+	glog = [32]int{0, 0, 1, 18, 2, 5, 19, 11, 3, 29, 6, 27, 20, 8, 12, 23, 4, 10, 30, 17, 7, 22, 28, 26, 21, 25, 9, 16, 13, 14, 24, 15}
+	// position of each address character in the codeword
+	codewordMap = [codewordLength]int{3, 2, 1, 0, 7, 6, 5, 4, 13, 14, 15, 16, 12, 8, 9, 10, 11}
+)
+
+type codeword [codewordLength]int
+
+func gmult(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gexp[(glog[a]+glog[b])%31]
+}
+
+// encode returns the codeword of the ID, data symbols are base-32 digits from the least significant one
+func encode(id uint64) codeword {
+	var cw codeword
+	for i := 0; i < dataLength; i++ {
+		cw[i] = int(id >> (5 * i) & 31)
+	}
+
+	var p [4]int
+	for i := dataLength - 1; i >= 0; i-- {
+		fb := cw[i] ^ p[3]
+		p[3] = p[2] ^ gmult(30, fb)
+		p[2] = p[1] ^ gmult(6, fb)
+		p[1] = p[0] ^ gmult(9, fb)
+		p[0] = gmult(17, fb)
+	}
+	copy(cw[dataLength:], p[:])
+	return cw
+}
+
+// decode returns the ID of the codeword, false if the data symbols don't fit into 64 bits
+func (cw *codeword) decode() (uint64, bool) {
+	if cw[dataLength-1] > 15 {
+		return 0, false
+	}
+	var id uint64
+	for i := dataLength - 1; i >= 0; i-- {
+		id = id<<5 | uint64(cw[i])
+	}
+	return id, true
+}
+
+func (cw *codeword) isValid() bool {
+	var sum int
+	for i := 1; i < 5; i++ {
+		var t int
+		for j := 0; j < 31; j++ {
+			if j > 12 && j < 27 {
+				continue
+			}
+			pos := j
+			if j > 26 {
+				pos -= 14
+			}
+			t ^= gmult(cw[pos], gexp[(i*j)%31])
+		}
+		sum |= t
+	}
+	return sum == 0
+}
+
+// String returns the address body like XXXX-XXXX-XXXX-XXXXX
+func (cw *codeword) String() string {
+	var sb strings.Builder
+	for i := 0; i < codewordLength; i++ {
+		sb.WriteByte(alphabet[cw[codewordMap[i]]])
+		if i&3 == 3 && i < dataLength {
+			sb.WriteByte('-')
+		}
+	}
+	return sb.String()
+}
+
+// parseCodeword reads 17 address characters, dashes are ignored. A character out of the alphabet is stored as -1
+// and its position is returned, so it can be guessed like a typo
+func parseCodeword(body string) (codeword, []int, bool) {
+	var cw codeword
+	var unknown []int
+	var n int
+	for _, c := range body {
+		if c == '-' {
+			continue
+		}
+		if n == codewordLength {
+			return cw, nil, false
+		}
+		index := strings.IndexRune(alphabet, c)
+		if index < 0 {
+			unknown = append(unknown, n)
+		}
+		cw[codewordMap[n]] = index
+		n++
+	}
+	return cw, unknown, n == codewordLength
+}
+
+// guess returns valid codewords with the unknown character replaced
+func (cw *codeword) guess(position int) []codeword {
+	var suggestions []codeword
+	for symbol := 0; symbol < len(alphabet); symbol++ {
+		candidate := *cw
+		candidate[codewordMap[position]] = symbol
+		if _, ok := candidate.decode(); ok && candidate.isValid() {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	return suggestions
+}
+
+// suggest returns valid codewords which differ from the given one by a single character or by two swapped neighbours
+func (cw *codeword) suggest() []codeword {
+	var suggestions []codeword
+	var seen = make(map[codeword]bool)
+	var add = func(candidate codeword) {
+		if _, ok := candidate.decode(); ok && candidate.isValid() && !seen[candidate] {
+			seen[candidate] = true
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	for i := 0; i < codewordLength; i++ {
+		for symbol := 0; symbol < len(alphabet); symbol++ {
+			if symbol == cw[codewordMap[i]] {
+				continue
+			}
+			candidate := *cw
+			candidate[codewordMap[i]] = symbol
+			add(candidate)
+		}
+	}
+	for i := 0; i+1 < codewordLength; i++ {
+		candidate := *cw
+		candidate[codewordMap[i]], candidate[codewordMap[i+1]] = candidate[codewordMap[i+1]], candidate[codewordMap[i]]
+		if candidate != *cw {
+			add(candidate)
+		}
+	}
+	return suggestions
+}
@@ -0,0 +1,147 @@
+package rsaddress
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+var vectors = map[uint64]string{
+	0:                   "S-2222-2222-2222-22222",
+	8301188658053077183: "S-4Q7Z-5BEE-F5JZ-9ZXE8",
+	1798923958688893959: "S-GM29-TWRT-M5CK-3HSXK",
+}
+
+func TestFormatVectors(t *testing.T) {
+	for id, address := range vectors {
+		if got := ToRS(id); got != address {
+			t.Errorf("ToRS(%v) = %v, want %v", id, got, address)
+		}
+	}
+}
+
+func TestParseVectors(t *testing.T) {
+	for id, address := range vectors {
+		parsed, err := Parse(address)
+		if err != nil {
+			t.Errorf("Parse(%v) returned error: %v", address, err)
+			continue
+		}
+		if parsed.ID != id || parsed.Prefix != PREFIX_SIGNUM {
+			t.Errorf("Parse(%v) = %v %v, want %v %v", address, parsed.Prefix, parsed.ID, PREFIX_SIGNUM, id)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		id := random.Uint64()
+		address := ToRS(id)
+		parsed, err := Parse(address)
+		if err != nil {
+			t.Fatalf("Parse(%v) of %v returned error: %v", address, id, err)
+		}
+		if parsed.ID != id {
+			t.Fatalf("Parse(ToRS(%v)) = %v", id, parsed.ID)
+		}
+		if parsed.String() != address {
+			t.Fatalf("String() = %v, want %v", parsed.String(), address)
+		}
+	}
+}
+
+func TestParsePrefixes(t *testing.T) {
+	var tests = []struct {
+		address string
+		prefix  string
+	}{
+		{"S-4Q7Z-5BEE-F5JZ-9ZXE8", PREFIX_SIGNUM},
+		{"BURST-4Q7Z-5BEE-F5JZ-9ZXE8", PREFIX_BURST},
+		{"TS-4Q7Z-5BEE-F5JZ-9ZXE8", PREFIX_TESTNET},
+		{"s-4q7z-5bee-f5jz-9zxe8", PREFIX_SIGNUM},
+		{"burst-4Q7Z-5bee-F5JZ-9zxe8", PREFIX_BURST},
+		{" ts-4q7z-5bee-f5jz-9zxe8 ", PREFIX_TESTNET},
+	}
+	for _, test := range tests {
+		parsed, err := Parse(test.address)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", test.address, err)
+			continue
+		}
+		if parsed.Prefix != test.prefix || parsed.ID != 8301188658053077183 {
+			t.Errorf("Parse(%q) = %v %v, want %v 8301188658053077183", test.address, parsed.Prefix, parsed.ID, test.prefix)
+		}
+	}
+
+	if _, err := Parse("X-4Q7Z-5BEE-F5JZ-9ZXE8"); !errors.Is(err, ErrPrefix) {
+		t.Errorf("unknown prefix: error = %v, want %v", err, ErrPrefix)
+	}
+	for _, address := range []string{"", "S-4Q7Z-5BEE-F5JZ", "S-4Q7Z5-BEE-F5JZ-9ZXE8", "S-4Q7Z-5BEE-F5JZ-9ZXE"} {
+		if _, err := Parse(address); !errors.Is(err, ErrFormat) {
+			t.Errorf("Parse(%q): error = %v, want %v", address, err, ErrFormat)
+		}
+	}
+}
+
+func TestChecksumSuggestions(t *testing.T) {
+	var tests = []struct {
+		address    string
+		suggestion string
+	}{
+		{"S-8N2F-TDD7-4LY6-64FZ8", "S-8N2F-TDD7-4LY6-64FZ7"}, // wrong character
+		{"S-8N2F-TD7D-4LY6-64FZ7", "S-8N2F-TDD7-4LY6-64FZ7"}, // swapped characters
+		{"S-8N2F-TDD7-4LY6-O4FZ7", "S-8N2F-TDD7-4LY6-64FZ7"}, // not an alphabet character
+		{"burst-4q7z-5bee-f5jz-9zxe9", "BURST-4Q7Z-5BEE-F5JZ-9ZXE8"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.address)
+		var checksumError *ChecksumError
+		if !errors.As(err, &checksumError) {
+			t.Errorf("Parse(%v): error = %v, want ChecksumError", test.address, err)
+			continue
+		}
+		var found bool
+		for _, suggestion := range checksumError.Suggestions {
+			found = found || suggestion == test.suggestion
+		}
+		if !found {
+			t.Errorf("Parse(%v): suggestions %v don't contain %v", test.address, checksumError.Suggestions, test.suggestion)
+		}
+		if !IsAddress(test.address) {
+			t.Errorf("IsAddress(%v) = false", test.address)
+		}
+	}
+}
+
+func TestExtendedAddress(t *testing.T) {
+	publicKey := bytes.Repeat([]byte{0x5a}, 32)
+	otherPublicKey := bytes.Repeat([]byte{0xa5}, 32)
+
+	address := &Address{Prefix: PREFIX_SIGNUM, ID: GetID(publicKey), PublicKey: publicKey}
+	parsed, err := Parse(address.Extended())
+	if err != nil {
+		t.Fatalf("Parse(%v) returned error: %v", address.Extended(), err)
+	}
+	if parsed.ID != address.ID || !bytes.Equal(parsed.PublicKey, publicKey) {
+		t.Errorf("Parse(%v) = %v %x, want %v %x", address.Extended(), parsed.ID, parsed.PublicKey, address.ID, publicKey)
+	}
+
+	mismatched := &Address{Prefix: PREFIX_SIGNUM, ID: GetID(otherPublicKey), PublicKey: publicKey}
+	if _, err := Parse(mismatched.Extended()); !errors.Is(err, ErrPublicKey) {
+		t.Errorf("Parse(%v): error = %v, want %v", mismatched.Extended(), err, ErrPublicKey)
+	}
+}
+
+func TestParseAccount(t *testing.T) {
+	for _, account := range []string{"8301188658053077183", "S-4Q7Z-5BEE-F5JZ-9ZXE8", "burst-4q7z-5bee-f5jz-9zxe8"} {
+		id, err := ParseAccount(account)
+		if err != nil || id != 8301188658053077183 {
+			t.Errorf("ParseAccount(%v) = %v, %v, want 8301188658053077183", account, id, err)
+		}
+	}
+	if _, err := ParseAccount("18446744073709551616"); !errors.Is(err, ErrFormat) {
+		t.Errorf("overflow: error = %v, want %v", err, ErrFormat)
+	}
+}
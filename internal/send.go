@@ -25,6 +25,9 @@ func (bot *AbstractTelegramBot) SendAnswer(chatID int64, answer *users.BotMessag
 		if answer.InlineText != "" {
 			bot.SendMessage(chatID, answer.InlineText, answer.InlineKeyboard)
 		}
+		if len(answer.Document) > 0 {
+			bot.NewDocumentUpload(chatID, answer.DocumentName, answer.Document)
+		}
 	} else { // need edit existing message
 		if len(answer.Chart) > 0 {
 			bot.EditPhotoMessage(chatID, answer.MessageID, "", answer.Chart, answer.InlineKeyboard)
@@ -52,6 +55,14 @@ func (bot *AbstractTelegramBot) NewPhotoUpload(chatID int64, text string, payloa
 	bot.ConfigureAndSend(photoConfig)
 }
 
+func (bot *AbstractTelegramBot) NewDocumentUpload(chatID int64, name string, payload []byte) {
+	fileBytes := tgbotapi.FileBytes{
+		Name:  name,
+		Bytes: payload,
+	}
+	bot.ConfigureAndSend(tgbotapi.NewDocumentUpload(chatID, fileBytes))
+}
+
 func (bot *AbstractTelegramBot) EditPhotoMessage(chatID int64, messageID int, text string, payload []byte, replyMarkup interface{}) {
 	deleteMessageConfig := tgbotapi.NewDeleteMessage(chatID, messageID)
 	bot.BotAPI.Request(deleteMessageConfig)
@@ -113,3 +124,18 @@ func (bot *AbstractTelegramBot) Send(msg tgbotapi.Chattable) {
 		bot.logger.Errorf("Send error: %v. Msg: %#v", err, msg)
 	}
 }
+
+// SendNotification returns the delivery error instead of logging it, the outbox listener decides whether to retry
+func (bot *AbstractTelegramBot) SendNotification(chatID int64, text string) (int, error) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	message, err := bot.BotAPI.Send(msg)
+	return message.MessageID, err
+}
+
+func (bot *AbstractTelegramBot) EditNotification(chatID int64, messageID int, text string) error {
+	msg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	_, err := bot.BotAPI.Send(msg)
+	return err
+}
@@ -0,0 +1,68 @@
+package tokens
+
+import (
+	"fmt"
+	"html"
+	"math"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"github.com/xDWart/signum-explorer-bot/internal/notifier"
+)
+
+type userTokenWatch struct {
+	UserName string
+	ChatID   int64
+	models.TokenWatch
+}
+
+// checkPriceAlerts compares the actual price with the price of the last alert of each watch
+func (tm *TokenManager) checkPriceAlerts(asset *signumapi.Asset) {
+	var watches []userTokenWatch
+	err := tm.db.Model(&models.DbUser{}).
+		Select("exbot_db_users.user_name, exbot_db_users.chat_id, exbot_token_watches.*").
+		Joins("join exbot_token_watches on exbot_token_watches.db_user_id = exbot_db_users.id").
+		Where("exbot_token_watches.deleted_at IS NULL").
+		Where("exbot_token_watches.asset = ? AND exbot_token_watches.price_percent > 0", asset.Asset).
+		Where("exbot_db_users.inactive = false").
+		Scan(&watches).Error
+	if err != nil {
+		tm.logger.Errorf("Can't get watches of token %v: %v", asset.Asset, err)
+		return
+	}
+
+	price := asset.GetPriceNQT()
+	for i := range watches {
+		watch := &watches[i]
+		if watch.LastPriceNQT == 0 { // the first trade since the token is watched
+			tm.db.Model(&watch.TokenWatch).Update("last_price_nqt", price)
+			continue
+		}
+
+		change := (price - watch.LastPriceNQT) / watch.LastPriceNQT * 100
+		if math.Abs(change) < watch.PricePercent {
+			continue
+		}
+
+		var icon, sign = "📉", ""
+		if change > 0 {
+			icon, sign = "📈", "+"
+		}
+		msg := fmt.Sprintf("%v <b>%v</b> token price has changed by %v%v%% since the last alert:"+
+			"\n<i>Was:</i> %v SIGNA"+
+			"\n<i>Price:</i> %v SIGNA",
+			icon, html.EscapeString(asset.Name), sign, common.FormatNumber(change, 2),
+			common.FormatNumber(asset.GetTokenPrice(watch.LastPriceNQT), 8), common.FormatNumber(asset.GetPrice(), 8))
+		err := tm.sink.Send(&notifier.NotifierMessage{
+			UserName: watch.UserName,
+			ChatID:   watch.ChatID,
+			Message:  msg,
+			Event:    &models.NotificationEvent{Type: notifier.EVENT_TOKEN_PRICE_ALERT},
+		})
+		if err != nil {
+			tm.logger.Errorf("Can't send token alert for user %v (Chat.ID %v): %v", watch.UserName, watch.ChatID, err)
+		}
+		tm.db.Model(&watch.TokenWatch).Update("last_price_nqt", price)
+	}
+}
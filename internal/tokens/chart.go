@@ -0,0 +1,72 @@
+package tokens
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+// GetTokenChart plots sampled prices of the token with the actual price as the last point, prices are sampled for watched, held and the most traded tokens
+func (tm *TokenManager) GetTokenChart(asset *signumapi.Asset) []byte {
+	var prices []models.AssetPrice
+	result := tm.db.Where("asset = ?", asset.Asset).Order("id asc").Find(&prices)
+	if result.Error != nil {
+		tm.logger.Errorf("Error getting prices of token %v from DB for plotting chart: %v", asset.Asset, result.Error)
+		return nil
+	}
+	if len(prices) == 0 { // nothing to plot yet
+		return nil
+	}
+
+	graph := chart.Chart{
+		Title: fmt.Sprintf("%v price", asset.Name),
+		Background: chart.Style{
+			Padding: chart.Box{
+				Top:  50,
+				Left: 20,
+			},
+		},
+		XAxis: chart.XAxis{
+			ValueFormatter: chart.TimeDateValueFormatter,
+		},
+		YAxis: chart.YAxis{
+			Name: "SIGNA",
+		},
+		Series: []chart.Series{},
+	}
+
+	priceChartTimeSeries := chart.TimeSeries{
+		Name: asset.Name,
+		Style: chart.Style{
+			StrokeColor: chart.GetDefaultColor(1),
+			FillColor:   chart.GetDefaultColor(1).WithAlpha(80),
+		},
+		XValues: []time.Time{},
+		YValues: []float64{},
+	}
+
+	for _, price := range prices {
+		priceChartTimeSeries.XValues = append(priceChartTimeSeries.XValues, price.CreatedAt)
+		priceChartTimeSeries.YValues = append(priceChartTimeSeries.YValues, asset.GetTokenPrice(price.PriceNQT))
+	}
+	priceChartTimeSeries.XValues = append(priceChartTimeSeries.XValues, time.Now())
+	priceChartTimeSeries.YValues = append(priceChartTimeSeries.YValues, asset.GetPrice())
+
+	graph.Series = append(graph.Series, priceChartTimeSeries)
+	graph.Elements = []chart.Renderable{
+		chart.Legend(&graph),
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	err := graph.Render(chart.PNG, buffer)
+	if err != nil {
+		tm.logger.Errorf("Could not render chart: %v", err)
+		return nil
+	}
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,236 @@
+package tokens
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+const (
+	defaultPricePercent    = 10
+	defaultTransferPercent = 1 // of the circulating supply
+	maxDescriptionLength   = 300
+)
+
+const tokenUsageText = `📇 Send <b>` + config.COMMAND_TOKEN + ` ASSET_ID_OR_NAME</b> to explore a token: supply, holders, trades and price.
+<b>` + config.COMMAND_TOKEN + ` TOKEN watch [PERCENT%] [QUANTITY]</b> - alert when the price changes by PERCENT (10% by default) and on transfers of at least QUANTITY tokens (1% of the circulating supply by default), 0 disables the alert
+<b>` + config.COMMAND_TOKEN + ` TOKEN unwatch</b> - stop watching the token`
+
+// GetTokenInfo processes the token command of the user, the chart is returned for the token view only
+func (tm *TokenManager) GetTokenInfo(dbUser *models.DbUser, message string) (string, []byte) {
+	splittedMessage := strings.Split(message, " ")
+	if len(splittedMessage) < 2 || splittedMessage[0] != config.COMMAND_TOKEN {
+		return tokenUsageText + tm.getWatchesText(dbUser.ID), nil
+	}
+
+	asset, text := tm.findAsset(splittedMessage[1])
+	if asset == nil {
+		return text, nil
+	}
+
+	if len(splittedMessage) > 2 {
+		switch strings.ToLower(splittedMessage[2]) {
+		case "watch":
+			return tm.watchToken(dbUser.ID, asset, splittedMessage[3:]), nil
+		case "unwatch":
+			tm.db.Unscoped().Where("db_user_id = ? AND asset = ?", dbUser.ID, asset.Asset).Delete(&models.TokenWatch{})
+			return fmt.Sprintf("✅ You don't watch <b>%v</b> anymore", html.EscapeString(asset.Name)), nil
+		default:
+			return fmt.Sprintf("🚫 Incorrect command format, please send <b>%v TOKEN [watch|unwatch]</b>", config.COMMAND_TOKEN), nil
+		}
+	}
+
+	return tm.getTokenText(dbUser.ID, asset), tm.GetTokenChart(asset)
+}
+
+// findAsset looks the token up by ID at first and then by name, the text explains why it's not found
+func (tm *TokenManager) findAsset(query string) (*signumapi.Asset, string) {
+	if config.ValidAccount.MatchString(query) {
+		asset, err := tm.signumClient.GetCachedAsset(tm.logger, query)
+		if err == nil {
+			return asset, ""
+		}
+	}
+
+	assets, err := tm.signumClient.GetAssetsByName(tm.logger, query)
+	if err != nil || len(assets.Assets) == 0 {
+		return nil, fmt.Sprintf("🚫 Token <b>%v</b> not found", html.EscapeString(query))
+	}
+	if len(assets.Assets) == 1 {
+		return &assets.Assets[0], ""
+	}
+
+	var text = fmt.Sprintf("📇 There are %v tokens named <b>%v</b>, please use the asset ID:\n", len(assets.Assets), html.EscapeString(query))
+	for _, asset := range assets.Assets {
+		text += fmt.Sprintf("\n<code>%v</code> issued by %v", asset.Asset, asset.AccountRS)
+	}
+	return nil, text
+}
+
+func parseQNT(quantityQNT string) uint64 {
+	quantity, _ := strconv.ParseUint(quantityQNT, 10, 64)
+	return quantity
+}
+
+func formatQuantity(asset *signumapi.Asset, quantityQNT uint64) string {
+	return common.FormatNumber(asset.GetQuantity(quantityQNT), int(asset.Decimals))
+}
+
+func formatPrice(asset *signumapi.Asset, priceNQT string) string {
+	price, _ := strconv.ParseFloat(priceNQT, 64)
+	return common.FormatNumber(asset.GetTokenPrice(price), 8)
+}
+
+func (tm *TokenManager) getTokenText(dbUserID uint, asset *signumapi.Asset) string {
+	var text = fmt.Sprintf("📇 <b>%v</b>\n<i>Asset ID:</i> <code>%v</code>", html.EscapeString(asset.Name), asset.Asset)
+	if asset.Description != "" {
+		description := []rune(asset.Description)
+		if len(description) > maxDescriptionLength {
+			description = append(description[:maxDescriptionLength], '…')
+		}
+		text += "\n<i>Description:</i> " + html.EscapeString(string(description))
+	}
+	text += "\n<i>Issuer:</i> " + asset.AccountRS
+	if issuerName := tm.signumClient.GetCachedAccountName(tm.logger, asset.Account); issuerName != "" {
+		text += " (" + html.EscapeString(issuerName) + ")"
+	}
+	var mintable = "no"
+	if asset.Mintable {
+		mintable = "yes"
+	}
+	text += fmt.Sprintf("\n<i>Decimals:</i> %v  <i>Mintable:</i> %v", asset.Decimals, mintable)
+
+	text += fmt.Sprintf("\n\n<i>Supply:</i> %v"+
+		"\n<i>Burnt:</i> %v"+
+		"\n<i>Circulating:</i> %v"+
+		"\n<i>Holders:</i> %v  <i>Transfers:</i> %v  <i>Trades:</i> %v"+
+		"\n<i>Volume:</i> %v",
+		formatQuantity(asset, parseQNT(asset.QuantityQNT)),
+		formatQuantity(asset, parseQNT(asset.QuantityBurntQNT)),
+		formatQuantity(asset, asset.GetCirculatingQNT()),
+		asset.NumberOfAccounts, asset.NumberOfTransfers, asset.NumberOfTrades,
+		formatQuantity(asset, parseQNT(asset.VolumeQNT)))
+
+	if asset.NumberOfTrades > 0 {
+		text += fmt.Sprintf("\n\n💹 <b>Price, SIGNA:</b>"+
+			"\n<i>Open:</i> %v  <i>High:</i> %v"+
+			"\n<i>Low:</i> %v  <i>Close:</i> %v",
+			formatPrice(asset, asset.PriceOpen), formatPrice(asset, asset.PriceHigh),
+			formatPrice(asset, asset.PriceLow), formatPrice(asset, asset.PriceClose))
+	} else {
+		text += "\n\n💹 <i>No trades yet</i>"
+	}
+
+	holders, err := tm.signumClient.GetAssetTopHolders(tm.logger, asset.Asset, tm.config.TopHolders)
+	if err != nil {
+		tm.logger.Errorf("Can't get top holders of token %v: %v", asset.Asset, err)
+	} else if len(holders.AccountAssets) > 0 {
+		text += "\n\n🏦 <b>Top holders:</b>"
+		circulatingQNT := asset.GetCirculatingQNT()
+		for i, holder := range holders.AccountAssets {
+			text += fmt.Sprintf("\n%v. %v: %v", i+1, holder.AccountRS, formatQuantity(asset, holder.QuantityQNT))
+			if circulatingQNT > 0 {
+				text += fmt.Sprintf(" (%v%%)", common.FormatNumber(float64(holder.QuantityQNT)/float64(circulatingQNT)*100, 2))
+			}
+		}
+	}
+
+	trades, err := tm.signumClient.GetAssetTrades(tm.logger, asset.Asset, tm.config.RecentTrades)
+	if err != nil {
+		tm.logger.Errorf("Can't get trades of token %v: %v", asset.Asset, err)
+	} else if len(trades.Trades) > 0 {
+		text += "\n\n🔁 <b>Recent trades:</b>"
+		for _, trade := range trades.Trades {
+			text += fmt.Sprintf("\n%v %v %v @ %v SIGNA",
+				common.FormatChainTimeToStringDatetimeUTC(trade.Timestamp), trade.TradeType,
+				formatQuantity(asset, trade.QuantityQNT), formatPrice(asset, strconv.FormatUint(trade.PriceNQT, 10)))
+		}
+	}
+
+	var watch models.TokenWatch
+	tm.db.Where("db_user_id = ? AND asset = ?", dbUserID, asset.Asset).Limit(1).Find(&watch)
+	if watch.ID != 0 {
+		text += "\n\n👁 You watch this token: " + formatWatch(asset, &watch)
+	} else {
+		text += fmt.Sprintf("\n\n👁 Send <b>%v %v watch</b> to get price and large transfer alerts", config.COMMAND_TOKEN, asset.Asset)
+	}
+	return text
+}
+
+func formatWatch(asset *signumapi.Asset, watch *models.TokenWatch) string {
+	var alerts []string
+	if watch.PricePercent > 0 {
+		alerts = append(alerts, fmt.Sprintf("price change %v%%", common.FormatNumber(watch.PricePercent, 2)))
+	}
+	if watch.TransferQNT > 0 {
+		alerts = append(alerts, fmt.Sprintf("transfers from %v", formatQuantity(asset, watch.TransferQNT)))
+	}
+	if len(alerts) == 0 {
+		return "no alerts"
+	}
+	return strings.Join(alerts, ", ")
+}
+
+func (tm *TokenManager) watchToken(dbUserID uint, asset *signumapi.Asset, args []string) string {
+	var watch models.TokenWatch
+	tm.db.Where("db_user_id = ? AND asset = ?", dbUserID, asset.Asset).Limit(1).Find(&watch)
+	watch.DbUserID = dbUserID
+	watch.Asset = asset.Asset
+	watch.PricePercent = defaultPricePercent
+	watch.TransferQNT = asset.GetCirculatingQNT() * defaultTransferPercent / 100
+	watch.LastPriceNQT = asset.GetPriceNQT()
+
+	for _, arg := range args {
+		if strings.HasSuffix(arg, "%") {
+			percent, err := common.ParseNumber(strings.TrimSuffix(arg, "%"))
+			if err != nil {
+				return err.Error()
+			}
+			if percent < 0 {
+				return "🚫 Percent should not be negative"
+			}
+			watch.PricePercent = percent
+			continue
+		}
+		quantity, err := common.ParseNumber(arg)
+		if err != nil {
+			return err.Error()
+		}
+		if quantity < 0 {
+			return "🚫 Quantity should not be negative"
+		}
+		watch.TransferQNT = uint64(quantity * math.Pow10(int(asset.Decimals)))
+	}
+
+	if err := tm.db.Save(&watch).Error; err != nil {
+		tm.logger.Errorf("Can't save token watch for user %v: %v", dbUserID, err)
+		return "🚫 Can't save the watch, please try again later"
+	}
+	return fmt.Sprintf("✅ You watch <b>%v</b>: %v", html.EscapeString(asset.Name), formatWatch(asset, &watch))
+}
+
+func (tm *TokenManager) getWatchesText(dbUserID uint) string {
+	var watches []models.TokenWatch
+	tm.db.Where("db_user_id = ?", dbUserID).Order("id").Find(&watches)
+	if len(watches) == 0 {
+		return ""
+	}
+
+	var text = "\n\n👁 <b>Your watched tokens:</b>"
+	for i := range watches {
+		asset, err := tm.signumClient.GetCachedAsset(tm.logger, watches[i].Asset)
+		if err != nil {
+			tm.logger.Errorf("Can't get asset %v: %v", watches[i].Asset, err)
+			asset = &signumapi.Asset{Asset: watches[i].Asset, Name: watches[i].Asset}
+		}
+		text += fmt.Sprintf("\n%v (<code>%v</code>): %v", html.EscapeString(asset.Name), asset.Asset, formatWatch(asset, &watches[i]))
+	}
+	return text
+}
@@ -0,0 +1,102 @@
+package tokens
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+func (tm *TokenManager) startListener(wg *sync.WaitGroup, shutdownChannel chan interface{}) {
+	defer wg.Done()
+
+	tm.logger.Infof("Start Token Listener")
+	ticker := time.NewTicker(tm.config.SamplePeriod)
+
+	samplesForAveraging := make(map[string][]float64)
+	var timeToSave uint
+	var scanIndex int
+	var heldAssets []string
+	var heldAssetsUpdatedAt time.Time
+	var lastSampleTime = time.Now().Add(-tm.config.SamplePeriod)
+
+	for {
+		select {
+		case <-shutdownChannel:
+			tm.logger.Infof("Token Listener received shutdown signal")
+			ticker.Stop()
+			return
+
+		case <-ticker.C:
+			if time.Since(heldAssetsUpdatedAt) >= tm.config.HoldingsPeriod {
+				heldAssets = tm.getHeldAssets(shutdownChannel)
+				heldAssetsUpdatedAt = time.Now()
+			}
+			watchedAssets := tm.getWatchedAssets()
+			var watched = make(map[string]bool, len(watchedAssets))
+			for _, token := range watchedAssets {
+				watched[token] = true
+			}
+			tradedAssets := tm.getTradedAssets(lastSampleTime)
+			lastSampleTime = time.Now()
+
+			newSamples := make(map[string][]float64) // tokens which are not sampled anymore are dropped
+			for _, token := range tm.getSampledAssets(watchedAssets, heldAssets, tradedAssets) {
+				asset, err := tm.signumClient.GetAsset(tm.logger, token)
+				if err != nil {
+					tm.logger.Errorf("Can't get asset %v: %v", token, err)
+					continue
+				}
+				price := asset.GetPriceNQT()
+				if price == 0 { // no trades yet
+					continue
+				}
+				samples := append(samplesForAveraging[token], price)
+				if uint(len(samples)) > tm.config.SmoothingFactor {
+					samples = samples[1:]
+				}
+				newSamples[token] = samples
+				if watched[token] {
+					tm.checkPriceAlerts(asset)
+				}
+			}
+			samplesForAveraging = newSamples
+			timeToSave = (timeToSave + 1) % tm.config.SaveEveryNSamples
+
+			if timeToSave == 0 {
+				for token, samples := range samplesForAveraging {
+					dbPrice := models.AssetPrice{Asset: token}
+					for _, price := range samples {
+						dbPrice.PriceNQT += price
+					}
+					dbPrice.PriceNQT /= float64(len(samples))
+					tm.db.Save(&dbPrice)
+				}
+				tm.logger.Infof("Saved new prices of %v tokens", len(samplesForAveraging))
+
+				// scan prices and thin out an old ones, only prices of the same token are merged
+				var scannedPrices []*models.AssetPrice
+				tm.db.Order("asset asc, id asc").Limit(tm.config.ScanQuantity).Offset(scanIndex * tm.config.ScanQuantity).Find(&scannedPrices)
+				if len(scannedPrices) == 0 {
+					scanIndex = 0
+				} else {
+					for i := 1; i < len(scannedPrices); i += 2 {
+						price0 := scannedPrices[i-1]
+						price1 := scannedPrices[i]
+						if price0.Asset != price1.Asset {
+							continue
+						}
+						X := time.Since(price0.CreatedAt) / time.Hour / 24
+						delayM := tm.config.DelayFuncK*X + tm.config.DelayFuncB
+						if price1.CreatedAt.Sub(price0.CreatedAt) < delayM {
+							price0.PriceNQT = (price0.PriceNQT + price1.PriceNQT) / 2
+							tm.db.Save(price0)
+							tm.db.Unscoped().Delete(price1)
+						}
+					}
+					scanIndex++
+				}
+			}
+		}
+	}
+}
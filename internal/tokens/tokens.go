@@ -0,0 +1,137 @@
+package tokens
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"github.com/xDWart/signum-explorer-bot/internal/notifier"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type TokenManager struct {
+	db           *gorm.DB
+	logger       *zap.SugaredLogger
+	signumClient *signumapi.SignumApiClient
+	sink         notifier.Sink
+	config       *Config
+}
+
+type Config struct {
+	SamplePeriod      time.Duration
+	SaveEveryNSamples uint
+	SmoothingFactor   uint
+	ScanQuantity      int
+	DelayFuncK        time.Duration // kx + b, x in days
+	DelayFuncB        time.Duration
+	TopHolders        int
+	RecentTrades      int
+	MaxSampledTokens  int           // watched tokens, then tokens held by menu accounts, then the most traded ones
+	HoldingsPeriod    time.Duration // tokens held by menu accounts are updated once per period
+}
+
+func NewTokenManager(logger *zap.SugaredLogger, db *gorm.DB, signumClient *signumapi.SignumApiClient, sink notifier.Sink, wg *sync.WaitGroup, shutdownChannel chan interface{}, config *Config) *TokenManager {
+	tm := TokenManager{
+		db:           db,
+		logger:       logger,
+		signumClient: signumClient,
+		sink:         sink,
+		config:       config,
+	}
+	wg.Add(1)
+	go tm.startListener(wg, shutdownChannel)
+	return &tm
+}
+
+// getWatchedAssets returns tokens watched by anybody, their prices are always sampled and checked for alerts
+func (tm *TokenManager) getWatchedAssets() []string {
+	var assets []string
+	if err := tm.db.Model(&models.TokenWatch{}).Distinct().Pluck("asset", &assets).Error; err != nil {
+		tm.logger.Errorf("Can't get watched tokens: %v", err)
+	}
+	return assets
+}
+
+// getHeldAssets returns tokens held by menu accounts of active users, the most held first
+func (tm *TokenManager) getHeldAssets(shutdownChannel chan interface{}) []string {
+	var accounts []string
+	err := tm.db.Model(&models.DbAccount{}).
+		Joins("join exbot_db_users on exbot_db_users.id = exbot_db_accounts.db_user_id").
+		Where("exbot_db_users.inactive = false").
+		Distinct().Pluck("exbot_db_accounts.account", &accounts).Error
+	if err != nil {
+		tm.logger.Errorf("Can't get menu accounts: %v", err)
+		return nil
+	}
+
+	var holders = make(map[string]int)
+	for _, accountID := range accounts {
+		select {
+		case <-shutdownChannel:
+			return nil
+		default:
+		}
+
+		account, err := tm.signumClient.GetCachedAccount(tm.logger, accountID)
+		if err != nil {
+			continue // not activated or the node is unavailable, the account is checked by the next update
+		}
+		for _, assetBalance := range account.AssetBalances {
+			if assetBalance.BalanceQNT > 0 {
+				holders[assetBalance.Asset]++
+			}
+		}
+	}
+	return sortByCount(holders)
+}
+
+// getTradedAssets returns tokens traded since the time, the biggest volume in SIGNA first
+func (tm *TokenManager) getTradedAssets(since time.Time) []string {
+	trades, err := tm.signumClient.GetAllTrades(tm.logger, common.TimeToChainTime(since))
+	if err != nil {
+		tm.logger.Errorf("Can't get the last trades: %v", err)
+		return nil
+	}
+
+	var volumes = make(map[string]int)
+	for _, trade := range trades.Trades {
+		volumes[trade.Asset] += int(trade.GetAmountNQT())
+	}
+	return sortByCount(volumes)
+}
+
+func sortByCount(counts map[string]int) []string {
+	var assets = make([]string, 0, len(counts))
+	for asset := range counts {
+		assets = append(assets, asset)
+	}
+	sort.SliceStable(assets, func(i, j int) bool {
+		if counts[assets[i]] == counts[assets[j]] {
+			return assets[i] < assets[j]
+		}
+		return counts[assets[i]] > counts[assets[j]]
+	})
+	return assets
+}
+
+// getSampledAssets merges watched, held and traded tokens in this order, limited by MaxSampledTokens
+func (tm *TokenManager) getSampledAssets(watched, held, traded []string) []string {
+	var assets []string
+	var added = make(map[string]bool)
+	for _, list := range [][]string{watched, held, traded} {
+		for _, asset := range list {
+			if len(assets) == tm.config.MaxSampledTokens {
+				return assets
+			}
+			if !added[asset] {
+				added[asset] = true
+				assets = append(assets, asset)
+			}
+		}
+	}
+	return assets
+}
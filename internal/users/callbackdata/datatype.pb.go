@@ -29,6 +29,7 @@ const (
 	KeyboardType_KT_NETWORK_CHART KeyboardType = 3
 	KeyboardType_KT_CALC          KeyboardType = 4
 	KeyboardType_KT_CONVERT       KeyboardType = 5
+	KeyboardType_KT_PRICE_ALERTS  KeyboardType = 6
 )
 
 var KeyboardType_name = map[int32]string{
@@ -38,6 +39,7 @@ var KeyboardType_name = map[int32]string{
 	3: "KT_NETWORK_CHART",
 	4: "KT_CALC",
 	5: "KT_CONVERT",
+	6: "KT_PRICE_ALERTS",
 }
 
 var KeyboardType_value = map[string]int32{
@@ -47,6 +49,7 @@ var KeyboardType_value = map[string]int32{
 	"KT_NETWORK_CHART": 3,
 	"KT_CALC":          4,
 	"KT_CONVERT":       5,
+	"KT_PRICE_ALERTS":  6,
 }
 
 func (x KeyboardType) String() string {
@@ -90,6 +93,16 @@ const (
 	ActionType_AT_CONVERT_USD                    ActionType = 27
 	ActionType_AT_CONVERT_BTC                    ActionType = 28
 	ActionType_AT_AT_PAYMENTS                    ActionType = 29
+	ActionType_AT_RULES                          ActionType = 30
+	ActionType_AT_DELETE_RULE                    ActionType = 31
+	ActionType_AT_BALANCE_ALERTS                 ActionType = 32
+	ActionType_AT_SET_BALANCE_FLOOR              ActionType = 33
+	ActionType_AT_DISABLE_BALANCE_FLOOR          ActionType = 34
+	ActionType_AT_SET_BALANCE_CHANGE             ActionType = 35
+	ActionType_AT_DISABLE_BALANCE_CHANGE         ActionType = 36
+	ActionType_AT_DELETE_PRICE_ALERT             ActionType = 37
+	ActionType_AT_TOKENS                         ActionType = 38
+	ActionType_AT_MARKETPLACE                    ActionType = 39
 )
 
 var ActionType_name = map[int32]string{
@@ -123,6 +136,16 @@ var ActionType_name = map[int32]string{
 	27: "AT_CONVERT_USD",
 	28: "AT_CONVERT_BTC",
 	29: "AT_AT_PAYMENTS",
+	30: "AT_RULES",
+	31: "AT_DELETE_RULE",
+	32: "AT_BALANCE_ALERTS",
+	33: "AT_SET_BALANCE_FLOOR",
+	34: "AT_DISABLE_BALANCE_FLOOR",
+	35: "AT_SET_BALANCE_CHANGE",
+	36: "AT_DISABLE_BALANCE_CHANGE",
+	37: "AT_DELETE_PRICE_ALERT",
+	38: "AT_TOKENS",
+	39: "AT_MARKETPLACE",
 }
 
 var ActionType_value = map[string]int32{
@@ -156,6 +179,16 @@ var ActionType_value = map[string]int32{
 	"AT_CONVERT_USD":                    27,
 	"AT_CONVERT_BTC":                    28,
 	"AT_AT_PAYMENTS":                    29,
+	"AT_RULES":                          30,
+	"AT_DELETE_RULE":                    31,
+	"AT_BALANCE_ALERTS":                 32,
+	"AT_SET_BALANCE_FLOOR":              33,
+	"AT_DISABLE_BALANCE_FLOOR":          34,
+	"AT_SET_BALANCE_CHANGE":             35,
+	"AT_DISABLE_BALANCE_CHANGE":         36,
+	"AT_DELETE_PRICE_ALERT":             37,
+	"AT_TOKENS":                         38,
+	"AT_MARKETPLACE":                    39,
 }
 
 func (x ActionType) String() string {
@@ -171,6 +204,8 @@ type QueryDataType struct {
 	Account              string       `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
 	Keyboard             KeyboardType `protobuf:"varint,3,opt,name=keyboard,proto3,enum=callbackdata.KeyboardType" json:"keyboard,omitempty"`
 	Action               ActionType   `protobuf:"varint,4,opt,name=action,proto3,enum=callbackdata.ActionType" json:"action,omitempty"`
+	ItemId               uint64       `protobuf:"varint,5,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Page                 uint64       `protobuf:"varint,6,opt,name=page,proto3" json:"page,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -229,6 +264,20 @@ func (m *QueryDataType) GetAction() ActionType {
 	return ActionType_AT_NULL
 }
 
+func (m *QueryDataType) GetItemId() uint64 {
+	if m != nil {
+		return m.ItemId
+	}
+	return 0
+}
+
+func (m *QueryDataType) GetPage() uint64 {
+	if m != nil {
+		return m.Page
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("callbackdata.KeyboardType", KeyboardType_name, KeyboardType_value)
 	proto.RegisterEnum("callbackdata.ActionType", ActionType_name, ActionType_value)
@@ -240,41 +289,50 @@ func init() {
 }
 
 var fileDescriptor_18009145167ae7f1 = []byte{
-	// 575 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x54, 0xdb, 0x4e, 0xdb, 0x40,
-	0x10, 0xad, 0xb9, 0x67, 0x08, 0x61, 0x98, 0x72, 0x31, 0x01, 0xda, 0xb4, 0x6a, 0x25, 0xc4, 0x03,
-	0xf4, 0x22, 0xf5, 0x7d, 0xb3, 0x59, 0x88, 0x65, 0xc7, 0x4b, 0xed, 0x09, 0x97, 0xa7, 0x95, 0x49,
-	0xac, 0x0a, 0x41, 0x13, 0x94, 0x98, 0x87, 0xfc, 0x56, 0xff, 0xa1, 0xff, 0x55, 0x39, 0x6c, 0xc0,
-	0x49, 0x79, 0xb1, 0xb4, 0xe7, 0xcc, 0x99, 0x33, 0xa3, 0x39, 0x32, 0x1c, 0xdd, 0xf6, 0xb2, 0x74,
-	0xd0, 0x4b, 0xee, 0x4f, 0x1e, 0x87, 0xe9, 0x60, 0x78, 0xd2, 0x49, 0xee, 0xef, 0x6f, 0x92, 0xce,
-	0x5d, 0x37, 0xc9, 0x92, 0x93, 0xfc, 0x93, 0x8d, 0x1e, 0xd2, 0xe3, 0x87, 0x41, 0x3f, 0xeb, 0x53,
-	0xb9, 0x48, 0x7e, 0xfc, 0xe3, 0xc0, 0xda, 0xcf, 0xc7, 0x74, 0x30, 0x6a, 0x24, 0x59, 0xc2, 0xa3,
-	0x87, 0x94, 0x0e, 0x00, 0x7e, 0xa7, 0xc3, 0x61, 0xf2, 0x2b, 0x35, 0xb7, 0x5d, 0xd7, 0xa9, 0x39,
-	0x87, 0xf3, 0x51, 0xc9, 0x22, 0x5e, 0x97, 0x5c, 0x58, 0x4e, 0x3a, 0x9d, 0xfe, 0x63, 0x2f, 0x73,
-	0xe7, 0x6a, 0xce, 0x61, 0x29, 0x9a, 0x3c, 0xe9, 0x07, 0xac, 0xdc, 0xa5, 0xa3, 0x9b, 0x7e, 0x32,
-	0xe8, 0xba, 0xf3, 0x35, 0xe7, 0xb0, 0xf2, 0xad, 0x7a, 0x5c, 0xf4, 0x3a, 0xf6, 0x2d, 0x9b, 0xdb,
-	0x44, 0xcf, 0xb5, 0xf4, 0x05, 0x96, 0x92, 0x4e, 0x76, 0xdb, 0xef, 0xb9, 0x0b, 0x63, 0x95, 0x3b,
-	0xad, 0x12, 0x63, 0x6e, 0xac, 0xb1, 0x75, 0x47, 0x03, 0x28, 0x17, 0x7b, 0xd1, 0x2a, 0x2c, 0xfb,
-	0x6c, 0xc2, 0x76, 0x10, 0xe0, 0x1b, 0xaa, 0x00, 0xf8, 0x6c, 0x84, 0x94, 0xba, 0x1d, 0x32, 0x3a,
-	0x44, 0x50, 0xf1, 0xd9, 0x9c, 0x47, 0x9e, 0x54, 0x46, 0x36, 0x45, 0xc4, 0x38, 0x47, 0x9b, 0x80,
-	0xb9, 0x40, 0xf1, 0xa5, 0x8e, 0x7c, 0x8b, 0xce, 0xdb, 0x36, 0x52, 0x04, 0x12, 0x17, 0x6c, 0x1b,
-	0xa9, 0xc3, 0x0b, 0x15, 0x31, 0x2e, 0x1e, 0xfd, 0x5d, 0x04, 0x78, 0x19, 0x25, 0xaf, 0x15, 0x45,
-	0x4b, 0xc1, 0x26, 0x52, 0xa7, 0x91, 0x8a, 0x9b, 0xe8, 0xd0, 0x3a, 0xac, 0x0a, 0x36, 0xe7, 0xe2,
-	0xba, 0xa5, 0x42, 0x8e, 0x71, 0x8e, 0x10, 0xca, 0x82, 0x4d, 0xab, 0x1d, 0xb0, 0x67, 0x74, 0x3b,
-	0xf7, 0xda, 0x82, 0x8d, 0x22, 0x62, 0x62, 0xd1, 0x52, 0xb8, 0x40, 0x6b, 0x50, 0x12, 0x6c, 0xea,
-	0x81, 0x96, 0x7e, 0x8c, 0x8b, 0xd6, 0xa5, 0x2e, 0xa4, 0x8f, 0x4b, 0x13, 0x4b, 0x75, 0xc5, 0xb8,
-	0x6c, 0x1f, 0xe7, 0x91, 0xba, 0xc0, 0x15, 0x7a, 0x07, 0x55, 0xc1, 0x46, 0x85, 0xa2, 0x1e, 0x28,
-	0xe3, 0x85, 0x52, 0xb7, 0x94, 0xe1, 0x2b, 0x13, 0x6a, 0xf6, 0x4e, 0xaf, 0xb1, 0x44, 0xef, 0x61,
-	0x4f, 0xb0, 0x69, 0x78, 0xf1, 0xeb, 0x05, 0x40, 0x55, 0xd8, 0x7e, 0x69, 0x30, 0x76, 0x9f, 0x70,
-	0xab, 0xb4, 0x07, 0x3b, 0x05, 0xf1, 0x14, 0x59, 0xa6, 0x03, 0xd8, 0x7d, 0x11, 0xea, 0x36, 0x9f,
-	0xe9, 0x42, 0xdf, 0x35, 0x3b, 0xd8, 0x44, 0x3b, 0xcb, 0x57, 0xc8, 0x85, 0x4d, 0x31, 0x75, 0x1b,
-	0xf3, 0xd5, 0x34, 0xc4, 0x35, 0xae, 0xd3, 0x2e, 0x6c, 0xfd, 0xc7, 0x5c, 0x2a, 0xe5, 0x23, 0xda,
-	0x61, 0xa7, 0xa9, 0x96, 0x0e, 0xb9, 0x89, 0x1b, 0xb4, 0x0d, 0x34, 0xc3, 0x89, 0x20, 0x40, 0xa2,
-	0x7d, 0x70, 0xc5, 0xcc, 0xc1, 0x9f, 0x55, 0x6f, 0xed, 0x18, 0xd3, 0x6c, 0xae, 0xdb, 0xb4, 0x87,
-	0xd3, 0xdc, 0x54, 0x91, 0xe1, 0xab, 0x18, 0xb7, 0xe8, 0x13, 0xd4, 0x0a, 0x1b, 0x5b, 0xe2, 0x69,
-	0x23, 0x4f, 0x0a, 0xf6, 0x74, 0x18, 0xe3, 0x36, 0x7d, 0x86, 0x0f, 0xc5, 0xc5, 0x5f, 0x2f, 0xdb,
-	0xb1, 0x41, 0xc9, 0x13, 0x67, 0xd8, 0xab, 0xa3, 0x6b, 0x93, 0xf4, 0x04, 0xd4, 0x71, 0x37, 0x0f,
-	0xaa, 0x78, 0x4e, 0xa1, 0x89, 0xbd, 0xb3, 0x50, 0x60, 0x35, 0x8f, 0x74, 0x01, 0x6d, 0xc7, 0x0d,
-	0xdc, 0x9b, 0xc1, 0xea, 0x2c, 0x71, 0xdf, 0x62, 0xc5, 0x28, 0x1e, 0xdc, 0x2c, 0x8d, 0xff, 0x02,
-	0xdf, 0xff, 0x05, 0x00, 0x00, 0xff, 0xff, 0xea, 0x4b, 0x98, 0x19, 0x33, 0x04, 0x00, 0x00,
+	// 705 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x54, 0xdb, 0x52, 0xe2, 0x4a,
+	0x14, 0x3d, 0x51, 0x04, 0xd9, 0x22, 0x6e, 0xb7, 0xb7, 0x88, 0x37, 0xf4, 0xe8, 0x39, 0x94, 0x0f,
+	0x7a, 0xce, 0x4c, 0xd5, 0xbc, 0x37, 0xa1, 0x95, 0x54, 0x42, 0xc2, 0x24, 0x8d, 0x97, 0xa7, 0xae,
+	0x08, 0x29, 0x8b, 0x52, 0x81, 0x82, 0xf8, 0xc0, 0x17, 0xcc, 0xdf, 0xcc, 0x2f, 0xcd, 0xaf, 0x4c,
+	0x25, 0x36, 0x10, 0x18, 0x5f, 0x28, 0x7a, 0xad, 0xbd, 0xf6, 0xa5, 0x7b, 0xed, 0xc0, 0x65, 0xb7,
+	0x17, 0x85, 0xc3, 0x5e, 0xf0, 0x7a, 0xfd, 0x3e, 0x0a, 0x87, 0xa3, 0xeb, 0x76, 0xf0, 0xfa, 0xfa,
+	0x14, 0xb4, 0x5f, 0x3a, 0x41, 0x14, 0x5c, 0xc7, 0x3f, 0xd1, 0x78, 0x10, 0x5e, 0x0d, 0x86, 0xfd,
+	0xa8, 0x4f, 0x85, 0x34, 0x79, 0xf6, 0x4b, 0x83, 0xf5, 0xef, 0xef, 0xe1, 0x70, 0x5c, 0x0b, 0xa2,
+	0x40, 0x8c, 0x07, 0x21, 0x1d, 0x01, 0xbc, 0x85, 0xa3, 0x51, 0xf0, 0x1c, 0xca, 0x6e, 0x47, 0xd7,
+	0xca, 0x5a, 0x65, 0xd9, 0xcb, 0x2b, 0xc4, 0xec, 0x90, 0x0e, 0xb9, 0xa0, 0xdd, 0xee, 0xbf, 0xf7,
+	0x22, 0x7d, 0xa9, 0xac, 0x55, 0xf2, 0xde, 0xe4, 0x48, 0xdf, 0x60, 0xf5, 0x25, 0x1c, 0x3f, 0xf5,
+	0x83, 0x61, 0x47, 0x5f, 0x2e, 0x6b, 0x95, 0xe2, 0x97, 0xd2, 0x55, 0xba, 0xd6, 0x95, 0xa5, 0xd8,
+	0xb8, 0x8c, 0x37, 0x8d, 0xa5, 0xff, 0x20, 0x1b, 0xb4, 0xa3, 0x6e, 0xbf, 0xa7, 0x67, 0x12, 0x95,
+	0x3e, 0xaf, 0x62, 0x09, 0x97, 0x68, 0x54, 0x1c, 0xed, 0x41, 0xae, 0x1b, 0x85, 0x6f, 0x71, 0x7f,
+	0x2b, 0x65, 0xad, 0x92, 0xf1, 0xb2, 0xf1, 0xd1, 0xec, 0x10, 0x41, 0x66, 0x10, 0x3c, 0x87, 0x7a,
+	0x36, 0x41, 0x93, 0xff, 0x97, 0x3f, 0x34, 0x28, 0xa4, 0x2b, 0xd3, 0x1a, 0xe4, 0x2c, 0x21, 0x9d,
+	0x96, 0x6d, 0xe3, 0x5f, 0x54, 0x04, 0xb0, 0x84, 0x64, 0x86, 0xe1, 0xb6, 0x1c, 0x81, 0x1a, 0x11,
+	0x14, 0x2d, 0x21, 0x9b, 0x9e, 0x69, 0x70, 0x69, 0xd4, 0x99, 0x27, 0x70, 0x89, 0xb6, 0x01, 0x63,
+	0x01, 0x17, 0xf7, 0xae, 0x67, 0x29, 0x74, 0x59, 0xa5, 0x31, 0x98, 0x6d, 0x60, 0x46, 0xa5, 0x31,
+	0x5c, 0xe7, 0x8e, 0x7b, 0x02, 0x57, 0x68, 0x0b, 0x36, 0xa6, 0x69, 0x98, 0xcd, 0x3d, 0xe1, 0x63,
+	0xf6, 0xf2, 0x67, 0x0e, 0x60, 0x36, 0x4d, 0x9c, 0x80, 0xa5, 0xfb, 0x60, 0x42, 0x7a, 0xfc, 0xc6,
+	0xe3, 0x7e, 0x1d, 0x35, 0xda, 0x80, 0x35, 0x26, 0x64, 0x93, 0x3d, 0x36, 0xb8, 0x23, 0x7c, 0x5c,
+	0x22, 0x84, 0x02, 0x13, 0xb2, 0xd1, 0xb2, 0x85, 0x29, 0xdd, 0x56, 0xdc, 0xc0, 0x0e, 0x6c, 0xa6,
+	0x11, 0xe9, 0xb3, 0x06, 0xc7, 0x0c, 0xad, 0x43, 0x9e, 0x09, 0x59, 0xb5, 0x5d, 0xc3, 0xf2, 0x71,
+	0x45, 0x55, 0xa9, 0x32, 0xc3, 0xc2, 0xec, 0xa4, 0x24, 0x7f, 0x10, 0x98, 0x53, 0x87, 0xa6, 0xc7,
+	0xef, 0x70, 0x95, 0x8e, 0xa1, 0xc4, 0x84, 0xe4, 0x0e, 0xab, 0xda, 0x5c, 0x9a, 0x8e, 0xe1, 0x36,
+	0xb8, 0x14, 0x0f, 0xd2, 0x71, 0x85, 0x79, 0xf3, 0x88, 0x79, 0x3a, 0x81, 0x03, 0x26, 0x64, 0xcd,
+	0xf4, 0x3f, 0x0f, 0x00, 0x2a, 0xc1, 0xee, 0x2c, 0x41, 0x52, 0x7d, 0xc2, 0xad, 0xd1, 0x01, 0xec,
+	0xa5, 0xc4, 0x73, 0x64, 0x81, 0x8e, 0x60, 0x7f, 0x26, 0x74, 0x5b, 0xe2, 0xd6, 0x4d, 0xe5, 0x5d,
+	0x57, 0x8d, 0x4d, 0xb4, 0x8b, 0x7c, 0x91, 0x74, 0xd8, 0x66, 0x73, 0x0f, 0x26, 0xff, 0x97, 0x35,
+	0xf6, 0x88, 0x1b, 0xb4, 0x0f, 0x3b, 0x7f, 0x30, 0xf7, 0x9c, 0x5b, 0x88, 0xaa, 0xd9, 0x79, 0xaa,
+	0xe1, 0x3a, 0xa2, 0x8e, 0x9b, 0xb4, 0x0b, 0xb4, 0xc0, 0x31, 0xdb, 0x46, 0xa2, 0x43, 0xd0, 0xd9,
+	0x82, 0x0b, 0xa6, 0xaa, 0x2d, 0xd5, 0xc6, 0x3c, 0x1b, 0xeb, 0xb6, 0xd5, 0xc3, 0xb9, 0xa2, 0xce,
+	0x3d, 0x29, 0x1e, 0x7c, 0xdc, 0xa1, 0x73, 0x28, 0xa7, 0x26, 0x56, 0xc4, 0xc7, 0x44, 0xa6, 0xc1,
+	0x84, 0xe9, 0x3a, 0x3e, 0xee, 0xd2, 0x05, 0x9c, 0xa6, 0x07, 0xff, 0x3c, 0x6c, 0x4f, 0x19, 0x25,
+	0xb6, 0xa1, 0x14, 0x66, 0x15, 0x75, 0xe5, 0xa4, 0x0f, 0xa0, 0x8a, 0xfb, 0xb1, 0x7b, 0xd9, 0xd4,
+	0x9a, 0xd2, 0x37, 0x6f, 0x1d, 0x86, 0xa5, 0xd8, 0xe7, 0x29, 0xb4, 0xe5, 0xd7, 0xf0, 0x60, 0x01,
+	0xab, 0x0a, 0x03, 0x0f, 0x15, 0x96, 0xb6, 0xe2, 0x11, 0x15, 0x60, 0x35, 0xf6, 0x6a, 0xcb, 0xe6,
+	0x3e, 0x1e, 0xab, 0x88, 0x1a, 0xb7, 0xb9, 0xe0, 0x09, 0x88, 0x27, 0xca, 0x9a, 0x55, 0x66, 0x33,
+	0x67, 0xb6, 0x00, 0x65, 0x75, 0x49, 0x3e, 0x9f, 0x51, 0x37, 0xb6, 0xeb, 0x7a, 0x78, 0xaa, 0x2e,
+	0x77, 0xea, 0x90, 0x39, 0xf6, 0x4c, 0xbd, 0x64, 0x5a, 0x67, 0xd4, 0x99, 0x73, 0xcb, 0xf1, 0x6f,
+	0xe5, 0x9e, 0x45, 0xa1, 0xa2, 0xcf, 0x95, 0x52, 0x35, 0x97, 0x5a, 0x47, 0xbc, 0x50, 0x7b, 0x22,
+	0x5c, 0x8b, 0x3b, 0x3e, 0xfe, 0xa3, 0xc6, 0x68, 0x30, 0xcf, 0xe2, 0xa2, 0x69, 0x33, 0x83, 0xe3,
+	0xbf, 0x4f, 0xd9, 0xe4, 0x8b, 0xf9, 0xf5, 0xf7, 0x00, 0x77, 0xe3, 0xec, 0xb6, 0x5f, 0x05, 0x00,
+	0x00,
 }
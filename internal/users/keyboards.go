@@ -1,8 +1,12 @@
 package users
 
 import (
+	"fmt"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
 	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"github.com/xDWart/signum-explorer-bot/internal/prices"
 	"github.com/xDWart/signum-explorer-bot/internal/users/callbackdata"
 )
 
@@ -90,6 +94,20 @@ func (user *User) GetAccountKeyboard(account string) *tgbotapi.InlineKeyboardMar
 					Action:   callbackdata.ActionType_AT_BLOCKS,
 				}.GetBase64ProtoString()),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				"Tokens", callbackdata.QueryDataType{
+					Account:  account,
+					Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+					Action:   callbackdata.ActionType_AT_TOKENS,
+				}.GetBase64ProtoString()),
+			tgbotapi.NewInlineKeyboardButtonData(
+				"Marketplace", callbackdata.QueryDataType{
+					Account:  account,
+					Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+					Action:   callbackdata.ActionType_AT_MARKETPLACE,
+				}.GetBase64ProtoString()),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData(
 				checkedIcon[userAccount.NotifyIncomeTransactions]+" Notify income TXs",
@@ -122,10 +140,165 @@ func (user *User) GetAccountKeyboard(account string) *tgbotapi.InlineKeyboardMar
 					Action:   actionTypes[OTHER][userAccount.NotifyOtherTXs],
 				}.GetBase64ProtoString()),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				"⚙ Notification rules",
+				callbackdata.QueryDataType{
+					Account:  account,
+					Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+					Action:   callbackdata.ActionType_AT_RULES,
+				}.GetBase64ProtoString()),
+			tgbotapi.NewInlineKeyboardButtonData(
+				"💰 Balance alerts",
+				callbackdata.QueryDataType{
+					Account:  account,
+					Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+					Action:   callbackdata.ActionType_AT_BALANCE_ALERTS,
+				}.GetBase64ProtoString()),
+		),
 	)
 	return &inlineKeyboard
 }
 
+// GetHistoryKeyboard pages the history shown by callbackData, the page is kept in the callback data
+func (user *User) GetHistoryKeyboard(callbackData *callbackdata.QueryDataType, hasNextPage bool) *tgbotapi.InlineKeyboardMarkup {
+	var pageRow []tgbotapi.InlineKeyboardButton
+	if callbackData.GetPage() > 0 {
+		pageRow = append(pageRow, tgbotapi.NewInlineKeyboardButtonData(
+			config.BUTTON_PREV,
+			callbackdata.QueryDataType{
+				Account:  callbackData.Account,
+				Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+				Action:   callbackData.Action,
+				Page:     callbackData.GetPage() - 1,
+			}.GetBase64ProtoString()))
+	}
+	if hasNextPage {
+		pageRow = append(pageRow, tgbotapi.NewInlineKeyboardButtonData(
+			config.BUTTON_NEXT,
+			callbackdata.QueryDataType{
+				Account:  callbackData.Account,
+				Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+				Action:   callbackData.Action,
+				Page:     callbackData.GetPage() + 1,
+			}.GetBase64ProtoString()))
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if len(pageRow) > 0 {
+		rows = append(rows, pageRow)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(
+			config.BUTTON_BACK,
+			callbackdata.QueryDataType{
+				Account:  callbackData.Account,
+				Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+				Action:   callbackdata.ActionType_AT_REFRESH,
+			}.GetBase64ProtoString()),
+	))
+
+	inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &inlineKeyboard
+}
+
+func (user *User) GetBalanceAlertsKeyboard(userAccount *models.DbAccount) *tgbotapi.InlineKeyboardMarkup {
+	var floorRow, changeRow []tgbotapi.InlineKeyboardButton
+	floorRow = append(floorRow, tgbotapi.NewInlineKeyboardButtonData(
+		"Set floor",
+		callbackdata.QueryDataType{
+			Account:  userAccount.Account,
+			Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+			Action:   callbackdata.ActionType_AT_SET_BALANCE_FLOOR,
+		}.GetBase64ProtoString()))
+	if userAccount.BalanceFloorNQT > 0 {
+		floorRow = append(floorRow, tgbotapi.NewInlineKeyboardButtonData(
+			"❌ Disable floor",
+			callbackdata.QueryDataType{
+				Account:  userAccount.Account,
+				Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+				Action:   callbackdata.ActionType_AT_DISABLE_BALANCE_FLOOR,
+			}.GetBase64ProtoString()))
+	}
+	changeRow = append(changeRow, tgbotapi.NewInlineKeyboardButtonData(
+		"Set change %",
+		callbackdata.QueryDataType{
+			Account:  userAccount.Account,
+			Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+			Action:   callbackdata.ActionType_AT_SET_BALANCE_CHANGE,
+		}.GetBase64ProtoString()))
+	if userAccount.BalanceChangePercent > 0 {
+		changeRow = append(changeRow, tgbotapi.NewInlineKeyboardButtonData(
+			"❌ Disable change",
+			callbackdata.QueryDataType{
+				Account:  userAccount.Account,
+				Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+				Action:   callbackdata.ActionType_AT_DISABLE_BALANCE_CHANGE,
+			}.GetBase64ProtoString()))
+	}
+
+	inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(
+		floorRow,
+		changeRow,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				config.BUTTON_BACK,
+				callbackdata.QueryDataType{
+					Account:  userAccount.Account,
+					Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+					Action:   callbackdata.ActionType_AT_REFRESH,
+				}.GetBase64ProtoString()),
+		),
+	)
+	return &inlineKeyboard
+}
+
+func (user *User) GetRulesKeyboard(account string, rules []*models.NotificationRule) *tgbotapi.InlineKeyboardMarkup {
+	var rows = make([][]tgbotapi.InlineKeyboardButton, 0, len(rules)+1)
+	for i, rule := range rules {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("❌ %v. %v", i+1, formatRule(rule)),
+				callbackdata.QueryDataType{
+					Account:  account,
+					Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+					Action:   callbackdata.ActionType_AT_DELETE_RULE,
+					ItemId:   uint64(rule.ID),
+				}.GetBase64ProtoString()),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(
+			config.BUTTON_BACK,
+			callbackdata.QueryDataType{
+				Account:  account,
+				Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
+				Action:   callbackdata.ActionType_AT_REFRESH,
+			}.GetBase64ProtoString()),
+	))
+
+	inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &inlineKeyboard
+}
+
+func (user *User) GetPriceAlertsKeyboard(alerts []*models.PriceAlert) *tgbotapi.InlineKeyboardMarkup {
+	var rows = make([][]tgbotapi.InlineKeyboardButton, 0, len(alerts))
+	for i, alert := range alerts {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("❌ %v. %v", i+1, prices.FormatPriceAlert(alert)),
+				callbackdata.QueryDataType{
+					Keyboard: callbackdata.KeyboardType_KT_PRICE_ALERTS,
+					Action:   callbackdata.ActionType_AT_DELETE_PRICE_ALERT,
+					ItemId:   uint64(alert.ID),
+				}.GetBase64ProtoString()),
+		))
+	}
+
+	inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &inlineKeyboard
+}
+
 func (user *User) GetPriceChartKeyboard() *tgbotapi.InlineKeyboardMarkup {
 	inlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
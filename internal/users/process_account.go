@@ -1,12 +1,16 @@
 package users
 
 import (
+	"errors"
 	"fmt"
+	"html"
+	"strconv"
 	"strings"
 
 	"github.com/xDWart/signum-explorer-bot/internal/common"
 	"github.com/xDWart/signum-explorer-bot/internal/config"
 	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"github.com/xDWart/signum-explorer-bot/internal/rsaddress"
 )
 
 func (user *User) tryFoundAccountInMenu(accountS string) (*models.DbAccount, int) {
@@ -18,19 +22,49 @@ func (user *User) tryFoundAccountInMenu(accountS string) (*models.DbAccount, int
 	return nil, 0
 }
 
-func (user *User) getAccountInfoMessage(accountS string) (*BotMessage, error) {
-	foundAccount, _ := user.tryFoundAccountInMenu(accountS)
+// max typo corrections suggested for a mistyped address
+const addressSuggestionsLimit = 3
 
-	if foundAccount == nil && !config.ValidAccountRS.MatchString(accountS) && !config.ValidAccount.MatchString(accountS) {
-		return nil, fmt.Errorf("🚫 Incorrect account format, please use the <b>S-XXXX-XXXX-XXXX-XXXXX</b> or <b>numeric AccountID</b>")
+// parseAccount returns the numeric ID of the account address or ID without requests to the node,
+// the error is ready to be sent to the user
+func parseAccount(accountS string) (string, error) {
+	id, err := rsaddress.ParseAccount(accountS)
+	if err == nil {
+		return strconv.FormatUint(id, 10), nil
 	}
 
+	var checksumError *rsaddress.ChecksumError
+	switch {
+	case errors.As(err, &checksumError) && len(checksumError.Suggestions) > 0:
+		suggestions := checksumError.Suggestions
+		if len(suggestions) > addressSuggestionsLimit {
+			suggestions = suggestions[:addressSuggestionsLimit]
+		}
+		return "", fmt.Errorf("🚫 The address <b>%v</b> has a typo, did you mean <b>%v</b>?",
+			html.EscapeString(accountS), strings.Join(suggestions, "</b> or <b>"))
+	case errors.As(err, &checksumError):
+		return "", fmt.Errorf("🚫 The address <b>%v</b> has a typo, please check it", html.EscapeString(accountS))
+	case errors.Is(err, rsaddress.ErrPublicKey):
+		return "", fmt.Errorf("🚫 The public key in the address <b>%v</b> doesn't match the account", html.EscapeString(accountS))
+	default:
+		return "", fmt.Errorf("🚫 Incorrect account format, please use the <b>S-XXXX-XXXX-XXXX-XXXXX</b> or <b>numeric AccountID</b>")
+	}
+}
+
+func (user *User) getAccountInfoMessage(accountS string) (*BotMessage, error) {
+	foundAccount, _ := user.tryFoundAccountInMenu(accountS)
+
 	var alias string
 	if foundAccount != nil {
 		accountS = foundAccount.Account
 		if foundAccount.Alias != "" {
 			alias = fmt.Sprintf(" alias: <i>%v</i>", foundAccount.Alias)
 		}
+	} else {
+		var err error
+		if accountS, err = parseAccount(accountS); err != nil {
+			return nil, err
+		}
 	}
 
 	account, err := user.signumClient.GetCachedAccount(user.logger, accountS)
@@ -60,15 +94,17 @@ func (user *User) getAccountInfoMessage(accountS string) (*BotMessage, error) {
 		"\nAccount ID: <code>%v</code>"+
 		"%v"+
 		"%v"+
+		"%v"+
 		"\n\nAvailable: %v SIGNA <i>($%v | %v BTC)</i>"+
 		"\nCommitment: %v SIGNA <i>($%v | %v BTC)</i>"+
 		"\n<b>Total: %v SIGNA</b> <i>($%v | %v BTC)</i>"+
+		"%v%v"+
 		"\n\nFor the full details visit the <a href='https://explorer.signum.network/?action=account&account=%v'>original Signum Explorer</a>",
-		account.AccountRS, alias, account.Account, accountName, rewardRecipientName,
+		account.AccountRS, alias, account.Account, accountName, rewardRecipientName, user.getAccountLeasingText(account),
 		common.FormatNQT(account.AvailableBalanceNQT), common.FormatNumber(float64(account.AvailableBalanceNQT)/1e8*signaPrice, 2), common.FormatNumber(float64(account.AvailableBalanceNQT)/1e8*signaPrice/btcPrice, 4),
 		common.FormatNQT(account.CommittedBalanceNQT), common.FormatNumber(float64(account.CommittedBalanceNQT)/1e8*signaPrice, 2), common.FormatNumber(float64(account.CommittedBalanceNQT)/1e8*signaPrice/btcPrice, 4),
 		common.FormatNQT(account.TotalBalanceNQT), common.FormatNumber(float64(account.TotalBalanceNQT)/1e8*signaPrice, 2), common.FormatNumber(float64(account.TotalBalanceNQT)/1e8*signaPrice/btcPrice, 4),
-		account.Account)
+		user.getAccountTokensText(account), user.getAccountAliasesText(account), account.Account)
 
 	inlineKeyboard := user.GetAccountKeyboard(account.Account)
 
@@ -98,16 +134,6 @@ func (user *User) ProcessAdd(message string) string {
 
 	userAccount, msg := user.addAccount(accountS, alias)
 	if userAccount != nil {
-		lastAccountTransaction := user.signumClient.GetLastCachedAccountPaymentTransaction(user.logger, userAccount.Account)
-		if lastAccountTransaction != nil {
-			userAccount.LastTransactionID = lastAccountTransaction.TransactionID
-			userAccount.LastTransactionH = lastAccountTransaction.Height
-		}
-		lastAccountATPaymentTransaction := user.signumClient.GetLastCachedAccountATPaymentTransaction(user.logger, userAccount.Account)
-		if lastAccountATPaymentTransaction != nil {
-			userAccount.LastATPaymentTX = lastAccountATPaymentTransaction.TransactionID
-			userAccount.LastATPaymentH = lastAccountATPaymentTransaction.Height
-		}
 		userAccount.NotifyIncomeTransactions = true
 		user.db.Save(userAccount)
 	}
@@ -115,8 +141,9 @@ func (user *User) ProcessAdd(message string) string {
 }
 
 func (user *User) addAccount(newAccount, alias string) (*models.DbAccount, string) {
-	if !config.ValidAccountRS.MatchString(newAccount) && !config.ValidAccount.MatchString(newAccount) {
-		return nil, "🚫 Incorrect account format, please use the <b>S-XXXX-XXXX-XXXX-XXXXX</b> or <b>numeric AccountID</b>"
+	newAccount, err := parseAccount(newAccount)
+	if err != nil {
+		return nil, err.Error()
 	}
 	userAccount := user.GetDbAccount(newAccount)
 	if userAccount != nil {
@@ -176,6 +203,10 @@ func (user *User) delAccount(alias string) string {
 		return "🚫 This account not found in the menu"
 	}
 
+	user.db.Unscoped().Where("db_account_id = ?", foundAccount.ID).Delete(&models.NotificationRule{})
+	user.db.Unscoped().Where("db_account_id = ?", foundAccount.ID).Delete(&models.AccountBalance{})
+	user.db.Unscoped().Where("db_account_id = ?", foundAccount.ID).Delete(&models.DigestStat{})
+	user.db.Unscoped().Where("db_account_id = ?", foundAccount.ID).Delete(&models.AccountWatchdog{})
 	user.db.Unscoped().Delete(foundAccount)
 	user.Accounts = append(user.Accounts[:foundAccountIndex], user.Accounts[foundAccountIndex+1:]...)
 	user.ResetState()
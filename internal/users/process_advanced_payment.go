@@ -0,0 +1,135 @@
+package users
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+)
+
+const subscriptionsUsageText = `🔄 Send <b>` + config.COMMAND_SUBSCRIPTIONS + ` ACCOUNT</b> to list active incoming and outgoing subscriptions of the account with their next renewals`
+
+const escrowsUsageText = `🤝 Send <b>` + config.COMMAND_ESCROWS + ` ACCOUNT</b> to list unresolved escrows of the account with their deadlines and signers`
+
+// getAdvancedPaymentAccount returns the account from the command argument, it can be an alias from the menu too
+func (user *User) getAdvancedPaymentAccount(accountS string) (*signumapi.Account, string) {
+	if userAccount, _ := user.tryFoundAccountInMenu(accountS); userAccount != nil {
+		accountS = userAccount.Account
+	} else if parsedAccount, err := parseAccount(accountS); err != nil {
+		return nil, err.Error()
+	} else {
+		accountS = parsedAccount
+	}
+	account, err := user.signumClient.GetCachedAccount(user.logger, accountS)
+	if err != nil {
+		return nil, fmt.Sprintf("🚫 Error: %v", err)
+	}
+	return account, ""
+}
+
+func (user *User) ProcessSubscriptions(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if len(splittedMessage) != 2 || splittedMessage[0] != config.COMMAND_SUBSCRIPTIONS {
+		return &BotMessage{MainText: subscriptionsUsageText}
+	}
+
+	account, errText := user.getAdvancedPaymentAccount(splittedMessage[1])
+	if account == nil {
+		return &BotMessage{MainText: errText}
+	}
+
+	outgoing, err := user.signumClient.GetAccountSubscriptions(user.logger, account.Account)
+	if err != nil {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 Error: %v", err)}
+	}
+	incoming, err := user.signumClient.GetSubscriptionsToAccount(user.logger, account.Account)
+	if err != nil {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 Error: %v", err)}
+	}
+
+	var text = fmt.Sprintf("🔄 <b>%v</b> subscriptions:", account.AccountRS)
+	if len(outgoing.Subscriptions) == 0 && len(incoming.Subscriptions) == 0 {
+		return &BotMessage{MainText: text + "\n\nThe account doesn't have any active subscriptions"}
+	}
+	if len(outgoing.Subscriptions) > 0 {
+		text += "\n\n📤 <b>Outgoing:</b>"
+		var totalNQT uint64
+		for _, subscription := range sortSubscriptions(outgoing.Subscriptions) {
+			text += "\n" + user.formatSubscription(&subscription, subscription.Recipient, subscription.RecipientRS, "-")
+			totalNQT += subscription.AmountNQT
+		}
+		text += fmt.Sprintf("\n<i>Total per renewal:</i> -%v SIGNA", common.FormatNQT(totalNQT))
+	}
+	if len(incoming.Subscriptions) > 0 {
+		text += "\n\n📥 <b>Incoming:</b>"
+		var totalNQT uint64
+		for _, subscription := range sortSubscriptions(incoming.Subscriptions) {
+			text += "\n" + user.formatSubscription(&subscription, subscription.Sender, subscription.SenderRS, "+")
+			totalNQT += subscription.AmountNQT
+		}
+		text += fmt.Sprintf("\n<i>Total per renewal:</i> +%v SIGNA", common.FormatNQT(totalNQT))
+	}
+	return &BotMessage{MainText: text}
+}
+
+// sortSubscriptions orders subscriptions by the next renewal
+func sortSubscriptions(subscriptions []signumapi.Subscription) []signumapi.Subscription {
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].TimeNext < subscriptions[j].TimeNext
+	})
+	return subscriptions
+}
+
+func (user *User) formatSubscription(subscription *signumapi.Subscription, counterparty, counterpartyRS, sign string) string {
+	if name := user.signumClient.GetCachedAccountName(user.logger, counterparty); name != "" {
+		counterpartyRS += " (" + html.EscapeString(name) + ")"
+	}
+	return fmt.Sprintf("<b>%v%v SIGNA</b> every %v %v"+
+		"\n<i>Next renewal:</i> %v",
+		sign, common.FormatNQT(subscription.AmountNQT), common.FormatPeriod(time.Duration(subscription.Frequency)*time.Second), counterpartyRS,
+		common.FormatChainTimeToStringDatetimeUTC(subscription.TimeNext))
+}
+
+func (user *User) ProcessEscrows(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if len(splittedMessage) != 2 || splittedMessage[0] != config.COMMAND_ESCROWS {
+		return &BotMessage{MainText: escrowsUsageText}
+	}
+
+	account, errText := user.getAdvancedPaymentAccount(splittedMessage[1])
+	if account == nil {
+		return &BotMessage{MainText: errText}
+	}
+
+	escrows, err := user.signumClient.GetAccountEscrows(user.logger, account.Account)
+	if err != nil {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 Error: %v", err)}
+	}
+
+	var text = fmt.Sprintf("🤝 <b>%v</b> escrows:", account.AccountRS)
+	if len(escrows.Escrows) == 0 {
+		return &BotMessage{MainText: text + "\n\nThe account doesn't take part in any unresolved escrows"}
+	}
+	sort.SliceStable(escrows.Escrows, func(i, j int) bool {
+		return escrows.Escrows[i].Deadline < escrows.Escrows[j].Deadline
+	})
+	for _, escrow := range escrows.Escrows {
+		text += fmt.Sprintf("\n\n<b>%v SIGNA</b> from %v to %v"+
+			"\n<i>Escrow:</i> %v"+
+			"\n<i>Deadline:</i> %v, then %v"+
+			"\n<i>Required signers:</i> %v of %v",
+			common.FormatNQT(escrow.AmountNQT), escrow.SenderRS, escrow.RecipientRS,
+			escrow.ID,
+			common.FormatChainTimeToStringDatetimeUTC(escrow.Deadline), escrow.DeadlineAction,
+			escrow.RequiredSigners, len(escrow.Signers))
+		for _, signer := range escrow.Signers {
+			text += fmt.Sprintf("\n    %v: %v", signer.IDRS, signer.Decision)
+		}
+	}
+	return &BotMessage{MainText: text}
+}
@@ -0,0 +1,148 @@
+package users
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"github.com/xDWart/signum-explorer-bot/internal/prices"
+)
+
+const alertsUsageText = `🔔 <b>Price alerts</b> notify you about SIGNA price moves, add <b>repeat</b> to keep the alert after it has been triggered:
+<b>` + config.COMMAND_ALERT + ` price above PRICE [usd|btc] [repeat]</b> - notify when the price rises above PRICE
+<b>` + config.COMMAND_ALERT + ` price below PRICE [usd|btc] [repeat]</b> - notify when the price falls below PRICE
+<b>` + config.COMMAND_ALERT + ` change PERCENT% PERIOD [usd|btc] [repeat]</b> - notify when the price changes by PERCENT within PERIOD (e.g. 30m, 4h, 24h, 7d)
+Send <b>` + config.COMMAND_ALERTS + `</b> to show and delete your alerts.`
+
+func (user *User) ProcessAlert(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if splittedMessage[0] == config.COMMAND_ALERTS {
+		return user.getPriceAlertsMessage()
+	}
+	if splittedMessage[0] != config.COMMAND_ALERT || len(splittedMessage) < 3 {
+		return &BotMessage{MainText: alertsUsageText}
+	}
+
+	var alert = models.PriceAlert{
+		DbUserID: user.ID,
+		Currency: "USD",
+	}
+	var options []string
+
+	switch strings.ToLower(splittedMessage[1]) {
+	case "price":
+		if len(splittedMessage) < 4 {
+			return &BotMessage{MainText: alertsUsageText}
+		}
+		alert.Kind = models.PriceAlertKind(strings.ToLower(splittedMessage[2]))
+		if alert.Kind != models.PRICE_ALERT_ABOVE && alert.Kind != models.PRICE_ALERT_BELOW {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect command format, please send <b>%v price above|below PRICE</b>", config.COMMAND_ALERT)}
+		}
+		price, err := common.ParseNumber(splittedMessage[3])
+		if err != nil {
+			return &BotMessage{MainText: err.Error()}
+		}
+		if price <= 0 {
+			return &BotMessage{MainText: "🚫 PRICE should be greater than 0"}
+		}
+		alert.Value = price
+		options = splittedMessage[4:]
+	case string(models.PRICE_ALERT_CHANGE):
+		if len(splittedMessage) < 4 {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect command format, please send <b>%v change PERCENT%% PERIOD</b>", config.COMMAND_ALERT)}
+		}
+		alert.Kind = models.PRICE_ALERT_CHANGE
+		percent, err := common.ParseNumber(strings.TrimSuffix(splittedMessage[2], "%"))
+		if err != nil {
+			return &BotMessage{MainText: err.Error()}
+		}
+		if percent <= 0 {
+			return &BotMessage{MainText: "🚫 PERCENT should be greater than 0"}
+		}
+		alert.Value = percent
+		alert.Period, err = parsePeriod(splittedMessage[3])
+		if err != nil {
+			return &BotMessage{MainText: err.Error()}
+		}
+		options = splittedMessage[4:]
+	default:
+		return &BotMessage{MainText: alertsUsageText}
+	}
+
+	for _, option := range options {
+		switch strings.ToLower(option) {
+		case "usd":
+			alert.Currency = "USD"
+		case "btc":
+			alert.Currency = "BTC"
+		case "repeat":
+			alert.Recurring = true
+		default:
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Unknown option <b>%v</b>, please use usd, btc or repeat", html.EscapeString(option))}
+		}
+	}
+
+	if err := user.db.Save(&alert).Error; err != nil {
+		user.logger.Errorf("Error saving price alert for user %v: %v", user.ID, err)
+		return &BotMessage{MainText: "🚫 Can't save the alert, please try again later"}
+	}
+
+	answer := user.getPriceAlertsMessage()
+	answer.MainText = fmt.Sprintf("✅ New alert has been added: %v", prices.FormatPriceAlert(&alert))
+	return answer
+}
+
+// parsePeriod parses durations like 30m, 4h or 7d
+func parsePeriod(period string) (time.Duration, error) {
+	var incorrectPeriod = fmt.Errorf("🚫 Incorrect PERIOD <b>%v</b>, please use minutes, hours or days, e.g. 30m, 4h, 24h, 7d", html.EscapeString(period))
+	if len(period) < 2 {
+		return 0, incorrectPeriod
+	}
+	number, err := strconv.ParseUint(period[:len(period)-1], 10, 16)
+	if err != nil || number == 0 {
+		return 0, incorrectPeriod
+	}
+	switch strings.ToLower(period[len(period)-1:]) {
+	case "m":
+		return time.Duration(number) * time.Minute, nil
+	case "h":
+		return time.Duration(number) * time.Hour, nil
+	case "d":
+		return time.Duration(number) * 24 * time.Hour, nil
+	}
+	return 0, incorrectPeriod
+}
+
+func (user *User) getPriceAlerts() []*models.PriceAlert {
+	var alerts []*models.PriceAlert
+	user.db.Where("db_user_id = ?", user.ID).Order("id").Find(&alerts)
+	return alerts
+}
+
+func (user *User) getPriceAlertsMessage() *BotMessage {
+	alerts := user.getPriceAlerts()
+
+	var inlineText = "🔔 <b>Your price alerts:</b>\n\n"
+	if len(alerts) == 0 {
+		inlineText += "<i>There are no alerts</i>\n"
+	}
+	for i, alert := range alerts {
+		inlineText += fmt.Sprintf("%v. %v\n", i+1, prices.FormatPriceAlert(alert))
+	}
+	inlineText += fmt.Sprintf("\nSend <b>%v</b> to see how to add a new alert, press an alert button to delete it.", config.COMMAND_ALERT)
+
+	var answer = &BotMessage{InlineText: inlineText}
+	if len(alerts) > 0 {
+		answer.InlineKeyboard = user.GetPriceAlertsKeyboard(alerts)
+	}
+	return answer
+}
+
+func (user *User) deletePriceAlert(alertID uint64) {
+	user.db.Unscoped().Where("id = ? AND db_user_id = ?", alertID, user.ID).Delete(&models.PriceAlert{})
+}
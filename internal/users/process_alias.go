@@ -0,0 +1,171 @@
+package users
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+)
+
+const aliasUsageText = `🏷 Send <b>` + config.COMMAND_ALIAS + ` NAME</b> to resolve an alias to its account or URI and to get the owner, sale price and history`
+
+const (
+	cardAliasesLimit     = 5
+	aliasHistoryOwners   = 5  // previous owners followed back
+	aliasHistoryLimit    = 10 // history entries
+	aliasOwnerTxsPerScan = 100
+)
+
+func (user *User) ProcessAlias(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if len(splittedMessage) != 2 || splittedMessage[0] != config.COMMAND_ALIAS {
+		return &BotMessage{MainText: aliasUsageText}
+	}
+
+	alias, err := user.signumClient.GetAlias(user.logger, splittedMessage[1])
+	if err != nil {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 Alias <b>%v</b> not found", html.EscapeString(splittedMessage[1]))}
+	}
+
+	var text = fmt.Sprintf("🏷 <b>%v</b>", html.EscapeString(alias.AliasName))
+	if uriAccount := alias.GetURIAccount(); uriAccount != "" {
+		text += "\n<i>Account:</i> " + html.EscapeString(uriAccount)
+	} else if alias.AliasURI != "" {
+		text += "\n<i>URI:</i> " + html.EscapeString(alias.AliasURI)
+	}
+	text += "\n<i>Owner:</i> " + alias.AccountRS
+	if ownerName := user.signumClient.GetCachedAccountName(user.logger, alias.Account); ownerName != "" {
+		text += " (" + html.EscapeString(ownerName) + ")"
+	}
+	text += "\n<i>Updated:</i> " + common.FormatChainTimeToStringDatetimeUTC(alias.Timestamp)
+	if alias.PriceNQT > 0 {
+		text += fmt.Sprintf("\n<i>For sale:</i> %v SIGNA", common.FormatNQT(alias.PriceNQT))
+		if alias.Buyer != "" {
+			text += " to " + alias.BuyerRS
+		}
+	}
+
+	history := user.getAliasHistory(alias)
+	if len(history) > 0 {
+		text += "\n\n📜 <b>History:</b>"
+	}
+	for _, transaction := range history {
+		text += "\n" + common.FormatChainTimeToStringDatetimeUTC(transaction.Timestamp) + " " + formatAliasTransaction(&transaction)
+	}
+	return &BotMessage{MainText: text}
+}
+
+func formatAliasTransaction(transaction *signumapi.Transaction) string {
+	switch transaction.Subtype {
+	case signumapi.TST_ALIAS_ASSIGNMENT:
+		return fmt.Sprintf("%v set it to %v", transaction.SenderRS, html.EscapeString(transaction.Attachment.URI))
+	case signumapi.TST_ALIAS_SELL:
+		priceNQT := transaction.GetPriceNQT()
+		switch {
+		case priceNQT == 0 && (transaction.Recipient == "" || transaction.Recipient == transaction.Sender):
+			return fmt.Sprintf("%v cancelled the sale", transaction.SenderRS)
+		case priceNQT == 0:
+			return fmt.Sprintf("%v transferred it to %v", transaction.SenderRS, transaction.RecipientRS)
+		case transaction.Recipient != "":
+			return fmt.Sprintf("%v offered it to %v for %v SIGNA", transaction.SenderRS, transaction.RecipientRS, common.FormatNQT(priceNQT))
+		default:
+			return fmt.Sprintf("%v listed it for %v SIGNA", transaction.SenderRS, common.FormatNQT(priceNQT))
+		}
+	default:
+		return fmt.Sprintf("%v bought it from %v for %v SIGNA", transaction.SenderRS, transaction.RecipientRS, common.FormatNQT(transaction.GetAmountNQT()))
+	}
+}
+
+// getMessagingTransactions returns up to aliasOwnerTxsPerScan latest messaging transactions of the account
+func (user *User) getMessagingTransactions(account string) ([]signumapi.Transaction, error) {
+	var transactions []signumapi.Transaction
+	for page := uint64(0); len(transactions) < aliasOwnerTxsPerScan; page++ {
+		accountTransactions, err := user.signumClient.GetAccountMessagingTransactions(user.logger, account, page)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, accountTransactions.Transactions...)
+		if !accountTransactions.HasNextPage {
+			break
+		}
+	}
+	return transactions, nil
+}
+
+// getAliasHistory collects alias transactions of the owner and follows previous owners back through transfers and purchases,
+// there is no API to request the history of an alias
+func (user *User) getAliasHistory(alias *signumapi.Alias) []signumapi.Transaction {
+	var history []signumapi.Transaction
+	var seenTransactions = make(map[string]bool)
+	var owners = []string{alias.Account}
+	var seenOwners = map[string]bool{alias.Account: true}
+
+	for i := 0; i < len(owners) && i < aliasHistoryOwners; i++ {
+		transactions, err := user.getMessagingTransactions(owners[i])
+		if err != nil {
+			user.logger.Errorf("Can't get messaging transactions of %v: %v", owners[i], err)
+			continue
+		}
+
+		for _, transaction := range transactions {
+			if !strings.EqualFold(transaction.Attachment.Alias, alias.AliasName) || seenTransactions[transaction.TransactionID] {
+				continue
+			}
+			var previousOwner string
+			switch transaction.Subtype {
+			case signumapi.TST_ALIAS_ASSIGNMENT:
+			case signumapi.TST_ALIAS_SELL:
+				if transaction.GetPriceNQT() == 0 && transaction.Recipient == owners[i] {
+					previousOwner = transaction.Sender
+				}
+			case signumapi.TST_ALIAS_BUY:
+				if transaction.Sender == owners[i] {
+					previousOwner = transaction.Recipient
+				}
+			default:
+				continue
+			}
+
+			seenTransactions[transaction.TransactionID] = true
+			history = append(history, transaction)
+			if previousOwner != "" && !seenOwners[previousOwner] {
+				seenOwners[previousOwner] = true
+				owners = append(owners, previousOwner)
+			}
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].Timestamp > history[j].Timestamp
+	})
+	if len(history) > aliasHistoryLimit {
+		history = history[:aliasHistoryLimit]
+	}
+	return history
+}
+
+// getAccountAliasesText returns the short list of aliases for the account card
+func (user *User) getAccountAliasesText(account *signumapi.Account) string {
+	aliases, err := user.signumClient.GetAccountAliases(user.logger, account.Account, cardAliasesLimit+1)
+	if err != nil {
+		user.logger.Errorf("Can't get aliases of %v: %v", account.Account, err)
+		return ""
+	}
+	if len(aliases.Aliases) == 0 {
+		return ""
+	}
+
+	var names []string
+	for i, alias := range aliases.Aliases {
+		if i == cardAliasesLimit {
+			names = append(names, "<i>and more</i>")
+			break
+		}
+		names = append(names, html.EscapeString(alias.AliasName))
+	}
+	return "\n\n🏷 Aliases: " + strings.Join(names, ", ")
+}
@@ -0,0 +1,100 @@
+package users
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/contracts"
+)
+
+const atUsageText = `🤖 Send <b>` + config.COMMAND_AT + ` AT_ID</b> (S-XXXX-XXXX-XXXX-XXXXX or numeric ID) to explore a smart contract: state, balance, decoded data and recent transactions`
+
+func (user *User) ProcessAT(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if len(splittedMessage) != 2 || splittedMessage[0] != config.COMMAND_AT {
+		return &BotMessage{MainText: atUsageText}
+	}
+
+	atS, err := parseAccount(splittedMessage[1])
+	if err != nil {
+		return &BotMessage{MainText: err.Error()}
+	}
+
+	at, err := user.signumClient.GetATDetails(user.logger, atS)
+	if err != nil {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 AT <b>%v</b> not found, it's probably an ordinary account", splittedMessage[1])}
+	}
+
+	return &BotMessage{MainText: user.getATText(at)}
+}
+
+func getATState(at *signumapi.ATDetails) string {
+	switch {
+	case at.Dead:
+		return "💀 dead"
+	case at.Finished:
+		return "🏁 finished"
+	case at.Frozen:
+		return "🧊 frozen, the balance is below the minimum activation amount"
+	case at.Running:
+		return "▶ running"
+	case at.Stopped:
+		return "⏸ waiting for a transaction"
+	default:
+		return "⏸ waiting for the next block"
+	}
+}
+
+func (user *User) getATText(at *signumapi.ATDetails) string {
+	var text = fmt.Sprintf("🤖 <b>%v</b> smart contract"+
+		"\n<i>AT:</i> %v (<code>%v</code>)", html.EscapeString(at.Name), at.AtRS, at.AT)
+	if at.Description != "" {
+		description := []rune(at.Description)
+		if len(description) > 300 {
+			description = append(description[:300], '…')
+		}
+		text += "\n<i>Description:</i> " + html.EscapeString(string(description))
+	}
+	text += "\n<i>Creator:</i> " + at.CreatorRS
+	if creatorName := user.signumClient.GetCachedAccountName(user.logger, at.Creator); creatorName != "" {
+		text += " (" + html.EscapeString(creatorName) + ")"
+	}
+	text += fmt.Sprintf("\n<i>State:</i> %v"+
+		"\n<i>Balance:</i> %v SIGNA"+
+		"\n<i>Minimum activation:</i> %v SIGNA"+
+		"\n<i>Created at block:</i> #%v"+
+		"\n<i>Version:</i> %v  <i>Code hash:</i> <code>%v</code>",
+		getATState(at), common.FormatNQT(at.BalanceNQT), common.FormatNQT(at.MinActivationNQT),
+		at.CreationBlock, at.AtVersion, at.MachineCodeHashId)
+
+	decoder := contracts.GetDecoder(at)
+	if fields := decoder.Decode(at); len(fields) > 0 {
+		text += fmt.Sprintf("\n\n🧩 <b>%v</b> data:", html.EscapeString(decoder.TypeName()))
+		for _, field := range fields {
+			text += fmt.Sprintf("\n<i>%v:</i> %v", html.EscapeString(field.Name), html.EscapeString(field.Value))
+		}
+	}
+
+	transactions, err := user.signumClient.GetAccountTransactions(user.logger, at.AT)
+	if err != nil {
+		user.logger.Errorf("Can't get transactions of AT %v: %v", at.AT, err)
+		return text
+	}
+	if len(transactions.Transactions) > 0 {
+		text += "\n\n🔁 <b>Recent transactions:</b>"
+	}
+	for _, transaction := range transactions.Transactions {
+		if transaction.Sender == at.AT {
+			text += fmt.Sprintf("\n%v ➡ -%v SIGNA to %v",
+				common.FormatChainTimeToStringDatetimeUTC(transaction.Timestamp), common.FormatNQT(transaction.GetAmountNQT()), transaction.RecipientRS)
+		} else {
+			text += fmt.Sprintf("\n%v ⬅ +%v SIGNA from %v",
+				common.FormatChainTimeToStringDatetimeUTC(transaction.Timestamp), common.FormatNQT(transaction.GetAmountNQT()), transaction.SenderRS)
+		}
+	}
+	return text
+}
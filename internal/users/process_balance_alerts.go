@@ -0,0 +1,59 @@
+package users
+
+import (
+	"fmt"
+
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+func (user *User) getBalanceAlertsMessage(userAccount *models.DbAccount) *BotMessage {
+	var inlineText = fmt.Sprintf("💰 <b>%v</b> balance alerts:\n\n", userAccount.AccountRS)
+	if userAccount.BalanceFloorNQT > 0 {
+		inlineText += fmt.Sprintf("<i>Floor:</i> alert when the available balance drops below <b>%v SIGNA</b>\n",
+			common.FormatNQT(userAccount.BalanceFloorNQT))
+	} else {
+		inlineText += "<i>Floor:</i> disabled\n"
+	}
+	if userAccount.BalanceChangePercent > 0 {
+		inlineText += fmt.Sprintf("<i>Change:</i> alert when the available balance changes by more than <b>%v%%</b>\n",
+			common.FormatNumber(userAccount.BalanceChangePercent, 2))
+	} else {
+		inlineText += "<i>Change:</i> disabled\n"
+	}
+
+	var accountBalance models.AccountBalance
+	user.db.Where("db_account_id = ?", userAccount.ID).Limit(1).Find(&accountBalance)
+	if accountBalance.ID != 0 {
+		inlineText += fmt.Sprintf("\n<i>Last observed balance:</i> %v SIGNA (%v UTC)\n",
+			common.FormatNQT(accountBalance.AvailableBalanceNQT), accountBalance.UpdatedAt.UTC().Format("2006-01-02 15:04"))
+	}
+	inlineText += "\nAlerts are sent no matter which transaction has changed the balance."
+
+	return &BotMessage{
+		InlineText:     inlineText,
+		InlineKeyboard: user.GetBalanceAlertsKeyboard(userAccount),
+	}
+}
+
+func (user *User) setBalanceFloor(userAccount *models.DbAccount, amount float64) string {
+	userAccount.BalanceFloorNQT = uint64(amount * 1e8)
+	user.db.Save(userAccount)
+
+	if userAccount.BalanceFloorNQT == 0 {
+		return fmt.Sprintf("💰 Disabled the balance floor alert for <b>%v</b>", userAccount.AccountRS)
+	}
+	return fmt.Sprintf("✅ You will be alerted when the available balance of <b>%v</b> drops below %v SIGNA",
+		userAccount.AccountRS, common.FormatNQT(userAccount.BalanceFloorNQT))
+}
+
+func (user *User) setBalanceChange(userAccount *models.DbAccount, percent float64) string {
+	userAccount.BalanceChangePercent = percent
+	user.db.Save(userAccount)
+
+	if userAccount.BalanceChangePercent == 0 {
+		return fmt.Sprintf("💰 Disabled the balance change alert for <b>%v</b>", userAccount.AccountRS)
+	}
+	return fmt.Sprintf("✅ You will be alerted when the available balance of <b>%v</b> changes by more than %v%%",
+		userAccount.AccountRS, common.FormatNumber(userAccount.BalanceChangePercent, 2))
+}
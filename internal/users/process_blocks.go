@@ -0,0 +1,62 @@
+package users
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+	"github.com/xDWart/signum-explorer-bot/internal/networkinfo"
+)
+
+const blocksUsageText = `💽 <b>Forging statistic</b> of accounts from your menu with enabled block notifications:
+<b>` + config.COMMAND_BLOCKS + ` stats [ACCOUNT]</b> - forged blocks and rewards for 24h, 7d and 30d with the chart
+Expected blocks and luck are calculated for the plot saved by <b>` + config.COMMAND_MINING + ` plot TiB COMMITMENT</b>`
+
+func (user *User) ProcessBlocks(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if len(splittedMessage) < 2 || len(splittedMessage) > 3 || splittedMessage[0] != config.COMMAND_BLOCKS ||
+		strings.ToLower(splittedMessage[1]) != "stats" {
+		return &BotMessage{MainText: blocksUsageText}
+	}
+
+	var accounts []*models.DbAccount
+	if len(splittedMessage) == 3 {
+		userAccount, _ := user.tryFoundAccountInMenu(splittedMessage[2])
+		if userAccount == nil {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 This account not found in the menu, please add it via <b>%v ACCOUNT</b> at first", config.COMMAND_ADD)}
+		}
+		accounts = append(accounts, userAccount)
+	} else {
+		for _, userAccount := range user.Accounts {
+			if userAccount.NotifyNewBlocks {
+				accounts = append(accounts, userAccount)
+			}
+		}
+		if len(accounts) == 0 {
+			return &BotMessage{MainText: "🚫 There are no accounts with enabled block notifications in your menu\n\n" + blocksUsageText}
+		}
+	}
+
+	plot := user.getMiningAlert()
+	var text = "💽 <b>Forging statistic:</b>"
+	var forgingAccounts = make([]networkinfo.ForgingAccount, 0, len(accounts))
+	for _, account := range accounts {
+		var name = account.AccountRS
+		if account.Alias != "" {
+			name = account.Alias
+		}
+		forgingAccounts = append(forgingAccounts, networkinfo.ForgingAccount{Account: account.Account, Name: name})
+
+		stats := user.networkInfoListener.GetForgingStats(account.Account, plot)
+		text += fmt.Sprintf("\n\n<b>%v</b>", name) + networkinfo.FormatForgingStats(stats)
+	}
+	if plot.PlotSizeTiB <= 0 {
+		text += fmt.Sprintf("\n\nSave your plot via <b>%v plot TiB COMMITMENT</b> to see expected blocks and luck", config.COMMAND_MINING)
+	}
+
+	return &BotMessage{
+		MainText: text,
+		Chart:    user.networkInfoListener.GetForgingChart(forgingAccounts, plot),
+	}
+}
@@ -55,6 +55,7 @@ func (user *User) calculate(tib, commit float64) string {
 	lastMiningInfo := user.networkInfoListener.GetLastMiningInfo()
 
 	if commit > 0 {
+		user.updateMiningAlert(map[string]interface{}{"plot_size_ti_b": tib, "commitment": commit}) // for mining alerts
 		calcResult := calculator.Calculate(&lastMiningInfo, tib, commit)
 		reinvestmentCalcResult := calculator.CalculateReinvestment(&lastMiningInfo, calcResult)
 
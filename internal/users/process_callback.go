@@ -75,6 +75,11 @@ func (user *User) ProcessCallback(callbackQuery *tgbotapi.CallbackQuery) *BotMes
 			user.currencySelected = CT_BTC
 		}
 		answerBotMessage.InlineKeyboard = user.GetConvertKeyboard()
+	case callbackdata.KeyboardType_KT_PRICE_ALERTS:
+		if callbackData.Action == callbackdata.ActionType_AT_DELETE_PRICE_ALERT {
+			user.deletePriceAlert(callbackData.GetItemId())
+		}
+		answerBotMessage = user.getPriceAlertsMessage()
 	}
 
 	if err != nil {
@@ -87,18 +92,6 @@ func (user *User) ProcessCallback(callbackQuery *tgbotapi.CallbackQuery) *BotMes
 }
 
 func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataType) (*BotMessage, error) {
-	backInlineKeyboard := tgbotapi.NewInlineKeyboardMarkup(
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				config.BUTTON_BACK,
-				callbackdata.QueryDataType{
-					Account:  callbackData.Account,
-					Keyboard: callbackdata.KeyboardType_KT_ACCOUNT,
-					Action:   callbackdata.ActionType_AT_REFRESH,
-				}.GetBase64ProtoString()),
-		),
-	)
-
 	account, err := user.signumClient.GetCachedAccount(user.logger, callbackData.Account)
 	if err != nil {
 		return nil, fmt.Errorf("🚫 Error: %v", err)
@@ -108,13 +101,58 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 	case callbackdata.ActionType_AT_REFRESH:
 		return user.getAccountInfoMessage(account.Account)
 
+	case callbackdata.ActionType_AT_RULES,
+		callbackdata.ActionType_AT_DELETE_RULE:
+		userAccount := user.GetDbAccount(account.Account)
+		if userAccount == nil {
+			return nil, fmt.Errorf("🚫 Please add <b>%v</b> into your menu via <b>%v ACCOUNT</b> to set up notification rules", account.AccountRS, config.COMMAND_ADD)
+		}
+
+		if callbackData.GetAction() == callbackdata.ActionType_AT_DELETE_RULE {
+			user.deleteRule(userAccount, callbackData.GetItemId())
+		}
+		return user.getRulesMessage(userAccount), nil
+
+	case callbackdata.ActionType_AT_BALANCE_ALERTS,
+		callbackdata.ActionType_AT_SET_BALANCE_FLOOR,
+		callbackdata.ActionType_AT_DISABLE_BALANCE_FLOOR,
+		callbackdata.ActionType_AT_SET_BALANCE_CHANGE,
+		callbackdata.ActionType_AT_DISABLE_BALANCE_CHANGE:
+		userAccount := user.GetDbAccount(account.Account)
+		if userAccount == nil {
+			// needs to add it at first
+			var msg string
+			userAccount, msg = user.addAccount(account.Account, "")
+			if userAccount == nil {
+				return nil, errors.New(msg)
+			}
+		}
+
+		switch callbackData.GetAction() {
+		case callbackdata.ActionType_AT_SET_BALANCE_FLOOR:
+			user.state = BALANCE_FLOOR_STATE
+			user.balanceAccount = userAccount.Account
+			return &BotMessage{MainText: fmt.Sprintf("💰 Please send me a <b>balance floor in SIGNA</b> for <b>%v</b>, "+
+				"you will be alerted when the available balance drops below it:", userAccount.AccountRS)}, nil
+		case callbackdata.ActionType_AT_SET_BALANCE_CHANGE:
+			user.state = BALANCE_CHANGE_STATE
+			user.balanceAccount = userAccount.Account
+			return &BotMessage{MainText: fmt.Sprintf("💰 Please send me a <b>percentage</b> for <b>%v</b>, "+
+				"you will be alerted when the available balance changes by more than it:", userAccount.AccountRS)}, nil
+		case callbackdata.ActionType_AT_DISABLE_BALANCE_FLOOR:
+			user.setBalanceFloor(userAccount, 0)
+		case callbackdata.ActionType_AT_DISABLE_BALANCE_CHANGE:
+			user.setBalanceChange(userAccount, 0)
+		}
+		return user.getBalanceAlertsMessage(userAccount), nil
+
 	case callbackdata.ActionType_AT_PAYMENTS:
-		accountTransactions, err := user.signumClient.GetCachedAccountOrdinaryPaymentTransactions(user.logger, account.Account)
+		accountTransactions, err := user.signumClient.GetCachedAccountOrdinaryPaymentTransactions(user.logger, account.Account, callbackData.GetPage())
 		if err != nil {
 			return nil, fmt.Errorf("🚫 Error: %v", err)
 		}
 
-		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last ordinary payment transactions:\n\n", account.AccountRS)
+		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last ordinary payment transactions%v:\n\n", account.AccountRS, getPageTitle(callbackData.GetPage()))
 		for _, transaction := range accountTransactions.Transactions {
 			if account.Account == transaction.Sender {
 				newInlineText += fmt.Sprintf("<i>%v</i>  Sent to <b>%v</b>  <i>-%v SIGNA</i>\n",
@@ -127,16 +165,16 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 
 		return &BotMessage{
 			InlineText:     newInlineText,
-			InlineKeyboard: &backInlineKeyboard,
+			InlineKeyboard: user.GetHistoryKeyboard(callbackData, accountTransactions.HasNextPage),
 		}, nil
 
 	case callbackdata.ActionType_AT_AT_PAYMENTS:
-		accountTransactions, err := user.signumClient.GetCachedAccountATPaymentTransactions(user.logger, account.Account)
+		accountTransactions, err := user.signumClient.GetCachedAccountATPaymentTransactions(user.logger, account.Account, callbackData.GetPage())
 		if err != nil {
 			return nil, fmt.Errorf("🚫 Error: %v", err)
 		}
 
-		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last AT payment transactions:\n\n", account.AccountRS)
+		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last AT payment transactions%v:\n\n", account.AccountRS, getPageTitle(callbackData.GetPage()))
 		for _, transaction := range accountTransactions.Transactions {
 			if account.Account == transaction.Sender {
 				newInlineText += fmt.Sprintf("<i>%v</i>  Sent to <b>%v</b>  <i>-%v SIGNA</i>\n",
@@ -149,16 +187,16 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 
 		return &BotMessage{
 			InlineText:     newInlineText,
-			InlineKeyboard: &backInlineKeyboard,
+			InlineKeyboard: user.GetHistoryKeyboard(callbackData, accountTransactions.HasNextPage),
 		}, nil
 
 	case callbackdata.ActionType_AT_BLOCKS:
-		accountBlocks, err := user.signumClient.GetCachedAccountBlocks(user.logger, account.Account)
+		accountBlocks, err := user.signumClient.GetCachedAccountBlocks(user.logger, account.Account, callbackData.GetPage())
 		if err != nil {
 			return nil, fmt.Errorf("🚫 Error: %v", err)
 		}
 
-		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last blocks:\n\n", account.AccountRS)
+		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last blocks%v:\n\n", account.AccountRS, getPageTitle(callbackData.GetPage()))
 		for _, block := range accountBlocks.Blocks {
 			timeSince := time.Since(common.ChainTimeToTime(block.Timestamp))
 			var timeSinceStr string
@@ -178,16 +216,22 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 
 		return &BotMessage{
 			InlineText:     newInlineText,
-			InlineKeyboard: &backInlineKeyboard,
+			InlineKeyboard: user.GetHistoryKeyboard(callbackData, accountBlocks.HasNextPage),
 		}, nil
 
+	case callbackdata.ActionType_AT_TOKENS:
+		return user.getTokensMessage(account, callbackData), nil
+
+	case callbackdata.ActionType_AT_MARKETPLACE:
+		return user.getMarketplaceMessage(account, callbackData)
+
 	case callbackdata.ActionType_AT_MULTI_OUT:
-		accountTransactions, err := user.signumClient.GetCachedAccountMultiOutTransactions(user.logger, account.Account)
+		accountTransactions, err := user.signumClient.GetCachedAccountMultiOutTransactions(user.logger, account.Account, callbackData.GetPage())
 		if err != nil {
 			return nil, fmt.Errorf("🚫 Error: %v", err)
 		}
 
-		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last multi-out payment transactions:\n\n", account.AccountRS)
+		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last multi-out payment transactions%v:\n\n", account.AccountRS, getPageTitle(callbackData.GetPage()))
 		for _, transaction := range accountTransactions.Transactions {
 			if account.Account != transaction.Sender {
 				newInlineText += fmt.Sprintf("<i>%v</i>  Received from <b>%v</b>  <i>+%v SIGNA</i>\n",
@@ -200,16 +244,16 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 
 		return &BotMessage{
 			InlineText:     newInlineText,
-			InlineKeyboard: &backInlineKeyboard,
+			InlineKeyboard: user.GetHistoryKeyboard(callbackData, accountTransactions.HasNextPage),
 		}, nil
 
 	case callbackdata.ActionType_AT_MULTI_OUT_SAME:
-		accountTransactions, err := user.signumClient.GetCachedAccountMultiOutSameTransactions(user.logger, account.Account)
+		accountTransactions, err := user.signumClient.GetCachedAccountMultiOutSameTransactions(user.logger, account.Account, callbackData.GetPage())
 		if err != nil {
 			return nil, fmt.Errorf("🚫 Error: %v", err)
 		}
 
-		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last multi-out same payment transactions:\n\n", account.AccountRS)
+		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last multi-out same payment transactions%v:\n\n", account.AccountRS, getPageTitle(callbackData.GetPage()))
 		for _, transaction := range accountTransactions.Transactions {
 			if account.Account != transaction.Sender {
 				newInlineText += fmt.Sprintf("<i>%v</i>  Received from <b>%v</b>  <i>+%v SIGNA</i>\n",
@@ -225,16 +269,16 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 
 		return &BotMessage{
 			InlineText:     newInlineText,
-			InlineKeyboard: &backInlineKeyboard,
+			InlineKeyboard: user.GetHistoryKeyboard(callbackData, accountTransactions.HasNextPage),
 		}, nil
 
 	case callbackdata.ActionType_AT_OTHER_TXS:
-		accountTransactions, err := user.signumClient.GetCachedAccountMiningTransactions(user.logger, account.Account)
+		accountTransactions, err := user.signumClient.GetCachedAccountMiningTransactions(user.logger, account.Account, callbackData.GetPage())
 		if err != nil {
 			return nil, fmt.Errorf("🚫 Error: %v", err)
 		}
 
-		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last mining transactions:\n\n", account.AccountRS)
+		var newInlineText = fmt.Sprintf("💳 <b>%v</b> last mining transactions%v:\n\n", account.AccountRS, getPageTitle(callbackData.GetPage()))
 		for _, transaction := range accountTransactions.Transactions {
 			switch transaction.Subtype {
 			case signumapi.TST_REWARD_RECIPIENT_ASSIGNMENT:
@@ -253,7 +297,7 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 
 		return &BotMessage{
 			InlineText:     newInlineText,
-			InlineKeyboard: &backInlineKeyboard,
+			InlineKeyboard: user.GetHistoryKeyboard(callbackData, accountTransactions.HasNextPage),
 		}, nil
 
 	case callbackdata.ActionType_AT_ENABLE_INCOME_TX_NOTIFY,
@@ -268,17 +312,6 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 			}
 		}
 
-		lastAccountTransaction := user.signumClient.GetLastCachedAccountPaymentTransaction(user.logger, userAccount.Account)
-		if lastAccountTransaction != nil {
-			userAccount.LastTransactionID = lastAccountTransaction.TransactionID
-			userAccount.LastTransactionH = lastAccountTransaction.Height
-		}
-		lastAccountATPaymentTransaction := user.signumClient.GetLastCachedAccountATPaymentTransaction(user.logger, account.Account)
-		if lastAccountATPaymentTransaction != nil {
-			userAccount.LastATPaymentTX = lastAccountATPaymentTransaction.TransactionID
-			userAccount.LastATPaymentH = lastAccountATPaymentTransaction.Height
-		}
-
 		var txType string
 		switch callbackData.GetAction() {
 		case callbackdata.ActionType_AT_ENABLE_INCOME_TX_NOTIFY:
@@ -335,11 +368,6 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 
 		if !userAccount.NotifyNewBlocks { // needs to enable
 			userAccount.NotifyNewBlocks = true
-			lastAccountBlock := user.signumClient.GetLastAccountBlock(user.logger, account.Account)
-			if lastAccountBlock != nil {
-				userAccount.LastBlockID = lastAccountBlock.Block
-				userAccount.LastBlockH = lastAccountBlock.Height
-			}
 			user.db.Save(userAccount)
 		}
 
@@ -376,16 +404,6 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 
 		if !userAccount.NotifyOtherTXs { // needs to enable
 			userAccount.NotifyOtherTXs = true
-			lastAccountMiningTransaction := user.signumClient.GetLastCachedAccountMiningTransaction(user.logger, account.Account)
-			if lastAccountMiningTransaction != nil {
-				userAccount.LastMiningTX = lastAccountMiningTransaction.TransactionID
-				userAccount.LastMiningH = lastAccountMiningTransaction.Height
-			}
-			lastAccountMessageTransaction := user.signumClient.GetLastCachedAccountMessageTransaction(user.logger, account.Account)
-			if lastAccountMessageTransaction != nil {
-				userAccount.LastMessageTX = lastAccountMessageTransaction.TransactionID
-				userAccount.LastMessageH = lastAccountMessageTransaction.Height
-			}
 			user.db.Save(userAccount)
 		}
 
@@ -413,3 +431,11 @@ func (user *User) processAccountKeyboard(callbackData *callbackdata.QueryDataTyp
 		return nil, fmt.Errorf("🚫 Unknown callback %v", callbackData.GetAction())
 	}
 }
+
+// getPageTitle returns nothing for the first page
+func getPageTitle(page uint64) string {
+	if page == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (page %v)", page+1)
+}
@@ -0,0 +1,96 @@
+package users
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+const digestUsageText = `📰 <b>Digest</b> summarizes income, outgo, fees, forged blocks and the balance change of your accounts:
+<b>` + config.COMMAND_DIGEST + ` daily|weekly [HOUR] [TIMEZONE] [only]</b> - send the digest at HOUR (9 by default) of TIMEZONE (e.g. UTC, Europe/Berlin), weekly digests are sent on Monday. Add <b>only</b> to receive the digest instead of instant transaction notifications
+<b>` + config.COMMAND_DIGEST + ` off</b> - disable the digest`
+
+func (user *User) ProcessDigest(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if splittedMessage[0] != config.COMMAND_DIGEST {
+		return &BotMessage{MainText: digestUsageText}
+	}
+	if len(splittedMessage) == 1 {
+		return &BotMessage{MainText: user.getDigestText() + "\n\n" + digestUsageText}
+	}
+
+	var period = models.DigestPeriod(strings.ToLower(splittedMessage[1]))
+	switch period {
+	case "off":
+		user.DigestPeriod = ""
+		user.DigestOnly = false
+		user.db.Save(&user.DbUser)
+		user.deleteDigestStats()
+		return &BotMessage{MainText: "📰 The digest has been disabled"}
+	case models.DIGEST_DAILY, models.DIGEST_WEEKLY:
+	default:
+		return &BotMessage{MainText: digestUsageText}
+	}
+
+	var hour = 9
+	var timezone = "UTC"
+	var digestOnly bool
+	for _, option := range splittedMessage[2:] {
+		if strings.ToLower(option) == "only" {
+			digestOnly = true
+			continue
+		}
+		if number, err := strconv.Atoi(option); err == nil {
+			if number < 0 || number > 23 {
+				return &BotMessage{MainText: "🚫 HOUR should be in the range 0-23"}
+			}
+			hour = number
+			continue
+		}
+		if _, err := time.LoadLocation(option); err != nil || option == "" || option == "Local" {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Unknown timezone <b>%v</b>, please use a name like UTC or Europe/Berlin", html.EscapeString(option))}
+		}
+		timezone = option
+	}
+
+	if user.DigestPeriod != period {
+		user.deleteDigestStats() // start collecting for the new period
+	}
+	user.DigestPeriod = period
+	user.DigestHour = hour
+	user.DigestTimezone = timezone
+	user.DigestOnly = digestOnly
+	user.db.Save(&user.DbUser)
+
+	return &BotMessage{MainText: "✅ " + user.getDigestText()}
+}
+
+func (user *User) getDigestText() string {
+	if user.DigestPeriod == "" {
+		return "📰 The digest is disabled"
+	}
+
+	var text = fmt.Sprintf("📰 The %v digest is sent at %02d:00 %v", user.DigestPeriod, user.DigestHour, user.DigestTimezone)
+	if user.DigestPeriod == models.DIGEST_WEEKLY {
+		text += " on Monday"
+	}
+	if user.DigestOnly {
+		text += " instead of instant transaction notifications"
+	}
+	return text + ", the first one covers the time since it has been enabled"
+}
+
+func (user *User) deleteDigestStats() {
+	var ids = make([]uint, 0, len(user.Accounts))
+	for _, account := range user.Accounts {
+		ids = append(ids, account.ID)
+	}
+	if len(ids) > 0 {
+		user.db.Unscoped().Where("db_account_id IN ?", ids).Delete(&models.DigestStat{})
+	}
+}
@@ -0,0 +1,365 @@
+package users
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/prices"
+)
+
+const exportUsageText = `📄 <b>Export</b> the full history of an account with USD and BTC values at the time of each transaction:
+<b>` + config.COMMAND_EXPORT + ` ACCOUNT [FROM] [TO] [csv|json]</b> - FROM and TO are dates like 2021-12-31 (UTC), CSV by default`
+
+const (
+	exportPageSize   = 100
+	exportMaxEntries = 50000
+	exportDateLayout = "2006-01-02"
+)
+
+const (
+	ENTRY_PAYMENT        = "payment"
+	ENTRY_MULTI_OUT      = "multi_out"
+	ENTRY_MULTI_OUT_SAME = "multi_out_same"
+	ENTRY_AT_PAYMENT     = "at_payment"
+	ENTRY_DISTRIBUTION   = "distribution"
+	ENTRY_OTHER          = "other"
+	ENTRY_FEE            = "fee"
+	ENTRY_BLOCK_REWARD   = "block_reward"
+	ENTRY_BLOCK_FEES     = "block_fees"
+)
+
+// ledgerEntry is a single balance change, the amount is negative for outgo
+type ledgerEntry struct {
+	Time          time.Time `json:"time"`
+	Height        uint64    `json:"height"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Type          string    `json:"type"`
+	Counterparty  string    `json:"counterparty,omitempty"`
+	AmountNQT     int64     `json:"amount_nqt"`
+	SignaUSD      float64   `json:"signa_usd"`
+	SignaBTC      float64   `json:"signa_btc"`
+	ValueUSD      float64   `json:"value_usd"`
+	ValueBTC      float64   `json:"value_btc"`
+}
+
+func (user *User) ProcessExport(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if len(splittedMessage) < 2 || len(splittedMessage) > 5 || splittedMessage[0] != config.COMMAND_EXPORT {
+		return &BotMessage{MainText: exportUsageText}
+	}
+
+	var format = "csv"
+	var dates []time.Time
+	for _, option := range splittedMessage[2:] {
+		switch strings.ToLower(option) {
+		case "csv", "json":
+			format = strings.ToLower(option)
+			continue
+		}
+		date, err := time.Parse(exportDateLayout, option)
+		if err != nil || len(dates) == 2 {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Bad date <b>%v</b>, please use the format 2021-12-31\n\n", html.EscapeString(option)) + exportUsageText}
+		}
+		dates = append(dates, date)
+	}
+
+	var from = common.ChainTimeToTime(0).UTC()
+	var to = time.Now().UTC()
+	if len(dates) > 0 {
+		from = dates[0]
+	}
+	if len(dates) > 1 {
+		to = dates[1].Add(config.DAY - time.Second) // the whole last day
+	}
+	if !from.Before(to) {
+		return &BotMessage{MainText: "🚫 FROM should be earlier than TO"}
+	}
+
+	var accountS = splittedMessage[1]
+	if userAccount, _ := user.tryFoundAccountInMenu(accountS); userAccount != nil {
+		accountS = userAccount.Account
+	} else if parsedAccount, err := parseAccount(accountS); err != nil {
+		return &BotMessage{MainText: err.Error()}
+	} else {
+		accountS = parsedAccount
+	}
+	account, err := user.signumClient.GetCachedAccount(user.logger, accountS)
+	if err != nil {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 Error: %v", err)}
+	}
+
+	if user.exportRunning {
+		return &BotMessage{MainText: "🚫 The previous export is still running, please wait for its file"}
+	}
+	user.exportRunning = true
+
+	return &BotMessage{
+		MainText: fmt.Sprintf("⏳ Export of <b>%v</b> has started, the file will be sent once it's ready", account.AccountRS),
+		Job: func(shutdownChannel chan interface{}) *BotMessage {
+			defer func() {
+				user.Lock()
+				user.exportRunning = false
+				user.Unlock()
+			}()
+			return user.exportLedger(account, from, to, format, shutdownChannel)
+		},
+	}
+}
+
+// exportLedger runs in the background, the answer is the file or the error
+func (user *User) exportLedger(account *signumapi.Account, from, to time.Time, format string, shutdownChannel chan interface{}) *BotMessage {
+	entries, err := user.getLedgerEntries(account.Account, from, to, shutdownChannel)
+	if err != nil {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 Export of <b>%v</b> has failed: %v", account.AccountRS, html.EscapeString(err.Error()))}
+	}
+	if len(entries) == 0 {
+		return &BotMessage{MainText: fmt.Sprintf("📄 There are no transactions of <b>%v</b> from %v to %v",
+			account.AccountRS, from.Format(exportDateLayout), to.Format(exportDateLayout))}
+	}
+	user.addLedgerValues(entries, from, to)
+
+	var document []byte
+	if format == "json" {
+		document, err = json.MarshalIndent(entries, "", "  ")
+	} else {
+		document, err = formatLedgerCSV(entries)
+	}
+	if err != nil {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 Export of <b>%v</b> has failed: %v", account.AccountRS, html.EscapeString(err.Error()))}
+	}
+
+	var incomeNQT, outgoNQT uint64
+	for _, entry := range entries {
+		if entry.AmountNQT > 0 {
+			incomeNQT += uint64(entry.AmountNQT)
+		} else {
+			outgoNQT += uint64(-entry.AmountNQT)
+		}
+	}
+
+	return &BotMessage{
+		MainText: fmt.Sprintf("📄 <b>Ledger of %v</b>"+
+			"\n<i>Period:</i> %v - %v (UTC)"+
+			"\n<i>Entries:</i> %v"+
+			"\n<i>Income:</i> +%v SIGNA"+
+			"\n<i>Outgo:</i> -%v SIGNA",
+			account.AccountRS, entries[0].Time.Format(exportDateLayout), to.Format(exportDateLayout), len(entries),
+			common.FormatNQT(incomeNQT), common.FormatNQT(outgoNQT)),
+		Document:     document,
+		DocumentName: fmt.Sprintf("%v_%v_%v.%v", account.AccountRS, from.Format(exportDateLayout), to.Format(exportDateLayout), format),
+	}
+}
+
+// getLedgerEntries pages through the whole history of the account, entries are ordered by time
+func (user *User) getLedgerEntries(account string, from, to time.Time, shutdownChannel chan interface{}) ([]*ledgerEntry, error) {
+	var timestamp = common.TimeToChainTime(from)
+	var entries []*ledgerEntry
+
+	for firstIndex := uint64(0); ; firstIndex += exportPageSize {
+		select {
+		case <-shutdownChannel:
+			return nil, fmt.Errorf("the bot is restarting, please try again later")
+		default:
+		}
+
+		accountTransactions, err := user.signumClient.GetAccountTransactionsRange(user.logger, account, timestamp, firstIndex, firstIndex+exportPageSize-1)
+		if err != nil {
+			return nil, err
+		}
+		for i := range accountTransactions.Transactions {
+			transaction := &accountTransactions.Transactions[i]
+			if common.ChainTimeToTime(transaction.Timestamp).After(to) {
+				continue
+			}
+			transactionEntries, err := user.getTransactionEntries(account, transaction)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, transactionEntries...)
+		}
+		if len(entries) > exportMaxEntries {
+			return nil, fmt.Errorf("there are more than %v transactions, please narrow the period", exportMaxEntries)
+		}
+		if len(accountTransactions.Transactions) < exportPageSize {
+			break
+		}
+	}
+
+	var heights = make(map[uint64]bool)
+	for firstIndex := uint64(0); ; firstIndex += exportPageSize {
+		select {
+		case <-shutdownChannel:
+			return nil, fmt.Errorf("the bot is restarting, please try again later")
+		default:
+		}
+
+		accountBlocks, err := user.signumClient.GetAccountBlocksRange(user.logger, account, timestamp, firstIndex, firstIndex+exportPageSize-1)
+		if err != nil {
+			return nil, err
+		}
+		var newBlocks int
+		for _, block := range accountBlocks.Blocks {
+			if heights[block.Height] { // the node may ignore lastIndex
+				continue
+			}
+			heights[block.Height] = true
+			newBlocks++
+
+			forgedAt := common.ChainTimeToTime(block.Timestamp)
+			if forgedAt.After(to) {
+				continue
+			}
+			blockRewardNQT := block.GetRewardNQT() - block.TotalFeeNQT
+			entries = append(entries, &ledgerEntry{Time: forgedAt, Height: block.Height, Type: ENTRY_BLOCK_REWARD, AmountNQT: int64(blockRewardNQT)})
+			if block.TotalFeeNQT > 0 {
+				entries = append(entries, &ledgerEntry{Time: forgedAt, Height: block.Height, Type: ENTRY_BLOCK_FEES, AmountNQT: int64(block.TotalFeeNQT)})
+			}
+		}
+		if len(entries) > exportMaxEntries {
+			return nil, fmt.Errorf("there are more than %v transactions, please narrow the period", exportMaxEntries)
+		}
+		if len(accountBlocks.Blocks) < exportPageSize || newBlocks == 0 {
+			break
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Time.Equal(entries[j].Time) {
+			return entries[i].Height < entries[j].Height
+		}
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries, nil
+}
+
+// getTransactionEntries returns the amount and the fee of the transaction as separate entries
+func (user *User) getTransactionEntries(account string, transaction *signumapi.Transaction) ([]*ledgerEntry, error) {
+	var outgo = transaction.Sender == account
+	var entry = &ledgerEntry{
+		Time:          common.ChainTimeToTime(transaction.Timestamp),
+		Height:        transaction.Height,
+		TransactionID: transaction.TransactionID,
+		Counterparty:  transaction.SenderRS,
+	}
+	if outgo {
+		entry.Counterparty = transaction.RecipientRS
+		entry.AmountNQT = -int64(transaction.AmountNQT)
+	} else {
+		entry.AmountNQT = int64(transaction.AmountNQT)
+	}
+
+	switch {
+	case transaction.Type == signumapi.TT_PAYMENT && transaction.Subtype == signumapi.TST_ORDINARY_PAYMENT:
+		entry.Type = ENTRY_PAYMENT
+	case transaction.Type == signumapi.TT_PAYMENT && transaction.Subtype == signumapi.TST_MULTI_OUT_PAYMENT:
+		entry.Type = ENTRY_MULTI_OUT
+		if outgo {
+			entry.Counterparty = fmt.Sprintf("%v recipients", len(transaction.Attachment.Recipients))
+		} else {
+			entry.AmountNQT = int64(transaction.GetMyMultiOutAmountNQT(account))
+		}
+	case transaction.Type == signumapi.TT_PAYMENT && transaction.Subtype == signumapi.TST_MULTI_OUT_SAME_PAYMENT:
+		entry.Type = ENTRY_MULTI_OUT_SAME
+		if outgo {
+			entry.Counterparty = fmt.Sprintf("%v recipients", len(transaction.Attachment.Recipients))
+		} else {
+			entry.AmountNQT = int64(transaction.GetMultiOutSameAmountNQT())
+		}
+	case transaction.Type == signumapi.TT_AUTOMATED_TRANSACTIONS && transaction.Subtype == signumapi.TST_AT_PAYMENT:
+		entry.Type = ENTRY_AT_PAYMENT
+	case transaction.Type == signumapi.TT_TOKENIZATION && transaction.Subtype == signumapi.TST_TOKENIZATION_DISTRIBUTION_TO_HOLDER:
+		entry.Type = ENTRY_DISTRIBUTION
+		entry.Counterparty = transaction.SenderRS
+		if !outgo {
+			distributionAmount, err := user.signumClient.GetDistributionAmount(user.logger, transaction.TransactionID, account)
+			if err != nil {
+				return nil, fmt.Errorf("can't get the distribution amount of transaction %v: %v", transaction.TransactionID, err)
+			}
+			entry.AmountNQT = int64(distributionAmount.AmountNQT)
+		}
+	default:
+		entry.Type = ENTRY_OTHER
+		if transaction.Recipient == "" { // commitments and tokens don't move SIGNA between accounts
+			entry.AmountNQT = 0
+		}
+	}
+	if transaction.Sender == transaction.Recipient {
+		entry.AmountNQT = 0
+	}
+
+	var entries []*ledgerEntry
+	if entry.AmountNQT != 0 {
+		entries = append(entries, entry)
+	}
+	if outgo && transaction.FeeNQT > 0 {
+		entries = append(entries, &ledgerEntry{
+			Time:          entry.Time,
+			Height:        transaction.Height,
+			TransactionID: transaction.TransactionID,
+			Type:          ENTRY_FEE,
+			AmountNQT:     -int64(transaction.FeeNQT),
+		})
+	}
+	return entries, nil
+}
+
+// addLedgerValues calculates USD and BTC values by the saved prices
+func (user *User) addLedgerValues(entries []*ledgerEntry, from, to time.Time) {
+	priceHistory := user.priceManager.GetPriceHistory(from, to)
+	for _, entry := range entries {
+		price := prices.FindPrice(priceHistory, entry.Time)
+		if price == nil {
+			continue
+		}
+		entry.SignaUSD = price.SignaPrice
+		if price.BtcPrice > 0 {
+			entry.SignaBTC = price.SignaPrice / price.BtcPrice
+		}
+		amount := float64(entry.AmountNQT) / 1e8
+		entry.ValueUSD = amount * entry.SignaUSD
+		entry.ValueBTC = amount * entry.SignaBTC
+	}
+}
+
+func formatLedgerCSV(entries []*ledgerEntry) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Write([]string{"time_utc", "height", "transaction_id", "type", "counterparty", "amount_signa",
+		"signa_usd", "signa_btc", "value_usd", "value_btc"})
+	for _, entry := range entries {
+		writer.Write([]string{
+			entry.Time.UTC().Format("2006-01-02 15:04:05"),
+			strconv.FormatUint(entry.Height, 10),
+			entry.TransactionID,
+			entry.Type,
+			entry.Counterparty,
+			formatSignedNQT(entry.AmountNQT),
+			strconv.FormatFloat(entry.SignaUSD, 'f', 8, 64),
+			strconv.FormatFloat(entry.SignaBTC, 'f', 10, 64),
+			strconv.FormatFloat(entry.ValueUSD, 'f', 2, 64),
+			strconv.FormatFloat(entry.ValueBTC, 'f', 8, 64),
+		})
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+// formatSignedNQT returns the exact amount in SIGNA without rounding
+func formatSignedNQT(amountNQT int64) string {
+	var sign string
+	if amountNQT < 0 {
+		sign = "-"
+		amountNQT = -amountNQT
+	}
+	return fmt.Sprintf("%v%d.%08d", sign, amountNQT/1e8, amountNQT%1e8)
+}
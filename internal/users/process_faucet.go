@@ -70,8 +70,10 @@ func (user *User) sendOrdinaryFaucet(account string) (bool, string) {
 	var userAccount *models.DbAccount
 	var addedMessage string
 
-	if !config.ValidAccountRS.MatchString(account) && !config.ValidAccount.MatchString(account) {
-		return false, "🚫 Incorrect account format, please use the <b>S-XXXX-XXXX-XXXX-XXXXX</b> or <b>numeric AccountID</b>"
+	var accountInput = account // is shown in the answer
+	account, err := parseAccount(account)
+	if err != nil {
+		return false, err.Error()
 	}
 
 	if user.ID > 1 {
@@ -92,16 +94,6 @@ func (user *User) sendOrdinaryFaucet(account string) (bool, string) {
 				addedMessage += "\n\n"
 			}
 
-			lastAccountTransaction := user.signumClient.GetLastCachedAccountPaymentTransaction(user.logger, userAccount.Account)
-			if lastAccountTransaction != nil {
-				userAccount.LastTransactionID = lastAccountTransaction.TransactionID
-				userAccount.LastTransactionH = lastAccountTransaction.Height
-			}
-			lastAccountATPaymentTransaction := user.signumClient.GetLastCachedAccountATPaymentTransaction(user.logger, userAccount.Account)
-			if lastAccountATPaymentTransaction != nil {
-				userAccount.LastATPaymentTX = lastAccountATPaymentTransaction.TransactionID
-				userAccount.LastATPaymentH = lastAccountATPaymentTransaction.Height
-			}
 			userAccount.NotifyIncomeTransactions = true
 			user.db.Save(&userAccount)
 		}
@@ -115,7 +107,7 @@ func (user *User) sendOrdinaryFaucet(account string) (bool, string) {
 	}
 
 	var accountFaucet models.Faucet
-	err := user.db.
+	err = user.db.
 		Where("account = ? OR account_rs = ?", account, account).
 		Where("amount = ?", amount).
 		Last(&accountFaucet).Error
@@ -135,7 +127,7 @@ func (user *User) sendOrdinaryFaucet(account string) (bool, string) {
 
 	user.ResetState()
 	return true, fmt.Sprintf(addedMessage+"✅ Faucet payment <b>%v SIGNA</b> has been successfully sent to the account <b>%v</b>, please wait for notification!",
-		amount, account)
+		amount, accountInput)
 }
 
 func (user *User) sendExtraFaucetIfNeeded(userAccount *models.DbAccount) string {
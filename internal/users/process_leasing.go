@@ -0,0 +1,45 @@
+package users
+
+import (
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+)
+
+// getAccountLeasingText returns the effective balance leasing state for the account card
+func (user *User) getAccountLeasingText(account *signumapi.Account) string {
+	var text string
+	if account.CurrentLessee != "" {
+		text += "\nLeasing to: " + account.CurrentLesseeRS
+		if lesseeName := user.signumClient.GetCachedAccountName(user.logger, account.CurrentLessee); lesseeName != "" {
+			text += " (" + html.EscapeString(lesseeName) + ")"
+		}
+		text += user.formatLeasingExpiration(account.CurrentLeasingHeightTo)
+	}
+	if account.NextLessee != "" && account.NextLessee != account.CurrentLessee {
+		text += "\nNext lessee: " + account.NextLesseeRS
+	}
+	switch len(account.LessorsRS) {
+	case 0:
+	case 1:
+		text += "\nLeased from: " + account.LessorsRS[0]
+	default:
+		text += fmt.Sprintf("\nLeased from: %v accounts", len(account.LessorsRS))
+	}
+	return text
+}
+
+// formatLeasingExpiration estimates the expiration time by the target block time
+func (user *User) formatLeasingExpiration(height uint64) string {
+	var text = fmt.Sprintf(" until block #%v", height)
+	blockchainStatus, err := user.signumClient.GetCachedBlockchainStatus(user.logger)
+	if err != nil || blockchainStatus.NumberOfBlocks > height {
+		return text
+	}
+	expiration := time.Now().Add(time.Duration(height-blockchainStatus.NumberOfBlocks+1) * config.BLOCK_TIME)
+	return text + " (~" + common.FormatChainTimeToStringDatetimeUTC(common.TimeToChainTime(expiration)) + ")"
+}
@@ -0,0 +1,110 @@
+package users
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/users/callbackdata"
+)
+
+// last delivered purchases shown on the first page of the Marketplace view
+const marketplaceDeliveriesLimit = 5
+
+// getMarketplaceMessage lists goods of the seller page by page, pending purchases and the last deliveries with feedback are on the first page
+func (user *User) getMarketplaceMessage(account *signumapi.Account, callbackData *callbackdata.QueryDataType) (*BotMessage, error) {
+	var pageSize = int(user.signumClient.GetPageSize())
+	var first = int(callbackData.GetPage()) * pageSize
+
+	var newInlineText = fmt.Sprintf("🛒 <b>%v</b> marketplace%v:\n\n", account.AccountRS, getPageTitle(callbackData.GetPage()))
+
+	goodsList, err := user.signumClient.GetSellerGoods(user.logger, account.Account, first, first+pageSize-1)
+	if err != nil {
+		return nil, fmt.Errorf("🚫 Error: %v", err)
+	}
+
+	if callbackData.GetPage() == 0 {
+		newInlineText += user.getPendingPurchasesText(account)
+	}
+
+	newInlineText += "🏷 <b>Listings:</b>\n"
+	if len(goodsList.Goods) == 0 && first == 0 {
+		newInlineText += "The account doesn't sell any goods\n"
+	}
+	for _, goods := range goodsList.Goods {
+		newInlineText += fmt.Sprintf("<b>%v</b>  %v SIGNA", html.EscapeString(goods.Name), common.FormatNQT(goods.PriceNQT))
+		if goods.Quantity > 0 {
+			newInlineText += fmt.Sprintf("  <i>In stock:</i> %v\n", goods.Quantity)
+		} else {
+			newInlineText += "  <i>sold out</i>\n"
+		}
+	}
+
+	if callbackData.GetPage() == 0 {
+		newInlineText += user.getDeliveriesText(account)
+	}
+
+	return &BotMessage{
+		InlineText:     newInlineText,
+		InlineKeyboard: user.GetHistoryKeyboard(callbackData, len(goodsList.Goods) == pageSize),
+	}, nil
+}
+
+func (user *User) getPendingPurchasesText(account *signumapi.Account) string {
+	purchases, err := user.signumClient.GetSellerPendingPurchases(user.logger, account.Account)
+	if err != nil {
+		user.logger.Errorf("Can't get pending purchases of %v: %v", account.Account, err)
+		return ""
+	}
+	if len(purchases.Purchases) == 0 {
+		return ""
+	}
+
+	var text = "⏳ <b>Waiting for delivery:</b>\n"
+	for _, purchase := range purchases.Purchases {
+		text += fmt.Sprintf("<b>%v</b> x%v  %v SIGNA  <i>from</i> %v%v\n<i>Deliver until:</i> %v\n",
+			html.EscapeString(purchase.Name), purchase.Quantity, common.FormatNQT(purchase.GetAmountNQT()),
+			purchase.BuyerRS, user.formatBuyerName(purchase.Buyer),
+			common.FormatChainTimeToStringDatetimeUTC(purchase.DeliveryDeadline))
+	}
+	return text + "\n"
+}
+
+func (user *User) getDeliveriesText(account *signumapi.Account) string {
+	purchases, err := user.signumClient.GetSellerCompletedPurchases(user.logger, account.Account, marketplaceDeliveriesLimit)
+	if err != nil {
+		user.logger.Errorf("Can't get completed purchases of %v: %v", account.Account, err)
+		return ""
+	}
+	if len(purchases.Purchases) == 0 {
+		return ""
+	}
+
+	var text = "\n📦 <b>Last deliveries:</b>\n"
+	for _, purchase := range purchases.Purchases {
+		amountNQT := purchase.GetAmountNQT() - purchase.DiscountNQT
+		text += fmt.Sprintf("<i>%v</i>  <b>%v</b> x%v to %v  <i>%v SIGNA</i>",
+			common.FormatChainTimeToStringDatetimeUTC(purchase.Timestamp), html.EscapeString(purchase.Name), purchase.Quantity,
+			purchase.BuyerRS, common.FormatNQT(amountNQT))
+		if purchase.RefundNQT > 0 {
+			text += fmt.Sprintf("  <i>refunded %v SIGNA</i>", common.FormatNQT(purchase.RefundNQT))
+		}
+		text += "\n"
+		if len(purchase.PublicFeedbacks) > 0 {
+			text += "💬 " + html.EscapeString(strings.Join(purchase.PublicFeedbacks, " | ")) + "\n"
+		}
+		if len(purchase.FeedbackNotes) > 0 {
+			text += fmt.Sprintf("💬 <i>%v encrypted feedback</i>\n", len(purchase.FeedbackNotes))
+		}
+	}
+	return text
+}
+
+func (user *User) formatBuyerName(buyer string) string {
+	if name := user.signumClient.GetCachedAccountName(user.logger, buyer); name != "" {
+		return " (" + html.EscapeString(name) + ")"
+	}
+	return ""
+}
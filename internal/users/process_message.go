@@ -1,16 +1,19 @@
 package users
 
 import (
+	"strings"
+
 	"github.com/xDWart/signum-explorer-bot/internal/common"
-	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/rsaddress"
 )
 
 func (user *User) ProcessMessage(message string) *BotMessage {
 	foundAccount, _ := user.tryFoundAccountInMenu(message)
 
 	if (user.state == CALC_TIB_STATE || user.state == CALC_COMMIT_STATE ||
-		user.state == CONVERT_STATE || user.state == CROSSING_STATE) &&
-		(foundAccount != nil || config.ValidAccountRS.MatchString(message)) {
+		user.state == CONVERT_STATE || user.state == CROSSING_STATE ||
+		user.state == BALANCE_FLOOR_STATE || user.state == BALANCE_CHANGE_STATE) &&
+		(foundAccount != nil || rsaddress.IsAddress(message)) {
 		user.ResetState()
 	}
 
@@ -37,16 +40,6 @@ func (user *User) ProcessMessage(message string) *BotMessage {
 	case ADD_STATE:
 		userAccount, msg := user.addAccount(message, "")
 		if userAccount != nil {
-			lastAccountTransaction := user.signumClient.GetLastCachedAccountPaymentTransaction(user.logger, userAccount.Account)
-			if lastAccountTransaction != nil {
-				userAccount.LastTransactionID = lastAccountTransaction.TransactionID
-				userAccount.LastTransactionH = lastAccountTransaction.Height
-			}
-			lastAccountATPaymentTransaction := user.signumClient.GetLastCachedAccountATPaymentTransaction(user.logger, userAccount.Account)
-			if lastAccountATPaymentTransaction != nil {
-				userAccount.LastATPaymentTX = lastAccountATPaymentTransaction.TransactionID
-				userAccount.LastATPaymentH = lastAccountATPaymentTransaction.Height
-			}
 			userAccount.NotifyIncomeTransactions = true
 			user.db.Save(userAccount)
 		}
@@ -74,6 +67,24 @@ func (user *User) ProcessMessage(message string) *BotMessage {
 			return &BotMessage{MainText: err.Error()}
 		}
 		return &BotMessage{MainText: user.setThreshold(amount)}
+	case BALANCE_FLOOR_STATE, BALANCE_CHANGE_STATE:
+		var state = user.state
+		user.ResetState()
+		userAccount := user.GetDbAccount(user.balanceAccount)
+		if userAccount == nil {
+			return &BotMessage{MainText: "🚫 This account not found in the menu"}
+		}
+		amount, err := common.ParseNumber(strings.TrimSuffix(message, "%"))
+		if err != nil {
+			return &BotMessage{MainText: err.Error()}
+		}
+		if amount < 0 {
+			return &BotMessage{MainText: "🚫 The value should not be negative"}
+		}
+		if state == BALANCE_FLOOR_STATE {
+			return &BotMessage{MainText: user.setBalanceFloor(userAccount, amount)}
+		}
+		return &BotMessage{MainText: user.setBalanceChange(userAccount, amount)}
 	default:
 		botMessage, err := user.getAccountInfoMessage(message)
 		if err != nil {
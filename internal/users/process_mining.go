@@ -0,0 +1,107 @@
+package users
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+const miningUsageText = `⛏ <b>Mining alerts</b> notify you about network changes with the recalculated rewards for your plot:
+<b>` + config.COMMAND_MINING + ` plot TiB COMMITMENT</b> - save your plot size and commitment (it's also saved by <b>` + config.COMMAND_CALC + ` TiB COMMITMENT</b>)
+<b>` + config.COMMAND_MINING + ` difficulty PiB</b> - notify when the network difficulty crosses PiB, 0 to disable
+<b>` + config.COMMAND_MINING + ` commitment PERCENT%</b> - notify when the network commitment per TiB changes by PERCENT, 0 to disable`
+
+func (user *User) ProcessMining(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if splittedMessage[0] != config.COMMAND_MINING {
+		return &BotMessage{MainText: miningUsageText}
+	}
+	if len(splittedMessage) == 1 {
+		return &BotMessage{MainText: user.getMiningAlertsText() + "\n\n" + miningUsageText}
+	}
+
+	var values = make([]float64, 0, 2)
+	for _, argument := range splittedMessage[2:] {
+		value, err := common.ParseNumber(strings.TrimSuffix(argument, "%"))
+		if err != nil {
+			return &BotMessage{MainText: err.Error()}
+		}
+		if value < 0 {
+			return &BotMessage{MainText: "🚫 The value should not be negative"}
+		}
+		values = append(values, value)
+	}
+
+	var updates = map[string]interface{}{}
+	switch strings.ToLower(splittedMessage[1]) {
+	case "plot":
+		if len(values) != 2 || values[0] == 0 {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect command format, please send <b>%v plot TiB COMMITMENT</b>", config.COMMAND_MINING)}
+		}
+		updates["plot_size_ti_b"] = values[0]
+		updates["commitment"] = values[1]
+	case "difficulty":
+		if len(values) != 1 {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect command format, please send <b>%v difficulty PiB</b>", config.COMMAND_MINING)}
+		}
+		updates["difficulty_pi_b"] = values[0]
+		updates["last_difficulty_pi_b"] = 0
+	case "commitment":
+		if len(values) != 1 {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect command format, please send <b>%v commitment PERCENT%%</b>", config.COMMAND_MINING)}
+		}
+		updates["commitment_percent"] = values[0]
+		updates["last_commitment_per_ti_b"] = 0
+	default:
+		return &BotMessage{MainText: miningUsageText}
+	}
+
+	user.updateMiningAlert(updates)
+	return &BotMessage{MainText: "✅ Mining alerts have been updated\n\n" + user.getMiningAlertsText()}
+}
+
+func (user *User) getMiningAlert() *models.MiningAlert {
+	var miningAlert models.MiningAlert
+	user.db.Where("db_user_id = ?", user.ID).Limit(1).Find(&miningAlert)
+	return &miningAlert
+}
+
+// updateMiningAlert updates the given columns only, the last observed values are written by the network info listener
+func (user *User) updateMiningAlert(updates map[string]interface{}) {
+	miningAlert := user.getMiningAlert()
+	if miningAlert.ID == 0 {
+		miningAlert.DbUserID = user.ID
+		if err := user.db.Create(miningAlert).Error; err != nil {
+			user.logger.Errorf("Error creating mining alert for user %v: %v", user.ID, err)
+			return
+		}
+	}
+	if err := user.db.Model(miningAlert).Updates(updates).Error; err != nil {
+		user.logger.Errorf("Error saving mining alert for user %v: %v", user.ID, err)
+	}
+}
+
+func (user *User) getMiningAlertsText() string {
+	miningAlert := user.getMiningAlert()
+
+	var text = "⛏ <b>Your mining alerts:</b>"
+	if miningAlert.PlotSizeTiB > 0 {
+		text += fmt.Sprintf("\n<i>Plot:</i> %.2f TiB with %v SIGNA commitment", miningAlert.PlotSizeTiB, common.FormatNumber(miningAlert.Commitment, 0))
+	} else {
+		text += "\n<i>Plot:</i> not saved"
+	}
+	if miningAlert.DifficultyPiB > 0 {
+		text += fmt.Sprintf("\n<i>Difficulty:</i> crossing %v PiB", common.FormatNumber(miningAlert.DifficultyPiB, 2))
+	} else {
+		text += "\n<i>Difficulty:</i> disabled"
+	}
+	if miningAlert.CommitmentPercent > 0 {
+		text += fmt.Sprintf("\n<i>Commitment:</i> change by %v%%", common.FormatNumber(miningAlert.CommitmentPercent, 2))
+	} else {
+		text += "\n<i>Commitment:</i> disabled"
+	}
+	return text
+}
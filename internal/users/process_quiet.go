@@ -0,0 +1,70 @@
+package users
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+)
+
+const quietUsageText = `🔕 <b>Quiet hours</b> hold notifications back, they are delivered as one combined message once quiet hours are over:
+<b>` + config.COMMAND_QUIET + ` FROM-TO [TIMEZONE]</b> - set quiet hours from FROM to TO hour of TIMEZONE (UTC by default), e.g. <b>` + config.COMMAND_QUIET + ` 23-7 Europe/Berlin</b>
+<b>` + config.COMMAND_QUIET + ` off</b> - disable quiet hours`
+
+func (user *User) ProcessQuiet(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if splittedMessage[0] != config.COMMAND_QUIET {
+		return &BotMessage{MainText: quietUsageText}
+	}
+	if len(splittedMessage) == 1 {
+		return &BotMessage{MainText: user.getQuietText() + "\n\n" + quietUsageText}
+	}
+	if len(splittedMessage) > 3 {
+		return &BotMessage{MainText: quietUsageText}
+	}
+
+	if strings.ToLower(splittedMessage[1]) == "off" {
+		user.QuietFrom = 0
+		user.QuietTo = 0
+		user.db.Save(&user.DbUser)
+		return &BotMessage{MainText: "🔔 Quiet hours have been disabled"}
+	}
+
+	hours := strings.Split(splittedMessage[1], "-")
+	if len(hours) != 2 {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect command format, please send <b>%v FROM-TO [TIMEZONE]</b>", config.COMMAND_QUIET)}
+	}
+	from, errFrom := strconv.Atoi(hours[0])
+	to, errTo := strconv.Atoi(hours[1])
+	if errFrom != nil || errTo != nil || from < 0 || from > 23 || to < 0 || to > 23 {
+		return &BotMessage{MainText: "🚫 FROM and TO should be hours in the range 0-23"}
+	}
+	if from == to {
+		return &BotMessage{MainText: "🚫 FROM and TO should be different"}
+	}
+
+	var timezone = "UTC"
+	if len(splittedMessage) == 3 {
+		timezone = splittedMessage[2]
+		if _, err := time.LoadLocation(timezone); err != nil || timezone == "" || timezone == "Local" {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Unknown timezone <b>%v</b>, please use a name like UTC or Europe/Berlin", html.EscapeString(timezone))}
+		}
+	}
+
+	user.QuietFrom = from
+	user.QuietTo = to
+	user.QuietTimezone = timezone
+	user.db.Save(&user.DbUser)
+
+	return &BotMessage{MainText: "✅ " + user.getQuietText()}
+}
+
+func (user *User) getQuietText() string {
+	if user.QuietFrom == user.QuietTo {
+		return "🔔 Quiet hours are disabled"
+	}
+	return fmt.Sprintf("🔕 Quiet hours are from %02d:00 to %02d:00 %v", user.QuietFrom, user.QuietTo, user.QuietTimezone)
+}
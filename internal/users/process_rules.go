@@ -0,0 +1,188 @@
+package users
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/database/models"
+)
+
+var ruleTransactionTypes = map[string]signumapi.TransactionType{
+	"payment":     signumapi.TT_PAYMENT,
+	"message":     signumapi.TT_MESSAGING,
+	"token":       signumapi.TT_TOKENIZATION,
+	"mining":      signumapi.TT_BURST_MINING,
+	"at":          signumapi.TT_AUTOMATED_TRANSACTIONS,
+	"marketplace": signumapi.TT_DIGITAL_GOODS,
+	"leasing":     signumapi.TT_ACCOUNT_CONTROL,
+}
+
+const rulesUsageText = `⚙ <b>Notification rules</b> filter notifications of an account from your menu. Rules of the same kind are combined by OR, different kinds by AND.
+Send <b>` + config.COMMAND_RULES + ` ACCOUNT</b> to show and delete the rules of the account, or add them:
+<b>` + config.COMMAND_RULES + ` ACCOUNT amount MIN [MAX]</b> - notify only about amounts in the range (in SIGNA)
+<b>` + config.COMMAND_RULES + ` ACCOUNT allow SENDER_OR_RECIPIENT</b> - notify only about transactions with these accounts
+<b>` + config.COMMAND_RULES + ` ACCOUNT deny SENDER_OR_RECIPIENT</b> - never notify about transactions with this account
+<b>` + config.COMMAND_RULES + ` ACCOUNT type payment|message|token|mining|at|marketplace|leasing [SUBTYPE]</b> - notify only about these transaction types
+<b>` + config.COMMAND_RULES + ` ACCOUNT contains TEXT</b> - notify only if the message contains the text
+<b>` + config.COMMAND_RULES + ` ACCOUNT clear</b> - delete all rules of the account`
+
+func (user *User) ProcessRules(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if len(splittedMessage) < 2 || splittedMessage[0] != config.COMMAND_RULES {
+		return &BotMessage{MainText: rulesUsageText}
+	}
+
+	userAccount, _ := user.tryFoundAccountInMenu(splittedMessage[1])
+	if userAccount == nil {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 This account not found in the menu, please add it via <b>%v ACCOUNT</b> at first", config.COMMAND_ADD)}
+	}
+
+	if len(splittedMessage) == 2 {
+		return user.getRulesMessage(userAccount)
+	}
+
+	var rule = models.NotificationRule{
+		DbAccountID: userAccount.ID,
+		Kind:        models.RuleKind(strings.ToLower(splittedMessage[2])),
+	}
+	var arguments = splittedMessage[3:]
+
+	switch rule.Kind {
+	case models.RULE_AMOUNT:
+		if len(arguments) < 1 || len(arguments) > 2 {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect command format, please send <b>%v ACCOUNT amount MIN [MAX]</b>", config.COMMAND_RULES)}
+		}
+		minAmount, err := common.ParseNumber(arguments[0])
+		if err != nil {
+			return &BotMessage{MainText: err.Error()}
+		}
+		rule.MinAmountNQT = uint64(minAmount * 1e8)
+		if len(arguments) == 2 {
+			maxAmount, err := common.ParseNumber(arguments[1])
+			if err != nil {
+				return &BotMessage{MainText: err.Error()}
+			}
+			rule.MaxAmountNQT = uint64(maxAmount * 1e8)
+			if rule.MaxAmountNQT < rule.MinAmountNQT {
+				return &BotMessage{MainText: "🚫 MAX amount should be greater than MIN"}
+			}
+		}
+	case models.RULE_ALLOW, models.RULE_DENY:
+		if len(arguments) != 1 {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect command format, please send <b>%v ACCOUNT %v SENDER_OR_RECIPIENT</b>", config.COMMAND_RULES, rule.Kind)}
+		}
+		counterpartyID, err := parseAccount(arguments[0])
+		if err != nil {
+			return &BotMessage{MainText: err.Error()}
+		}
+		counterparty, err := user.signumClient.GetCachedAccount(user.logger, counterpartyID)
+		if err != nil {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Error: %v", err)}
+		}
+		rule.Account = counterparty.Account
+		rule.AccountRS = counterparty.AccountRS
+	case models.RULE_TYPE:
+		if len(arguments) < 1 || len(arguments) > 2 {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect command format, please send <b>%v ACCOUNT type payment|message|token|mining|at|marketplace|leasing [SUBTYPE]</b>", config.COMMAND_RULES)}
+		}
+		transactionType, ok := ruleTransactionTypes[strings.ToLower(arguments[0])]
+		if !ok {
+			return &BotMessage{MainText: "🚫 Unknown transaction type, please use one of: payment, message, token, mining, at, marketplace, leasing"}
+		}
+		rule.TxType = int(transactionType)
+		rule.TxSubtype = -1
+		if len(arguments) == 2 {
+			subtype, err := strconv.ParseUint(arguments[1], 10, 8)
+			if err != nil {
+				return &BotMessage{MainText: "🚫 SUBTYPE should be a number"}
+			}
+			rule.TxSubtype = int(subtype)
+		}
+	case models.RULE_CONTAINS:
+		if len(arguments) == 0 {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect command format, please send <b>%v ACCOUNT contains TEXT</b>", config.COMMAND_RULES)}
+		}
+		rule.Text = strings.Join(arguments, " ")
+		if utf8.RuneCountInString(rule.Text) > models.RULE_TEXT_MAX_LENGTH {
+			return &BotMessage{MainText: fmt.Sprintf("🚫 TEXT should not be longer than %v characters", models.RULE_TEXT_MAX_LENGTH)}
+		}
+	case "clear":
+		user.db.Unscoped().Where("db_account_id = ?", userAccount.ID).Delete(&models.NotificationRule{})
+		answer := user.getRulesMessage(userAccount)
+		answer.MainText = fmt.Sprintf("❎ All rules of <b>%v</b> have been deleted", userAccount.AccountRS)
+		return answer
+	default:
+		return &BotMessage{MainText: rulesUsageText}
+	}
+
+	if err := user.db.Save(&rule).Error; err != nil {
+		user.logger.Errorf("Error saving rule for account %v of user %v: %v", userAccount.Account, user.ID, err)
+		return &BotMessage{MainText: "🚫 Can't save the rule, please try again later"}
+	}
+
+	answer := user.getRulesMessage(userAccount)
+	answer.MainText = fmt.Sprintf("✅ New rule has been added for <b>%v</b>", userAccount.AccountRS)
+	return answer
+}
+
+func (user *User) getRules(userAccount *models.DbAccount) []*models.NotificationRule {
+	var rules []*models.NotificationRule
+	user.db.Where("db_account_id = ?", userAccount.ID).Order("id").Find(&rules)
+	return rules
+}
+
+func (user *User) getRulesMessage(userAccount *models.DbAccount) *BotMessage {
+	rules := user.getRules(userAccount)
+
+	var inlineText = fmt.Sprintf("⚙ <b>%v</b> notification rules:\n\n", userAccount.AccountRS)
+	if len(rules) == 0 {
+		inlineText += "<i>There are no rules, all enabled notifications are sent</i>\n"
+	}
+	for i, rule := range rules {
+		inlineText += fmt.Sprintf("%v. %v\n", i+1, html.EscapeString(formatRule(rule)))
+	}
+	inlineText += fmt.Sprintf("\nSend <b>%v</b> to see how to add a new rule, press a rule button to delete it.", config.COMMAND_RULES)
+
+	return &BotMessage{
+		InlineText:     inlineText,
+		InlineKeyboard: user.GetRulesKeyboard(userAccount.Account, rules),
+	}
+}
+
+func (user *User) deleteRule(userAccount *models.DbAccount, ruleID uint64) {
+	user.db.Unscoped().Where("id = ? AND db_account_id = ?", ruleID, userAccount.ID).Delete(&models.NotificationRule{})
+}
+
+func formatRule(rule *models.NotificationRule) string {
+	switch rule.Kind {
+	case models.RULE_AMOUNT:
+		if rule.MaxAmountNQT == 0 {
+			return fmt.Sprintf("amount ≥ %v SIGNA", common.FormatNQT(rule.MinAmountNQT))
+		}
+		return fmt.Sprintf("amount %v - %v SIGNA", common.FormatNQT(rule.MinAmountNQT), common.FormatNQT(rule.MaxAmountNQT))
+	case models.RULE_ALLOW:
+		return "allow " + rule.AccountRS
+	case models.RULE_DENY:
+		return "deny " + rule.AccountRS
+	case models.RULE_TYPE:
+		var typeName = strconv.Itoa(rule.TxType)
+		for name, transactionType := range ruleTransactionTypes {
+			if int(transactionType) == rule.TxType {
+				typeName = name
+			}
+		}
+		if rule.TxSubtype >= 0 {
+			return fmt.Sprintf("type %v:%v", typeName, rule.TxSubtype)
+		}
+		return "type " + typeName
+	case models.RULE_CONTAINS:
+		return fmt.Sprintf("contains \"%v\"", rule.Text)
+	}
+	return string(rule.Kind)
+}
@@ -0,0 +1,95 @@
+package users
+
+import (
+	"fmt"
+	"html"
+
+	"github.com/xDWart/signum-explorer-bot/api/signumapi"
+	"github.com/xDWart/signum-explorer-bot/internal/common"
+	"github.com/xDWart/signum-explorer-bot/internal/users/callbackdata"
+)
+
+// max tokens listed on the account card, all of them are in the Tokens view
+const cardTokensLimit = 5
+
+type tokenHolding struct {
+	asset       *signumapi.Asset
+	quantityQNT uint64
+}
+
+// getTokenHoldings returns non-zero holdings from first to last (exclusive), the total number of them is returned too
+func (user *User) getTokenHoldings(account *signumapi.Account, first, last int) ([]tokenHolding, int) {
+	var assetBalances []signumapi.AssetBalance
+	for _, assetBalance := range account.AssetBalances {
+		if assetBalance.BalanceQNT > 0 {
+			assetBalances = append(assetBalances, assetBalance)
+		}
+	}
+	if first >= len(assetBalances) {
+		return nil, len(assetBalances)
+	}
+	if last > len(assetBalances) {
+		last = len(assetBalances)
+	}
+
+	var holdings = make([]tokenHolding, 0, last-first)
+	for _, assetBalance := range assetBalances[first:last] {
+		asset, err := user.signumClient.GetCachedAsset(user.logger, assetBalance.Asset)
+		if err != nil {
+			user.logger.Errorf("Can't get asset %v: %v", assetBalance.Asset, err)
+			asset = &signumapi.Asset{Asset: assetBalance.Asset, Name: assetBalance.Asset}
+		}
+		holdings = append(holdings, tokenHolding{asset: asset, quantityQNT: assetBalance.BalanceQNT})
+	}
+	return holdings, len(assetBalances)
+}
+
+func formatTokenQuantity(holding *tokenHolding) string {
+	return common.FormatNumber(holding.asset.GetQuantity(holding.quantityQNT), int(holding.asset.Decimals))
+}
+
+// getAccountTokensText returns the short list of tokens for the account card
+func (user *User) getAccountTokensText(account *signumapi.Account) string {
+	holdings, total := user.getTokenHoldings(account, 0, cardTokensLimit)
+	if total == 0 {
+		return ""
+	}
+
+	var text = "\n\n📇 Tokens:"
+	for i := range holdings {
+		text += fmt.Sprintf("\n%v: %v", html.EscapeString(holdings[i].asset.Name), formatTokenQuantity(&holdings[i]))
+	}
+	if total > len(holdings) {
+		text += fmt.Sprintf("\n<i>and %v more</i>", total-len(holdings))
+	}
+	return text
+}
+
+func (user *User) getTokensMessage(account *signumapi.Account, callbackData *callbackdata.QueryDataType) *BotMessage {
+	var pageSize = int(user.signumClient.GetPageSize())
+	var first = int(callbackData.GetPage()) * pageSize
+	holdings, total := user.getTokenHoldings(account, first, first+pageSize)
+
+	var newInlineText = fmt.Sprintf("📇 <b>%v</b> tokens%v:\n\n", account.AccountRS, getPageTitle(callbackData.GetPage()))
+	if total == 0 {
+		newInlineText += "The account doesn't hold any tokens"
+	}
+	for i := range holdings {
+		asset := holdings[i].asset
+		newInlineText += fmt.Sprintf("<b>%v</b>  %v\n<i>Circulating:</i> %v",
+			html.EscapeString(asset.Name), formatTokenQuantity(&holdings[i]),
+			common.FormatNumber(asset.GetQuantity(asset.GetCirculatingQNT()), int(asset.Decimals)))
+		if asset.GetPriceNQT() > 0 {
+			newInlineText += fmt.Sprintf("  <i>Price:</i> %v SIGNA  <i>Value:</i> ~%v SIGNA",
+				common.FormatNumber(asset.GetPrice(), 8), common.FormatNQT(asset.GetValueNQT(holdings[i].quantityQNT)))
+		} else {
+			newInlineText += "  <i>no trades yet</i>"
+		}
+		newInlineText += "\n\n"
+	}
+
+	return &BotMessage{
+		InlineText:     newInlineText,
+		InlineKeyboard: user.GetHistoryKeyboard(callbackData, first+pageSize < total),
+	}
+}
@@ -0,0 +1,74 @@
+package users
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/xDWart/signum-explorer-bot/internal/config"
+	"github.com/xDWart/signum-explorer-bot/internal/notifier"
+)
+
+const webhookUsageText = `🪝 <b>Webhook</b> receives your notifications as signed JSON POST requests with the event type, account, transaction id, amount, height and the message text:
+<b>` + config.COMMAND_WEBHOOK + ` URL</b> - set the webhook URL, a new secret is generated every time
+<b>` + config.COMMAND_WEBHOOK + ` off</b> - disable the webhook
+The <b>` + notifier.WEBHOOK_SIGNATURE_HEADER + `</b> header contains <i>sha256=HEX</i> HMAC-SHA256 of the body with the secret as the key`
+
+func (user *User) ProcessWebhook(message string) *BotMessage {
+	splittedMessage := strings.Split(message, " ")
+	if splittedMessage[0] != config.COMMAND_WEBHOOK {
+		return &BotMessage{MainText: webhookUsageText}
+	}
+	if len(splittedMessage) == 1 {
+		return &BotMessage{MainText: user.getWebhookText() + "\n\n" + webhookUsageText}
+	}
+	if len(splittedMessage) > 2 {
+		return &BotMessage{MainText: webhookUsageText}
+	}
+
+	if strings.ToLower(splittedMessage[1]) == "off" {
+		user.WebhookURL = ""
+		user.WebhookSecret = ""
+		user.db.Save(&user.DbUser)
+		return &BotMessage{MainText: "🪝 The webhook has been disabled"}
+	}
+
+	webhookURL, err := url.Parse(splittedMessage[1])
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" || len(splittedMessage[1]) > 512 {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 Incorrect URL <b>%v</b>, please send an http(s) URL", html.EscapeString(splittedMessage[1]))}
+	}
+	if err := notifier.CheckWebhookHost(webhookURL.Hostname()); err != nil {
+		return &BotMessage{MainText: fmt.Sprintf("🚫 Webhook host <b>%v</b> is not allowed: %v", html.EscapeString(webhookURL.Hostname()), html.EscapeString(err.Error()))}
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		user.logger.Errorf("Can't generate webhook secret: %v", err)
+		return &BotMessage{MainText: "🚫 Can't generate the webhook secret, please try again later"}
+	}
+
+	user.WebhookURL = webhookURL.String()
+	user.WebhookSecret = secret
+	user.db.Save(&user.DbUser)
+
+	return &BotMessage{MainText: "✅ " + user.getWebhookText()}
+}
+
+func (user *User) getWebhookText() string {
+	if user.WebhookURL == "" {
+		return "🪝 The webhook is disabled"
+	}
+	return fmt.Sprintf("🪝 Notifications are posted to <b>%v</b>\n<i>Secret:</i> <code>%v</code>",
+		html.EscapeString(user.WebhookURL), user.WebhookSecret)
+}
+
+func generateWebhookSecret() (string, error) {
+	var secret = make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
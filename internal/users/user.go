@@ -27,10 +27,12 @@ type User struct {
 
 	state            stateType
 	lastTib          float64
+	balanceAccount   string // the account whose balance alert is being set
 	tbSelected       bool
 	currencySelected currencyType
 	lastCallbackData string
 	lastCallbackTime time.Time
+	exportRunning    bool
 }
 
 type currencyType byte
@@ -51,6 +53,11 @@ type BotMessage struct {
 	InlineKeyboard interface{}
 
 	Chart []byte
+
+	Document     []byte
+	DocumentName string
+
+	Job func(shutdownChannel chan interface{}) *BotMessage // long running work, its answer is sent once it's done
 }
 
 type stateType byte
@@ -65,6 +72,8 @@ const (
 	FAUCET_STATE
 	CONVERT_STATE
 	THRESHOLD_STATE
+	BALANCE_FLOOR_STATE
+	BALANCE_CHANGE_STATE
 )
 
 func (user *User) ResetState() {
@@ -103,12 +112,17 @@ func (user *User) GetMainMenu() *tgbotapi.ReplyKeyboardMarkup {
 		keyboardButtonRows[row] = append(keyboardButtonRows[row], tgbotapi.NewKeyboardButton(accountAlias))
 	}
 
-	keyboardButtonRows = append(keyboardButtonRows, tgbotapi.NewKeyboardButtonRow(
+	var lastRow []tgbotapi.KeyboardButton
+	if buttonsCount > 0 {
+		lastRow = append(lastRow, tgbotapi.NewKeyboardButton(config.BUTTON_PORTFOLIO))
+	}
+	lastRow = append(lastRow,
 		tgbotapi.NewKeyboardButton(config.BUTTON_PRICES),
 		tgbotapi.NewKeyboardButton(config.BUTTON_CALC),
 		tgbotapi.NewKeyboardButton(config.BUTTON_NETWORK),
 		tgbotapi.NewKeyboardButton(config.BUTTON_INFO),
-	))
+	)
+	keyboardButtonRows = append(keyboardButtonRows, lastRow)
 
 	keyboard := tgbotapi.NewReplyKeyboard(keyboardButtonRows...)
 
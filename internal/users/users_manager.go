@@ -79,5 +79,26 @@ func (um *Manager) GetUserByChatIdFromUpdate(update *tgbotapi.Update) *User {
 		um.Unlock()
 	}
 
+	if botUser.Inactive { // the user has unblocked the bot
+		botUser.Lock()
+		botUser.Inactive = false
+		um.db.Model(&models.DbUser{}).Where("chat_id = ?", botUser.ChatID).Update("inactive", false)
+		botUser.Unlock()
+	}
+
 	return botUser
 }
+
+// DeactivateUser is called when the bot has been blocked in the chat, no notifications will be sent until the user comes back
+func (um *Manager) DeactivateUser(chatID int64) {
+	um.RLock()
+	botUser, ok := um.users[chatID]
+	um.RUnlock()
+
+	if ok {
+		botUser.Lock()
+		botUser.Inactive = true
+		botUser.Unlock()
+	}
+	um.db.Model(&models.DbUser{}).Where("chat_id = ?", chatID).Update("inactive", true)
+}
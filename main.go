@@ -31,7 +31,7 @@ func main() {
 
 	bot := internal.InitTelegramBot(logger)
 
-	var gracefulStop = make(chan os.Signal)
+	var gracefulStop = make(chan os.Signal, 1)
 	signal.Notify(gracefulStop, syscall.SIGTERM, syscall.SIGINT)
 
 	go func() {